@@ -191,8 +191,10 @@ func (c *MockKameletBindingsClient) Create(ctx context.Context, binding *camelka
 	return call.Result[0].(*camelkapis.KameletBinding), mock.ErrorOrNil(call.Result[1])
 }
 
+// Update performs a previously recorded action
 func (c *MockKameletBindingsClient) Update(ctx context.Context, binding *camelkapis.KameletBinding, opts v1.UpdateOptions) (*camelkapis.KameletBinding, error) {
 	call := c.recorder.r.VerifyCall("Update")
+	assert.DeepEqual(c.t, call.Result[0].(*camelkapis.KameletBinding), binding)
 	return call.Result[0].(*camelkapis.KameletBinding), mock.ErrorOrNil(call.Result[1])
 }
 
@@ -241,8 +243,15 @@ func (c *MockKameletBindingsClient) Get(ctx context.Context, name string, opts v
 	return call.Result[0].(*camelkapis.KameletBinding), mock.ErrorOrNil(call.Result[1])
 }
 
+// WatchKameletBinding records a call for Watch with the expected result and error (nil if none)
+func (sr *KameletRecorder) WatchKameletBinding(watcher watch.Interface, err error) {
+	sr.r.Add("Watch", nil, []interface{}{watcher, err})
+}
+
+// Watch performs a previously recorded action
 func (c *MockKameletBindingsClient) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
-	panic("implement me")
+	call := c.recorder.r.VerifyCall("Watch")
+	return call.Result[0].(watch.Interface), mock.ErrorOrNil(call.Result[1])
 }
 
 func (c *MockKameletBindingsClient) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *camelkapis.KameletBinding, err error) {
@@ -29,11 +29,42 @@ var bindExample = `
   kn source kamelet bind SOURCE
 
   # Add a binding properties
-  kn source kamelet bind SOURCE --sink|broker|channel|service=<name> --property=<key>=<value>`
+  kn source kamelet bind SOURCE --sink|broker|channel|service=<name> --property=<key>=<value>
+
+  # Bind source to an HTTP endpoint
+  kn source kamelet bind SOURCE --sink=https://example.com/events
+
+  # Bind source to an addressable resource given by its kind or resource name and verify the sink
+  kn source kamelet bind SOURCE --sink=<apiVersion>:<kind>:<name> --verify-sink
+
+  # Bind a broker to a sink Kamelet
+  kn source kamelet bind <sink-kamelet> --source=broker:<name> --property=<key>=<value>
+
+  # Read properties from a file and reference a Secret key instead of passing the value
+  kn source kamelet bind SOURCE --broker=<name> --property-file=props.yaml --property-from-secret=<secret>:<key>
+
+  # Print the binding as YAML without creating it on the cluster
+  kn source kamelet bind SOURCE --broker=<name> --property=<key>=<value> --dry-run=client -o yaml
+
+  # Validate the binding on the cluster without persisting it
+  kn source kamelet bind SOURCE --broker=<name> --property=<key>=<value> --dry-run=server
+
+  # Print the binding for a Kamelet of a local catalog without cluster access
+  kn source kamelet bind SOURCE --broker=<name> --property=<key>=<value> --catalog-dir=./kamelets --dry-run=client
+
+  # Bind a source to a broker and deliver its events to a Knative service with a Trigger
+  kn source kamelet bind SOURCE --broker=<name> --property=<key>=<value> --ce-type=<type> --subscriber=ksvc:<name>
+
+  # Process the events with an action Kamelet between source and sink, requires camel.apache.org/v1 Pipes
+  kn source kamelet bind SOURCE --broker=<name> --property=<key>=<value> --step=<action-kamelet> --step-property=step1.<key>=<value>
+
+  # Select the source Kamelet, its properties and the sink interactively
+  kn source kamelet bind --interactive`
 
 // NewBindCommand implements 'kn-source-kamelet bind' command
 func NewBindCommand(p *KameletPluginParams) *cobra.Command {
-	var properties []string
+	var propertyFlags PropertyFlags
+	var bindingSource string
 	var sink string
 	var broker string
 	var channel string
@@ -41,22 +72,63 @@ func NewBindCommand(p *KameletPluginParams) *cobra.Command {
 	var cloudEventsOverride []string
 	var cloudEventsSpecVersion string
 	var cloudEventsType string
+	var verifySink bool
+	var interactive bool
+	var waitFlags WaitFlags
+	var kameletFileFlags KameletFileFlags
+	var integrationFlags IntegrationFlags
+	var errorHandlerFlags ErrorHandlerFlags
+	var eventTypeFlags EventTypeFlags
+	var stepFlags StepFlags
+	var registerEventType bool
+	var subscriber string
+	var filters []string
+	outputFlags := NewOutputFlags("")
 	cmd := &cobra.Command{
-		Use:     "bind SOURCE",
+		Use:     "bind [SOURCE]",
 		Short:   "Create Kamelet bindings and bind source to Knative broker, channel or service.",
 		Example: bindExample,
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
-			if len(args) != 1 {
+			if len(args) > 1 || (len(args) == 0 && !interactive && !isTerminal(cmd.InOrStdin())) {
 				return errors.New("'kn-source-kamelet bind' requires the Kamelet source as argument")
 			}
-			source := args[0]
+			var source string
+			if len(args) == 1 {
+				source = args[0]
+			}
 
 			namespace, err := p.GetNamespace(cmd)
 			if err != nil {
 				return err
 			}
 
-			client, err := p.NewKameletClient()
+			dryRun, err := outputFlags.dryRunStrategy()
+			if err != nil {
+				return err
+			}
+			printer, err := outputFlags.printer(dryRun)
+			if err != nil {
+				return err
+			}
+
+			// local Kamelets and a client side dry run do not need any cluster access
+			offline := kameletFileFlags.specified() && dryRun == dryRunClient
+			client, err := kameletFileFlags.client(p.NewKameletClient, offline)
+			if err != nil {
+				return err
+			}
+
+			resolver := &sinkResolver{}
+			if offline && verifySink {
+				return errors.New("--verify-sink requires cluster access and can not be used with local Kamelets and --dry-run=client")
+			} else if !offline {
+				resolver, err = p.newSinkResolver(verifySink)
+				if err != nil {
+					return err
+				}
+			}
+
+			properties, err := propertyFlags.resolve(p.Context, p.NewDynamicClient, namespace)
 			if err != nil {
 				return err
 			}
@@ -69,6 +141,7 @@ func NewBindCommand(p *KameletPluginParams) *cobra.Command {
 			options := CreateBindingOptions{
 				Name:                   name,
 				Source:                 source,
+				BindingSource:          bindingSource,
 				SourceProperties:       properties,
 				Sink:                   sink,
 				CloudEventsOverride:    cloudEventsOverride,
@@ -78,10 +151,35 @@ func NewBindCommand(p *KameletPluginParams) *cobra.Command {
 				Channel:                channel,
 				Service:                service,
 				Force:                  true,
+				Wait:                   waitFlags.Wait,
+				WaitTimeout:            waitFlags.TimeoutInSeconds,
+				DryRun:                 dryRun,
+				Printer:                printer,
+				Pipes:                  p.pipesInUse(client, offline),
+				Integration:            &integrationFlags,
+				ErrorHandler:           &errorHandlerFlags,
+				EventTypes:             &eventTypeFlags,
+				Steps:                  &stepFlags,
+				RegisterEventType:      registerEventType,
+				NewEventTypeClient:     p.NewEventingV1beta1Client,
+				Subscriber:             subscriber,
+				Filters:                filters,
+				NewTriggerClient:       p.NewEventingClient,
 				CmdOut:                 cmd.OutOrStdout(),
 			}
 
-			err = createBinding(client, p.Context, namespace, options)
+			if source == "" || interactive {
+				newDynamicClient := p.NewDynamicClient
+				if offline {
+					newDynamicClient = nil
+				}
+				wizard := newBindingWizard(cmd.InOrStdin(), cmd.OutOrStdout())
+				if err := wizard.run(p.Context, client, newDynamicClient, namespace, &options); err != nil {
+					return err
+				}
+			}
+
+			err = createBinding(client, resolver, p.Context, namespace, options)
 			if err != nil {
 				return err
 			}
@@ -93,13 +191,26 @@ func NewBindCommand(p *KameletPluginParams) *cobra.Command {
 	commands.AddNamespaceFlags(flags, false)
 
 	flags.String("name", "", "Binding name.")
-	flags.StringVarP(&sink, "sink", "s", "", "Sink expression to define the binding sink.")
+	flags.StringVarP(&sink, "sink", "s", "", "Sink expression to define the binding sink. Use an http or https URI to send events to an endpoint address.")
 	flags.StringVar(&broker, "broker", "", "Uses a broker as binding sink.")
 	flags.StringVar(&channel, "channel", "", "Uses a channel as binding sink.")
 	flags.StringVar(&service, "service", "", "Uses a Knative service as binding sink.")
-	flags.StringArrayVar(&properties, "property", nil, `Add a source property in the form of "<key>=<value>"`)
+	flags.StringVar(&bindingSource, "source", "", "Source expression to define the binding source when binding a sink Kamelet, e.g. broker:<name> or channel:<name>.")
+	propertyFlags.AddFlags(flags)
+	kameletFileFlags.AddFlags(flags)
 	flags.StringVar(&cloudEventsSpecVersion, "ce-spec", "", "Customize cloud events spec version provided to the binding sink.")
 	flags.StringVar(&cloudEventsType, "ce-type", "", "Customize cloud events type provided to the binding sink.")
 	flags.StringArrayVar(&cloudEventsOverride, "ce-override", nil, `Customize cloud events property in the form of "<key>=<value>"`)
+	flags.BoolVar(&verifySink, "verify-sink", false, "Verify that the sink exists and is addressable before applying the binding.")
+	integrationFlags.AddFlags(flags, false)
+	errorHandlerFlags.AddFlags(flags)
+	eventTypeFlags.AddFlags(flags)
+	stepFlags.AddFlags(flags)
+	flags.StringVar(&subscriber, "subscriber", "", "Create a Trigger on the broker sink delivering the events of the binding to the given subscriber, e.g. ksvc:<name>. The Trigger is deleted along with the binding.")
+	flags.StringArrayVar(&filters, "filter", nil, `Filter the events of the Trigger created with --subscriber in the form of "<attribute>=<value>", defaults to the cloud events type of the binding.`)
+	flags.BoolVar(&registerEventType, "register-event-type", false, "Register a Knative EventType for the events the binding sends to its broker sink, it is deleted along with the binding.")
+	flags.BoolVar(&interactive, "interactive", false, "Prompt for the source Kamelet, its properties and the binding sink. Also used when no source is given on a terminal.")
+	waitFlags.AddFlags(flags)
+	outputFlags.AddFlags(cmd)
 	return cmd
 }
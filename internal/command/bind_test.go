@@ -19,18 +19,22 @@ package command
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
 	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
 	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
 	"knative.dev/client-pkg/pkg/commands"
+	clienteventingv1 "knative.dev/client-pkg/pkg/eventing/v1"
+	"knative.dev/client-pkg/pkg/util"
 	"knative.dev/kn-plugin-source-kamelet/internal/client"
 
 	"gotest.tools/v3/assert"
@@ -42,7 +46,7 @@ func TestBindSetup(t *testing.T) {
 	}
 
 	bindCmd := NewBindCommand(&p)
-	assert.Equal(t, bindCmd.Use, "bind SOURCE")
+	assert.Equal(t, bindCmd.Use, "bind [SOURCE]")
 	assert.Equal(t, bindCmd.Short, "Create Kamelet bindings and bind source to Knative broker, channel or service.")
 	assert.Assert(t, bindCmd.RunE != nil)
 }
@@ -74,12 +78,12 @@ func TestBindErrorCaseNoEventSource(t *testing.T) {
 
 	kamelet := createKamelet("k1")
 	kamelet.Labels = map[string]string{
-		KameletTypeLabel: "sink",
+		KameletTypeLabel: "action",
 	}
 	recorder.Get(kamelet, nil)
 
 	err := runBindCmd(mockClient, "k1", "--channel", "test")
-	assert.Error(t, err, "kamelet k1 is not an event source")
+	assert.Error(t, err, "kamelet k1 is not an event source or sink")
 	recorder.Validate()
 }
 
@@ -171,7 +175,7 @@ func TestBindToChannel(t *testing.T) {
 			},
 		},
 	}, nil)
-	err := runBindCmd(mockClient, "k1", "--channel", "test", "--property", "k1_prop=foo")
+	err := runBindCmd(mockClient, "k1", "--channel", "test", "--property", "k1_prop=foo", "--no-wait")
 	assert.NilError(t, err)
 
 	recorder.Validate()
@@ -193,7 +197,7 @@ func TestBindToBroker(t *testing.T) {
 		Spec: v1alpha1.KameletBindingSpec{
 			Source: v1alpha1.Endpoint{
 				Properties: &v1alpha1.EndpointProperties{
-					RawMessage: []byte("{\"k2_optional\":\"bar\",\"k2_prop\":\"foo\"}"),
+					RawMessage: []byte("{\"k2_optional\":true,\"k2_prop\":\"foo\"}"),
 				},
 				Ref: &corev1.ObjectReference{
 					Kind:       v1alpha1.KameletKind,
@@ -213,7 +217,7 @@ func TestBindToBroker(t *testing.T) {
 			},
 		},
 	}, nil)
-	err := runBindCmd(mockClient, "k2", "--broker", "test", "--property", "k2_prop=foo", "--property", "k2_optional=bar")
+	err := runBindCmd(mockClient, "k2", "--broker", "test", "--property", "k2_prop=foo", "--property", "k2_optional=true", "--no-wait")
 	assert.NilError(t, err)
 
 	recorder.Validate()
@@ -255,7 +259,7 @@ func TestBindToService(t *testing.T) {
 			},
 		},
 	}, nil)
-	err := runBindCmd(mockClient, "k3", "--service", "test", "--property", "k3_prop=foo")
+	err := runBindCmd(mockClient, "k3", "--service", "test", "--property", "k3_prop=foo", "--no-wait")
 	assert.NilError(t, err)
 
 	recorder.Validate()
@@ -304,7 +308,7 @@ func TestBindAutoUpdate(t *testing.T) {
 
 	recorder.UpdateKameletBinding(binding, nil)
 
-	err := runBindCmd(mockClient, "k1", "--channel", "test", "--property", "k1_prop=foo")
+	err := runBindCmd(mockClient, "k1", "--channel", "test", "--property", "k1_prop=foo", "--no-wait")
 	assert.NilError(t, err)
 
 	recorder.Validate()
@@ -348,13 +352,324 @@ func TestBindWithCustomCloudEventSettings(t *testing.T) {
 			},
 		},
 	}, nil)
-	err := runBindCmd(mockClient, "k4", "--channel", "test", "--name", "k4-ce-settings-test", "--property", "k4_prop=foo", "--ce-spec", "1.0.1", "--ce-type", "custom-type", "--ce-override", "subject=custom")
+	err := runBindCmd(mockClient, "k4", "--channel", "test", "--name", "k4-ce-settings-test", "--property", "k4_prop=foo", "--ce-spec", "1.0.1", "--ce-type", "custom-type", "--ce-override", "subject=custom", "--no-wait")
 	assert.NilError(t, err)
 
 	recorder.Validate()
 }
 
+func TestBindToURI(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	kamelet := createKameletInNamespace("k1", namespace)
+	recorder.Get(kamelet, nil)
+
+	uri := "http://receiver.example.com:8080/"
+	binding := createKameletBindingInNamespace("k1-to-uri-receiver-example-com", "k1", namespace, nil)
+	binding.Spec.Sink.URI = &uri
+
+	recorder.CreateKameletBinding(binding, nil)
+	err := runBindCmd(mockClient, "k1", "--sink", uri, "--property", "k1_prop=foo", "--no-wait")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+}
+
+func TestBindChannelToSinkKamelet(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	recorder.Get(createSinkKameletInNamespace("log-sink", namespace), nil)
+
+	recorder.CreateKameletBinding(&v1alpha1.KameletBinding{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: namespace,
+			Name:      "channel-test-to-log-sink",
+		},
+		Spec: v1alpha1.KameletBindingSpec{
+			Source: v1alpha1.Endpoint{
+				Properties: &v1alpha1.EndpointProperties{},
+				Ref: &corev1.ObjectReference{
+					Kind:       "Channel",
+					APIVersion: messagingv1.SchemeGroupVersion.String(),
+					Namespace:  namespace,
+					Name:       "test",
+				},
+			},
+			Sink: v1alpha1.Endpoint{
+				Properties: &v1alpha1.EndpointProperties{
+					RawMessage: []byte("{\"log-sink_prop\":\"foo\"}"),
+				},
+				Ref: &corev1.ObjectReference{
+					Kind:       v1alpha1.KameletKind,
+					APIVersion: v1alpha1.SchemeGroupVersion.String(),
+					Namespace:  namespace,
+					Name:       "log-sink",
+				},
+			},
+		},
+	}, nil)
+	err := runBindCmd(mockClient, "log-sink", "--source", "channel:test", "--property", "log-sink_prop=foo", "--no-wait")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+}
+
+func TestBindErrorCaseURISource(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createSinkKameletInNamespace("log-sink", "current"), nil)
+
+	err := runBindCmd(mockClient, "log-sink", "--source", "https://example.com", "--property", "log-sink_prop=foo")
+	assert.Error(t, err, "unsupported binding source \"https://example.com\" - URIs can only be used as binding sink")
+	recorder.Validate()
+}
+
+func TestBindWithPropertyFromSecret(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+
+	binding := createKameletBindingInNamespace("k1-to-channel-test", "k1", namespace, channelRef(namespace))
+	binding.Spec.Source.Properties.RawMessage = []byte("{\"k1_prop\":\"{{secret:credentials/k1_prop}}\"}")
+	recorder.CreateKameletBinding(binding, nil)
+
+	err := runBindCmd(mockClient, "k1", "--channel", "test", "--property-from-secret", "credentials:k1_prop", "--no-wait")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+}
+
+func TestBindErrorCaseUnknownPropertyFromConfigMap(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKameletInNamespace("k1", "current"), nil)
+
+	err := runBindCmd(mockClient, "k1", "--channel", "test", "--property", "k1_prop=foo", "--property-from-configmap", "settings:level")
+	assert.Error(t, err, "binding uses unknown property \"level\" for Kamelet \"k1\"")
+
+	recorder.Validate()
+}
+
+func TestBindDryRunClient(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKameletInNamespace("k1", "current"), nil)
+
+	err := runBindCmd(mockClient, "k1", "--channel", "test", "--property", "k1_prop=foo", "--dry-run=client", "-o", "yaml")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+}
+
+func TestBindToBrokerWithSubscriber(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+
+	binding := createKameletBindingInNamespace("k1-to-broker", "k1", namespace, brokerRef(namespace))
+	binding.Labels = map[string]string{bindingLabel: "k1-to-broker"}
+	binding.Spec.Sink.Properties.RawMessage = []byte("{\"cloudEventsType\":\"org.example.order\"}")
+	recorder.CreateKameletBinding(binding, nil)
+
+	triggerClient := newFakeTriggerClient()
+	output, err := runBindCmdWithTriggers(mockClient, triggerClient, nil, "k1", "--name", "k1-to-broker", "--broker", "default",
+		"--property", "k1_prop=foo", "--ce-type", "org.example.order", "--subscriber", "ksvc:foo", "--no-wait")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(output, "kamelet binding \"k1-to-broker\" created", "trigger \"k1-to-broker\" created"))
+
+	trigger, ok := triggerClient.triggers["k1-to-broker"]
+	assert.Assert(t, ok)
+	assert.Equal(t, trigger.Namespace, namespace)
+	assert.Equal(t, trigger.Labels[bindingLabel], "k1-to-broker")
+	assert.Equal(t, trigger.Spec.Broker, "default")
+	assert.DeepEqual(t, trigger.Spec.Filter.Attributes, eventingv1.TriggerFilterAttributes{"type": "org.example.order"})
+	assert.Equal(t, trigger.Spec.Subscriber.Ref.Kind, "Service")
+	assert.Equal(t, trigger.Spec.Subscriber.Ref.APIVersion, servingv1.SchemeGroupVersion.String())
+	assert.Equal(t, trigger.Spec.Subscriber.Ref.Name, "foo")
+
+	recorder.Validate()
+}
+
+func TestBindToBrokerWithSubscriberAndFilters(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+
+	binding := createKameletBindingInNamespace("k1-to-broker", "k1", namespace, brokerRef(namespace))
+	binding.Labels = map[string]string{bindingLabel: "k1-to-broker"}
+	recorder.CreateKameletBinding(binding, nil)
+
+	triggerClient := newFakeTriggerClient()
+	_, err := runBindCmdWithTriggers(mockClient, triggerClient, nil, "k1", "--name", "k1-to-broker", "--broker", "default",
+		"--property", "k1_prop=foo", "--subscriber", "https://example.com/orders", "--filter", "source=/orders", "--filter", "subject=new", "--no-wait")
+	assert.NilError(t, err)
+
+	trigger := triggerClient.triggers["k1-to-broker"]
+	assert.DeepEqual(t, trigger.Spec.Filter.Attributes, eventingv1.TriggerFilterAttributes{"source": "/orders", "subject": "new"})
+	assert.Equal(t, trigger.Spec.Subscriber.URI.String(), "https://example.com/orders")
+
+	recorder.Validate()
+}
+
+func TestBindErrorCaseSubscriberBrokerNotFound(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKameletInNamespace("k1", "current"), nil)
+
+	triggerClient := newFakeTriggerClient()
+	_, err := runBindCmdWithTriggers(mockClient, triggerClient, []runtime.Object{}, "k1", "--name", "k1-to-broker", "--broker", "default",
+		"--property", "k1_prop=foo", "--ce-type", "org.example.order", "--subscriber", "https://example.com/orders", "--no-wait")
+	assert.ErrorContains(t, err, "\"default\" not found")
+	assert.Equal(t, len(triggerClient.triggers), 0)
+
+	recorder.Validate()
+}
+
+func TestBindErrorCaseSubscriberForeignTrigger(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKameletInNamespace("k1", "current"), nil)
+
+	foreign := &eventingv1.Trigger{ObjectMeta: v1.ObjectMeta{Name: "k1-to-broker", Namespace: "current", Labels: map[string]string{bindingLabel: "other"}}}
+	_, err := runBindCmdWithTriggers(mockClient, newFakeTriggerClient(foreign), []runtime.Object{readyBroker("current")}, "k1", "--name", "k1-to-broker",
+		"--broker", "default", "--property", "k1_prop=foo", "--ce-type", "org.example.order", "--subscriber", "https://example.com/orders", "--no-wait")
+	assert.Error(t, err, "trigger \"k1-to-broker\" already exists and does not belong to kamelet binding \"k1-to-broker\"")
+
+	recorder.Validate()
+}
+
+func TestBindSubscriberRollback(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+	binding := createKameletBindingInNamespace("k1-to-broker", "k1", namespace, brokerRef(namespace))
+	binding.Labels = map[string]string{bindingLabel: "k1-to-broker"}
+	binding.Spec.Sink.Properties.RawMessage = []byte("{\"cloudEventsType\":\"org.example.order\"}")
+	recorder.CreateKameletBinding(binding, nil)
+	recorder.DeleteKameletBinding("k1-to-broker", nil)
+
+	triggerClient := newFakeTriggerClient()
+	triggerClient.createErr = errors.New("admission webhook denied the trigger")
+	output, err := runBindCmdWithTriggers(mockClient, triggerClient, []runtime.Object{readyBroker(namespace)}, "k1", "--name", "k1-to-broker",
+		"--broker", "default", "--property", "k1_prop=foo", "--ce-type", "org.example.order", "--subscriber", "https://example.com/orders", "--no-wait")
+	assert.Error(t, err, "admission webhook denied the trigger - the kamelet binding \"k1-to-broker\" has been deleted again")
+	assert.Assert(t, util.ContainsAll(output, "kamelet binding \"k1-to-broker\" created", "kamelet binding \"k1-to-broker\" deleted"))
+
+	recorder.Validate()
+}
+
+func TestBindReplacesBindingWithSubscriber(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+	binding := createKameletBindingInNamespace("k1-to-broker", "k1", namespace, brokerRef(namespace))
+	recorder.CreateKameletBinding(binding, k8serrors.NewAlreadyExists(v1alpha1.Resource("kameletbindings"), "k1-to-broker"))
+	existing := binding.DeepCopy()
+	existing.Labels = map[string]string{bindingLabel: "k1-to-broker", "team": "orders"}
+	recorder.GetKameletBinding(existing, nil)
+	// the user label is kept, the binding label of the Trigger is gone
+	updated := binding.DeepCopy()
+	updated.Labels = map[string]string{"team": "orders"}
+	recorder.UpdateKameletBinding(updated, nil)
+
+	trigger := &eventingv1.Trigger{ObjectMeta: v1.ObjectMeta{Name: "k1-to-broker", Namespace: namespace, Labels: map[string]string{bindingLabel: "k1-to-broker"}}}
+	triggerClient := newFakeTriggerClient(trigger)
+	output, err := runBindCmdWithTriggers(mockClient, triggerClient, nil, "k1", "--name", "k1-to-broker", "--broker", "default",
+		"--property", "k1_prop=foo", "--no-wait")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kamelet binding \"k1-to-broker\" updated", "trigger \"k1-to-broker\" deleted"))
+	assert.Equal(t, len(triggerClient.triggers), 0)
+
+	recorder.Validate()
+}
+
+func TestBindErrorCaseSubscriberWithoutBroker(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKameletInNamespace("k1", "current"), nil)
+
+	err := runBindCmd(mockClient, "k1", "--channel", "test", "--property", "k1_prop=foo", "--ce-type", "org.example.order", "--subscriber", "ksvc:foo")
+	assert.Error(t, err, "--subscriber requires a broker as binding sink")
+
+	recorder.Validate()
+}
+
+func TestBindErrorCaseSubscriberWithoutFilter(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKameletInNamespace("k1", "current"), nil)
+
+	err := runBindCmd(mockClient, "k1", "--broker", "default", "--property", "k1_prop=foo", "--subscriber", "ksvc:foo")
+	assert.Error(t, err, "--subscriber requires --ce-type or --filter to select the events of the binding")
+
+	recorder.Validate()
+}
+
+func TestBindErrorCaseFilterWithoutSubscriber(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKameletInNamespace("k1", "current"), nil)
+
+	err := runBindCmd(mockClient, "k1", "--broker", "default", "--property", "k1_prop=foo", "--filter", "type=org.example.order")
+	assert.Error(t, err, "--filter requires --subscriber")
+
+	recorder.Validate()
+}
+
 func runBindCmd(c *client.MockClient, options ...string) error {
+	return runBindCmdWithInput(c, nil, "", options...)
+}
+
+func runBindCmdWithTriggers(c *client.MockClient, triggerClient *fakeTriggerClient, objects []runtime.Object, options ...string) (string, error) {
+	p := KameletPluginParams{
+		KnParams: &commands.KnParams{
+			NewEventingClient: func(namespace string) (clienteventingv1.KnEventingClient, error) {
+				return triggerClient, nil
+			},
+		},
+		Context: context.TODO(),
+		NewKameletClient: func() (camelkv1alpha1.CamelV1alpha1Interface, error) {
+			return c, nil
+		},
+	}
+	// the broker and subscriber are verified only with a dynamic client
+	if objects != nil {
+		p.NewDynamicClient = newFakeDynamicClient(objects...)
+	}
+
+	bindCmd, _, output := commands.CreateSourcesTestKnCommand(NewBindCommand(&p), p.KnParams)
+
+	args := []string{"bind"}
+	args = append(args, options...)
+	bindCmd.SetArgs(args)
+	err := bindCmd.Execute()
+
+	return output.String(), err
+}
+
+func runBindCmdWithInput(c *client.MockClient, objects []runtime.Object, input string, options ...string) error {
 	p := KameletPluginParams{
 		KnParams: &commands.KnParams{},
 		Context:  context.TODO(),
@@ -362,8 +677,10 @@ func runBindCmd(c *client.MockClient, options ...string) error {
 			return c, nil
 		},
 	}
+	p.NewDynamicClient = newFakeDynamicClient(objects...)
 
 	bindCmd, _, _ := commands.CreateSourcesTestKnCommand(NewBindCommand(&p), p.KnParams)
+	bindCmd.SetIn(strings.NewReader(input))
 
 	args := []string{"bind"}
 	args = append(args, options...)
@@ -22,7 +22,7 @@ import (
 
 var bindingExample = `
   # Configure and manage a Kamelet binding.
-  kn-source-kamelet binding create|update|delete`
+  kn-source-kamelet binding create|update|apply|delete`
 
 // NewBindingCommand implements 'kn-source-kamelet binding' command
 func NewBindingCommand(p *KameletPluginParams) *cobra.Command {
@@ -33,7 +33,17 @@ func NewBindingCommand(p *KameletPluginParams) *cobra.Command {
 	}
 
 	cmd.AddCommand(newBindingCreateCommand(p))
+	cmd.AddCommand(newBindingUpdateCommand(p))
+	cmd.AddCommand(newBindingApplyCommand(p))
+	cmd.AddCommand(newBindingExportCommand(p))
+	cmd.AddCommand(newBindingMigrateCommand(p))
 	cmd.AddCommand(newBindingDeleteCommand(p))
+	cmd.AddCommand(newBindingPauseCommand(p))
+	cmd.AddCommand(newBindingResumeCommand(p))
+	cmd.AddCommand(newBindingScaleCommand(p))
 	cmd.AddCommand(newBindingListCommand(p))
+	cmd.AddCommand(newBindingLogsCommand(p))
+	cmd.AddCommand(newBindingDescribeCommand(p))
+	cmd.AddCommand(newBindingCheckCommand(p))
 	return cmd
 }
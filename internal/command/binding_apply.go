@@ -0,0 +1,503 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	"github.com/spf13/cobra"
+	yamlv3 "gopkg.in/yaml.v3"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"knative.dev/client-pkg/pkg/commands"
+	knerrors "knative.dev/client-pkg/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+var bindingApplyExample = `
+  # Create or update the Kamelet bindings of a manifest file
+  kn source kamelet binding apply -f bindings.yaml
+
+  # Apply all manifests of a directory and delete labeled bindings that are no longer part of the manifests
+  kn source kamelet binding apply -f ./bindings --prune -l team=integration
+
+  # Apply a binding in the compact format from standard input
+  cat <<EOF | kn source kamelet binding apply -f -
+  name: timer-to-broker
+  kamelet: timer-source
+  properties:
+    message: Hello
+  sink: broker:default
+  EOF`
+
+// BindingManifest is the compact format of a binding that resembles the options of the 'binding create' command
+type BindingManifest struct {
+	// Name of the binding, generated from source and sink if not set
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Kamelet used as binding source or sink
+	Kamelet string `json:"kamelet" yaml:"kamelet"`
+	// Properties of the Kamelet as written in the manifest
+	Properties PropertyValues `json:"properties,omitempty" yaml:"properties,omitempty"`
+	// Sink expression for a source Kamelet
+	Sink string `json:"sink,omitempty" yaml:"sink,omitempty"`
+	// Source expression for a sink Kamelet
+	Source string `json:"source,omitempty" yaml:"source,omitempty"`
+	// Labels added to the binding
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+// newBindingApplyCommand implements 'kn-source-kamelet binding apply' command
+func newBindingApplyCommand(p *KameletPluginParams) *cobra.Command {
+	var filenames []string
+	var prune bool
+	var selector string
+
+	cmd := &cobra.Command{
+		Use:     "apply -f FILE|DIR|-",
+		Short:   "Create or update Kamelet bindings from manifest files.",
+		Example: bindingApplyExample,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) != 0 {
+				return errors.New("'kn-source-kamelet binding apply' does not accept arguments - please use --filename to specify the manifests")
+			}
+			if len(filenames) == 0 {
+				return errors.New("'kn-source-kamelet binding apply' requires the manifests given with --filename")
+			}
+			if prune && selector == "" {
+				return errors.New("--prune requires a label selector given with --selector")
+			}
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := p.NewKameletClient()
+			if err != nil {
+				return err
+			}
+
+			resolver, err := p.newSinkResolver(false)
+			if err != nil {
+				return err
+			}
+
+			docs, err := readBindingManifests(filenames, cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+
+			bindings, err := buildManifestBindings(client, resolver, p.Context, namespace, docs)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			for _, binding := range bindings {
+				status, err := applyBinding(client, p.Context, binding)
+				if err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintf(out, "kamelet binding %q %s\n", binding.Name, status)
+			}
+
+			if prune {
+				return pruneBindings(client, p.Context, namespace, selector, bindings, out)
+			}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	commands.AddNamespaceFlags(flags, false)
+	flags.StringArrayVarP(&filenames, "filename", "f", nil, `Manifest file or directory holding the bindings, use "-" to read from standard input.`)
+	flags.BoolVar(&prune, "prune", false, "Delete bindings matching the label selector that are not part of the manifests.")
+	flags.StringVarP(&selector, "selector", "l", "", "Label selector of the bindings to prune, e.g. team=integration.")
+	return cmd
+}
+
+// bindingDocument is a single YAML document of a manifest file
+type bindingDocument struct {
+	source string
+	data   []byte
+}
+
+// readBindingManifests reads all YAML documents of the given files and directories. A "-" reads from the given input.
+func readBindingManifests(filenames []string, in io.Reader) ([]bindingDocument, error) {
+	var docs []bindingDocument
+	for _, filename := range filenames {
+		var files []string
+		if filename == "-" {
+			data, err := io.ReadAll(in)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read manifests from standard input: %v", err)
+			}
+			docs = append(docs, splitBindingDocuments("<stdin>", data)...)
+			continue
+		}
+
+		info, err := os.Stat(filename)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read manifests: %v", err)
+		}
+		if info.IsDir() {
+			entries, err := os.ReadDir(filename)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read manifests: %v", err)
+			}
+			for _, entry := range entries {
+				ext := strings.ToLower(filepath.Ext(entry.Name()))
+				if !entry.IsDir() && (ext == ".yaml" || ext == ".yml" || ext == ".json") {
+					files = append(files, filepath.Join(filename, entry.Name()))
+				}
+			}
+		} else {
+			files = append(files, filename)
+		}
+
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read manifests: %v", err)
+			}
+			docs = append(docs, splitBindingDocuments(file, data)...)
+		}
+	}
+	return docs, nil
+}
+
+func splitBindingDocuments(source string, data []byte) []bindingDocument {
+	var docs []bindingDocument
+	for _, doc := range yamlDocumentSeparator.Split(string(data), -1) {
+		if strings.TrimSpace(doc) != "" {
+			docs = append(docs, bindingDocument{source: source, data: []byte(doc)})
+		}
+	}
+	return docs
+}
+
+// buildManifestBindings converts the documents to bindings and validates the Kamelet properties of all bindings
+// before any binding gets applied. All validation errors are reported at once.
+func buildManifestBindings(client camelkv1alpha1.CamelV1alpha1Interface, resolver *sinkResolver, ctx context.Context, namespace string,
+	docs []bindingDocument) ([]*v1alpha1.KameletBinding, error) {
+	var bindings []*v1alpha1.KameletBinding
+	var errs []error
+	names := make(map[string]string)
+
+	add := func(source string, binding *v1alpha1.KameletBinding, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source, err))
+			return
+		}
+		key := binding.Namespace + "/" + binding.Name
+		if previous, ok := names[key]; ok {
+			errs = append(errs, fmt.Errorf("%s: kamelet binding %q is already defined in %s", source, binding.Name, previous))
+			return
+		}
+		names[key] = source
+		bindings = append(bindings, binding)
+	}
+
+	for _, doc := range docs {
+		content := make(map[string]interface{})
+		if err := yaml.Unmarshal(doc.data, &content); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", doc.source, err))
+			continue
+		}
+
+		switch {
+		case content["kind"] == v1alpha1.KameletBindingKind || content["kind"] == pipeKind:
+			binding, err := readManifestBinding(client, ctx, namespace, doc.data)
+			add(doc.source, binding, err)
+		case content["bindings"] != nil:
+			var list struct {
+				Bindings []BindingManifest `yaml:"bindings"`
+			}
+			if err := decodeCompactManifest(doc.data, &list); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %v", doc.source, err))
+				continue
+			}
+			for _, manifest := range list.Bindings {
+				binding, err := manifest.toBinding(client, resolver, ctx, namespace)
+				add(doc.source, binding, err)
+			}
+		case content["kamelet"] != nil:
+			var manifest BindingManifest
+			if err := decodeCompactManifest(doc.data, &manifest); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %v", doc.source, err))
+				continue
+			}
+			binding, err := manifest.toBinding(client, resolver, ctx, namespace)
+			add(doc.source, binding, err)
+		default:
+			errs = append(errs, fmt.Errorf("%s: unsupported document - please use a KameletBinding, a Pipe or the compact binding format", doc.source))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return bindings, nil
+}
+
+// readManifestBinding reads a KameletBinding or a Pipe and validates the properties of its Kamelet endpoints
+func readManifestBinding(client camelkv1alpha1.CamelV1alpha1Interface, ctx context.Context, namespace string, data []byte) (*v1alpha1.KameletBinding, error) {
+	binding, err := decodeManifestBinding(data)
+	if err != nil {
+		return nil, err
+	}
+	if binding.Name == "" {
+		return nil, errors.New("kamelet binding is missing a name")
+	}
+	if binding.Namespace == "" {
+		binding.Namespace = namespace
+	}
+
+	steps, err := bindingSteps(binding)
+	if err != nil {
+		return nil, err
+	}
+	// source and sink are followed by the steps
+	endpoints := []*v1alpha1.Endpoint{&binding.Spec.Source, &binding.Spec.Sink}
+	for i := range steps {
+		endpoints = append(endpoints, &steps[i])
+	}
+	for i, endpoint := range endpoints {
+		if !isKameletEndpoint(*endpoint) {
+			continue
+		}
+		kameletNamespace := endpoint.Ref.Namespace
+		if kameletNamespace == "" {
+			kameletNamespace = binding.Namespace
+		}
+		kamelet, err := client.Kamelets(kameletNamespace).Get(ctx, endpoint.Ref.Name, v1.GetOptions{})
+		if err != nil {
+			return nil, knerrors.GetError(err)
+		}
+		if i >= 2 && !isActionType(kamelet) {
+			return nil, fmt.Errorf("kamelet %s is not an action - only action Kamelets can be used as step", kamelet.Name)
+		}
+		if err := coerceEndpointProperties(kamelet, endpoint.Properties); err != nil {
+			return nil, err
+		}
+		if err := verifyProperties(kamelet, *endpoint); err != nil {
+			return nil, err
+		}
+	}
+	if err := setBindingSteps(binding, steps); err != nil {
+		return nil, err
+	}
+	return binding, nil
+}
+
+// decodeManifestBinding decodes the KameletBinding or converts the Pipe into the binding served by the clients
+func decodeManifestBinding(data []byte) (*v1alpha1.KameletBinding, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(jsonData); err != nil {
+		return nil, err
+	}
+	return bindingFromCamelV1(obj, nil)
+}
+
+// decodeCompactManifest decodes a compact binding manifest, unknown fields are rejected. The manifest is decoded
+// as YAML node tree, so the property values are taken as written.
+func decodeCompactManifest(data []byte, into interface{}) error {
+	decoder := yamlv3.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	return decoder.Decode(into)
+}
+
+// toBinding creates the binding described by the compact manifest
+func (m BindingManifest) toBinding(client camelkv1alpha1.CamelV1alpha1Interface, resolver *sinkResolver, ctx context.Context, namespace string) (*v1alpha1.KameletBinding, error) {
+	if m.Kamelet == "" {
+		return nil, errors.New("binding is missing the kamelet")
+	}
+
+	properties := make([]string, 0, len(m.Properties))
+	for _, key := range sortedKeys(m.Properties) {
+		properties = append(properties, key+"="+m.Properties[key])
+	}
+
+	binding, err := buildBinding(client, resolver, ctx, namespace, CreateBindingOptions{
+		Name:             m.Name,
+		Source:           m.Kamelet,
+		BindingSource:    m.Source,
+		SourceProperties: properties,
+		Sink:             m.Sink,
+	})
+	if err != nil {
+		return nil, err
+	}
+	binding.Labels = m.Labels
+	return binding, nil
+}
+
+// applyBinding creates the binding or updates an existing binding when it differs from the given one.
+// The result is one of created, updated or unchanged.
+func applyBinding(client camelkv1alpha1.CamelV1alpha1Interface, ctx context.Context, binding *v1alpha1.KameletBinding) (string, error) {
+	existing, err := client.KameletBindings(binding.Namespace).Get(ctx, binding.Name, v1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		if _, err := client.KameletBindings(binding.Namespace).Create(ctx, binding, v1.CreateOptions{}); err != nil {
+			return "", knerrors.GetError(err)
+		}
+		return "created", nil
+	}
+	if err != nil {
+		return "", knerrors.GetError(err)
+	}
+
+	if bindingUpToDate(existing, binding) {
+		return "unchanged", nil
+	}
+
+	updated := existing.DeepCopy()
+	updated.Spec = binding.Spec
+	updated.Labels = mergeStringMaps(updated.Labels, binding.Labels)
+	updated.Annotations = mergeStringMaps(updated.Annotations, binding.Annotations)
+	if _, err := client.KameletBindings(binding.Namespace).Update(ctx, updated, v1.UpdateOptions{}); err != nil {
+		return "", knerrors.GetError(err)
+	}
+	return "updated", nil
+}
+
+// pruneBindings deletes the bindings matching the selector that are not part of the applied bindings
+func pruneBindings(client camelkv1alpha1.CamelV1alpha1Interface, ctx context.Context, namespace string, selector string,
+	applied []*v1alpha1.KameletBinding, out io.Writer) error {
+	keep := make(map[string]bool)
+	for _, binding := range applied {
+		keep[binding.Namespace+"/"+binding.Name] = true
+	}
+
+	list, err := client.KameletBindings(namespace).List(ctx, v1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return knerrors.GetError(err)
+	}
+	for _, binding := range list.Items {
+		bindingNamespace := binding.Namespace
+		if bindingNamespace == "" {
+			bindingNamespace = namespace
+		}
+		if keep[bindingNamespace+"/"+binding.Name] {
+			continue
+		}
+		if err := client.KameletBindings(bindingNamespace).Delete(ctx, binding.Name, v1.DeleteOptions{}); err != nil {
+			return knerrors.GetError(err)
+		}
+		_, _ = fmt.Fprintf(out, "kamelet binding %q pruned\n", binding.Name)
+	}
+	return nil
+}
+
+// bindingUpToDate returns true when the existing binding has the spec, labels and annotations of the desired binding
+func bindingUpToDate(existing *v1alpha1.KameletBinding, desired *v1alpha1.KameletBinding) bool {
+	for key, value := range desired.Labels {
+		if existing.Labels[key] != value {
+			return false
+		}
+	}
+	for key, value := range desired.Annotations {
+		if existing.Annotations[key] != value {
+			return false
+		}
+	}
+	return jsonEqual(existing.Spec, desired.Spec)
+}
+
+// jsonEqual compares the JSON representation of both values ignoring empty fields
+func jsonEqual(a interface{}, b interface{}) bool {
+	normalized := make([]interface{}, 0, 2)
+	for _, value := range []interface{}{a, b} {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return false
+		}
+		var content interface{}
+		if err := json.Unmarshal(data, &content); err != nil {
+			return false
+		}
+		normalized = append(normalized, pruneEmpty(content))
+	}
+	return reflect.DeepEqual(normalized[0], normalized[1])
+}
+
+// pruneEmpty removes null values and empty objects from the given JSON content
+func pruneEmpty(content interface{}) interface{} {
+	switch v := content.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			value = pruneEmpty(value)
+			if value == nil {
+				delete(v, key)
+			} else {
+				v[key] = value
+			}
+		}
+		if len(v) == 0 {
+			return nil
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = pruneEmpty(v[i])
+		}
+	}
+	return content
+}
+
+func mergeStringMaps(existing map[string]string, changes map[string]string) map[string]string {
+	if len(changes) == 0 {
+		return existing
+	}
+	merged := make(map[string]string, len(existing)+len(changes))
+	for key, value := range existing {
+		merged[key] = value
+	}
+	for key, value := range changes {
+		merged[key] = value
+	}
+	return merged
+}
+
+// withoutKeys returns a copy of the map without the given keys, nil when no other key is left
+func withoutKeys(m map[string]string, keys ...string) map[string]string {
+	var result map[string]string
+	for key, value := range m {
+		if contains(keys, key) {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]string)
+		}
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,268 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"knative.dev/client-pkg/pkg/commands"
+	"knative.dev/client-pkg/pkg/util"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+const compactBinding = `name: k1-to-channel
+kamelet: k1
+properties:
+  k1_prop: foo
+sink: channel:test
+`
+
+const kameletBindingManifest = `apiVersion: camel.apache.org/v1alpha1
+kind: KameletBinding
+metadata:
+  name: k1-to-channel
+spec:
+  source:
+    ref:
+      apiVersion: camel.apache.org/v1alpha1
+      kind: Kamelet
+      name: k1
+      namespace: current
+    properties:
+      k1_prop: foo
+  sink:
+    ref:
+      apiVersion: messaging.knative.dev/v1
+      kind: Channel
+      name: test
+      namespace: current
+`
+
+func TestBindingApplyCreate(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+	recorder.GetKameletBinding(nil, k8serrors.NewNotFound(v1alpha1.Resource("kameletbindings"), "k1-to-channel"))
+	recorder.CreateKameletBinding(createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace)), nil)
+
+	output, err := runBindingApplyCmd(mockClient, compactBinding, "-f", "-")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kamelet binding \"k1-to-channel\" created"))
+
+	recorder.Validate()
+}
+
+func TestBindingApplyCompactKeepsPropertyValues(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	kamelet := createKameletInNamespace("k1", namespace)
+	kamelet.Spec.Definition.Properties["k1_version"] = v1alpha1.JSONSchemaProps{Type: "string"}
+	kamelet.Spec.Definition.Properties["k1_offset"] = v1alpha1.JSONSchemaProps{Type: "integer"}
+	recorder.Get(kamelet, nil)
+	recorder.GetKameletBinding(nil, k8serrors.NewNotFound(v1alpha1.Resource("kameletbindings"), "k1-to-channel"))
+	expected := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	expected.Spec.Source.Properties.RawMessage = []byte(`{"k1_offset":9007199254740993,"k1_prop":"on","k1_version":"1.10"}`)
+	recorder.CreateKameletBinding(expected, nil)
+
+	manifest := "name: k1-to-channel\nkamelet: k1\nproperties:\n  k1_prop: on\n  k1_version: 1.10\n  k1_offset: 9007199254740993\nsink: channel:test\n"
+	output, err := runBindingApplyCmd(mockClient, manifest, "-f", "-")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kamelet binding \"k1-to-channel\" created"))
+
+	recorder.Validate()
+}
+
+func TestBindingApplyUnchanged(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	file := writeTestFile(t, "binding.yaml", kameletBindingManifest)
+
+	existing := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	existing.Spec.Sink.Properties = nil
+	existing.Labels = map[string]string{"team": "integration"}
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+	recorder.GetKameletBinding(existing, nil)
+
+	output, err := runBindingApplyCmd(mockClient, "", "-f", file)
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kamelet binding \"k1-to-channel\" unchanged"))
+
+	recorder.Validate()
+}
+
+func TestBindingApplyPipe(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	manifest := strings.ReplaceAll(kameletBindingManifest, "camel.apache.org/v1alpha1", "camel.apache.org/v1")
+	manifest = strings.Replace(manifest, "kind: KameletBinding", "kind: Pipe", 1)
+
+	existing := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	existing.Spec.Sink.Properties = nil
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+	recorder.GetKameletBinding(existing, nil)
+
+	output, err := runBindingApplyCmd(mockClient, manifest, "-f", "-")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kamelet binding \"k1-to-channel\" unchanged"))
+
+	recorder.Validate()
+}
+
+func TestBindingApplySteps(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	manifest := kameletBindingManifest + `  steps:
+  - ref:
+      apiVersion: camel.apache.org/v1alpha1
+      kind: Kamelet
+      name: a1
+      namespace: current
+    properties:
+      a1_prop: bar
+`
+
+	existing := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	existing.Spec.Sink.Properties = nil
+	step := existing.Spec.Source.DeepCopy()
+	step.Ref.Name = "a1"
+	step.Properties.RawMessage = []byte(`{"a1_prop":"bar"}`)
+	assert.NilError(t, setBindingSteps(existing, []v1alpha1.Endpoint{*step}))
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+	recorder.Get(createActionKameletInNamespace("a1", namespace), nil)
+	recorder.GetKameletBinding(existing, nil)
+
+	output, err := runBindingApplyCmd(mockClient, manifest, "-f", "-")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kamelet binding \"k1-to-channel\" unchanged"))
+
+	// steps must be action Kamelets
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+	recorder.Get(createKameletInNamespace("a1", namespace), nil)
+	_, err = runBindingApplyCmd(mockClient, manifest, "-f", "-")
+	assert.ErrorContains(t, err, "kamelet a1 is not an action - only action Kamelets can be used as step")
+
+	recorder.Validate()
+}
+
+func TestBindingApplyUpdateAndPrune(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	dir := t.TempDir()
+	manifest := strings.Replace(compactBinding, "k1_prop: foo", "k1_prop: bar", 1) + "labels:\n  team: integration\n"
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "bindings.yaml"), []byte(manifest), 0o600))
+
+	existing := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	existing.ResourceVersion = "1"
+	updated := existing.DeepCopy()
+	updated.Spec.Source.Properties.RawMessage = []byte("{\"k1_prop\":\"bar\"}")
+	updated.Labels = map[string]string{"team": "integration"}
+	obsolete := createKameletBindingInNamespace("k2-to-channel", "k2", namespace, channelRef(namespace))
+
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+	recorder.GetKameletBinding(existing, nil)
+	recorder.UpdateKameletBinding(updated, nil)
+	recorder.ListBindings(&v1alpha1.KameletBindingList{Items: []v1alpha1.KameletBinding{*updated, *obsolete}}, nil)
+	recorder.DeleteKameletBinding("k2-to-channel", nil)
+
+	output, err := runBindingApplyCmd(mockClient, "", "-f", dir, "--prune", "-l", "team=integration")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kamelet binding \"k1-to-channel\" updated", "kamelet binding \"k2-to-channel\" pruned"))
+
+	recorder.Validate()
+}
+
+func TestBindingApplyErrorCaseInvalidManifests(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+
+	input := "bindings:\n- kamelet: k1\n  sink: channel:test\n- kamelet: k1\n  properties:\n    k1_prop: foo\n    k1_optional: maybe\n  sink: channel:test\n---\nfoo: bar\n"
+	_, err := runBindingApplyCmd(mockClient, input, "-f", "-")
+	assert.Error(t, err, `<stdin>: binding is missing required property "k1_prop" for Kamelet "k1"
+<stdin>: binding property "k1_optional" must be of type boolean but is "maybe" for Kamelet "k1"
+<stdin>: unsupported document - please use a KameletBinding, a Pipe or the compact binding format`)
+
+	recorder.Validate()
+}
+
+func TestBindingApplyErrorCaseDuplicateBinding(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+
+	_, err := runBindingApplyCmd(mockClient, compactBinding+"---\n"+compactBinding, "-f", "-")
+	assert.Error(t, err, `<stdin>: kamelet binding "k1-to-channel" is already defined in <stdin>`)
+
+	recorder.Validate()
+}
+
+func TestBindingApplyErrorCasePruneWithoutSelector(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	_, err := runBindingApplyCmd(mockClient, "", "-f", "-", "--prune")
+	assert.Error(t, err, "--prune requires a label selector given with --selector")
+
+	recorder.Validate()
+}
+
+func runBindingApplyCmd(c *client.MockClient, input string, options ...string) (string, error) {
+	p := KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewKameletClient: func() (camelkv1alpha1.CamelV1alpha1Interface, error) {
+			return c, nil
+		},
+	}
+
+	applyCmd, _, output := commands.CreateSourcesTestKnCommand(newBindingApplyCommand(&p), p.KnParams)
+	applyCmd.SetIn(strings.NewReader(input))
+
+	args := []string{"apply"}
+	args = append(args, options...)
+	applyCmd.SetArgs(args)
+	err := applyCmd.Execute()
+
+	return output.String(), err
+}
@@ -0,0 +1,269 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	"github.com/spf13/cobra"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/client-pkg/pkg/commands"
+	knerrors "knative.dev/client-pkg/pkg/errors"
+	hprinters "knative.dev/client-pkg/pkg/printers"
+)
+
+var bindingCheckExample = `
+  # Check given Kamelet binding against the current definition of its Kamelets
+  kn source kamelet binding check NAME
+
+  # Check all Kamelet bindings of all namespaces, e.g. after upgrading the Kamelets
+  kn source kamelet binding check --all --all-namespaces`
+
+// bindingProblem is a problem found on the source or sink of a binding
+type bindingProblem struct {
+	endpoint string
+	message  string
+}
+
+// bindingChecker checks the bindings against the current Kamelets and sinks, each Kamelet is fetched only once
+type bindingChecker struct {
+	client   camelkv1alpha1.CamelV1alpha1Interface
+	resolver *sinkResolver
+	kamelets map[types.NamespacedName]kameletLookup
+}
+
+// kameletLookup is the result of fetching a Kamelet, err is set for a Kamelet that does not exist
+type kameletLookup struct {
+	kamelet *v1alpha1.Kamelet
+	err     error
+}
+
+// newBindingCheckCommand implements 'kn-source-kamelet binding check' command
+func newBindingCheckCommand(p *KameletPluginParams) *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:     "check [NAME|--all]",
+		Short:   "Check Kamelet bindings against the current Kamelet definitions.",
+		Example: bindingCheckExample,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) > 1 {
+				return errors.New("'kn-source-kamelet binding check' accepts at most one binding name as argument")
+			}
+			if len(args) == 1 && all {
+				return errors.New("'kn-source-kamelet binding check' can not be used with a binding name and --all")
+			}
+			if len(args) == 0 && !all {
+				return errors.New("'kn-source-kamelet binding check' requires the binding name or --all")
+			}
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			if namespace == "" && len(args) == 1 {
+				return errors.New("'kn-source-kamelet binding check' can not be used with a binding name and --all-namespaces")
+			}
+
+			client, err := p.NewKameletClient()
+			if err != nil {
+				return err
+			}
+
+			bindings := &v1alpha1.KameletBindingList{}
+			if len(args) == 1 {
+				binding, err := client.KameletBindings(namespace).Get(p.Context, args[0], v1.GetOptions{})
+				if err != nil {
+					return knerrors.GetError(err)
+				}
+				bindings.Items = append(bindings.Items, *binding)
+			} else {
+				bindings, err = client.KameletBindings(namespace).List(p.Context, v1.ListOptions{})
+				if err != nil {
+					return knerrors.GetError(err)
+				}
+			}
+			if len(bindings.Items) == 0 {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "No resources found.\n")
+				return nil
+			}
+
+			resolver, err := p.newSinkResolver(true)
+			if err != nil {
+				return err
+			}
+			checker := &bindingChecker{
+				client:   client,
+				resolver: resolver,
+				kamelets: make(map[types.NamespacedName]kameletLookup),
+			}
+
+			failed := &v1alpha1.KameletBindingList{}
+			problems := make(map[types.NamespacedName][]bindingProblem)
+			count := 0
+			for _, binding := range bindings.Items {
+				found, err := checker.check(p.Context, &binding)
+				if err != nil {
+					return err
+				}
+				if len(found) > 0 {
+					failed.Items = append(failed.Items, binding)
+					problems[types.NamespacedName{Namespace: binding.Namespace, Name: binding.Name}] = found
+					count += len(found)
+				}
+			}
+
+			if len(failed.Items) == 0 {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%d kamelet binding(s) checked, no problems found\n", len(bindings.Items))
+				return nil
+			}
+
+			printer := hprinters.NewTablePrinter(hprinters.PrintOptions{AllNamespaces: namespace == ""})
+			bindingCheckHandlers(printer, problems)
+			if err := printer.PrintObj(failed, cmd.OutOrStdout()); err != nil {
+				return err
+			}
+			return fmt.Errorf("found %d problem(s) in %d of %d kamelet binding(s)", count, len(failed.Items), len(bindings.Items))
+		},
+	}
+	cmd.Flags().BoolVar(&all, "all", false, "Check all Kamelet bindings of the namespace.")
+	commands.AddNamespaceFlags(cmd.Flags(), true)
+	return cmd
+}
+
+// bindingCheckHandlers handles printing the table of problems found on the bindings
+func bindingCheckHandlers(h hprinters.PrintHandler, problems map[types.NamespacedName][]bindingProblem) {
+	columnDefinitions := []metav1beta1.TableColumnDefinition{
+		{Name: "Namespace", Type: "string", Description: "Namespace of the Kamelet binding", Priority: 0},
+		{Name: "Name", Type: "string", Description: "Name of the Kamelet binding", Priority: 1},
+		{Name: "Endpoint", Type: "string", Description: "Endpoint of the Kamelet binding having the problem", Priority: 1},
+		{Name: "Problem", Type: "string", Description: "Problem found on the endpoint", Priority: 1},
+	}
+	h.TableHandler(columnDefinitions, func(bindingList *v1alpha1.KameletBindingList, options hprinters.PrintOptions) ([]metav1beta1.TableRow, error) {
+		rows := make([]metav1beta1.TableRow, 0, len(bindingList.Items))
+		for i := range bindingList.Items {
+			binding := &bindingList.Items[i]
+			for _, problem := range problems[types.NamespacedName{Namespace: binding.Namespace, Name: binding.Name}] {
+				row := metav1beta1.TableRow{
+					Object: runtime.RawExtension{Object: binding},
+				}
+				if options.AllNamespaces {
+					row.Cells = append(row.Cells, binding.Namespace)
+				}
+				row.Cells = append(row.Cells, binding.Name, problem.endpoint, problem.message)
+				rows = append(rows, row)
+			}
+		}
+		return rows, nil
+	})
+}
+
+// check returns the problems found on the source, steps and sink of the binding. Kamelet endpoints are verified
+// against the current Kamelet definition, all other referenced objects must exist and be addressable.
+func (c *bindingChecker) check(ctx context.Context, binding *v1alpha1.KameletBinding) ([]bindingProblem, error) {
+	type namedEndpoint struct {
+		name     string
+		endpoint v1alpha1.Endpoint
+	}
+	steps, err := bindingSteps(binding)
+	if err != nil {
+		return nil, err
+	}
+	endpoints := []namedEndpoint{{name: "source", endpoint: binding.Spec.Source}}
+	for i, step := range steps {
+		endpoints = append(endpoints, namedEndpoint{name: fmt.Sprintf("step%d", i+1), endpoint: step})
+	}
+	endpoints = append(endpoints, namedEndpoint{name: "sink", endpoint: binding.Spec.Sink})
+
+	var problems []bindingProblem
+	for _, endpoint := range endpoints {
+		messages, err := c.checkEndpoint(ctx, endpoint.endpoint, binding.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, message := range messages {
+			problems = append(problems, bindingProblem{endpoint: endpoint.name, message: message})
+		}
+	}
+	return problems, nil
+}
+
+// checkEndpoint returns the problems found on the given endpoint, URI endpoints are not checked
+func (c *bindingChecker) checkEndpoint(ctx context.Context, endpoint v1alpha1.Endpoint, bindingNamespace string) ([]string, error) {
+	if endpoint.Ref == nil {
+		return nil, nil
+	}
+	ref := *endpoint.Ref
+	if ref.Namespace == "" {
+		ref.Namespace = bindingNamespace
+	}
+
+	if !isKameletEndpoint(endpoint) {
+		err := c.resolver.verifySink(ctx, ref)
+		switch {
+		case err == nil:
+			return nil, nil
+		case k8serrors.IsNotFound(err):
+			return []string{fmt.Sprintf("%s %q not found in namespace %q", ref.Kind, ref.Name, ref.Namespace)}, nil
+		case errors.Is(err, errNotAddressable):
+			return []string{err.Error()}, nil
+		}
+		return nil, err
+	}
+
+	kamelet, err := c.kamelet(ctx, ref.Namespace, ref.Name)
+	if k8serrors.IsNotFound(err) {
+		return []string{fmt.Sprintf("kamelet %q not found in namespace %q", ref.Name, ref.Namespace)}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	violations, err := propertyViolations(kamelet, endpoint)
+	if err != nil {
+		return []string{err.Error()}, nil
+	}
+	var messages []string
+	for _, violation := range violations {
+		messages = append(messages, violation.Error())
+	}
+	return messages, nil
+}
+
+// kamelet returns the Kamelet of the given namespace and name fetching it on first use. Missing Kamelets are
+// remembered as well, so bindings sharing a deleted Kamelet do not fetch it again.
+func (c *bindingChecker) kamelet(ctx context.Context, namespace string, name string) (*v1alpha1.Kamelet, error) {
+	key := types.NamespacedName{Namespace: namespace, Name: name}
+	if lookup, ok := c.kamelets[key]; ok {
+		return lookup.kamelet, lookup.err
+	}
+
+	kamelet, err := c.client.Kamelets(namespace).Get(ctx, name, v1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return nil, knerrors.GetError(err)
+	}
+	c.kamelets[key] = kameletLookup{kamelet: kamelet, err: err}
+	return kamelet, err
+}
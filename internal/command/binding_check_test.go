@@ -0,0 +1,209 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/client-pkg/pkg/commands"
+	"knative.dev/client-pkg/pkg/util"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBindingCheckSetup(t *testing.T) {
+	p := KameletPluginParams{
+		Context: context.TODO(),
+	}
+	checkCmd := newBindingCheckCommand(&p)
+	assert.Equal(t, checkCmd.Use, "check [NAME|--all]")
+	assert.Equal(t, checkCmd.Short, "Check Kamelet bindings against the current Kamelet definitions.")
+	assert.Assert(t, checkCmd.RunE != nil)
+}
+
+func TestBindingCheckErrorCaseMissingArgument(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	_, err := runBindingCheckCmd(mockClient, nil)
+	assert.Error(t, err, "'kn-source-kamelet binding check' requires the binding name or --all")
+
+	_, err = runBindingCheckCmd(mockClient, nil, "k1-to-broker", "--all")
+	assert.Error(t, err, "'kn-source-kamelet binding check' can not be used with a binding name and --all")
+
+	_, err = runBindingCheckCmd(mockClient, nil, "k1-to-broker", "--all-namespaces")
+	assert.Error(t, err, "'kn-source-kamelet binding check' can not be used with a binding name and --all-namespaces")
+
+	recorder.Validate()
+}
+
+func TestBindingCheck(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.GetKameletBinding(createKameletBindingInNamespace("k1-to-broker", "k1", "current", brokerRef("current")), nil)
+	recorder.Get(createKameletInNamespace("k1", "current"), nil)
+
+	output, err := runBindingCheckCmd(mockClient, []runtime.Object{readyBroker("current")}, "k1-to-broker", "-n", "current")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "1 kamelet binding(s) checked, no problems found\n")
+
+	recorder.Validate()
+}
+
+func TestBindingCheckKameletChanged(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.GetKameletBinding(createKameletBindingInNamespace("k1-to-broker", "k1", "current", brokerRef("current")), nil)
+
+	// the upgraded Kamelet renamed its required property and made the optional property a number
+	kamelet := createKameletInNamespace("k1", "current")
+	kamelet.Spec.Definition.Required = []string{"k1_renamed"}
+	kamelet.Spec.Definition.Properties = map[string]v1alpha1.JSONSchemaProps{
+		"k1_renamed": {Type: "string"},
+	}
+	recorder.Get(kamelet, nil)
+
+	output, err := runBindingCheckCmd(mockClient, []runtime.Object{readyBroker("current")}, "k1-to-broker", "-n", "current")
+	assert.Error(t, err, "found 2 problem(s) in 1 of 1 kamelet binding(s)")
+	assert.Assert(t, util.ContainsAll(output, "NAME", "ENDPOINT", "PROBLEM"))
+	assert.Assert(t, util.ContainsNone(output, "NAMESPACE"))
+	assert.Assert(t, util.ContainsAll(output, "k1-to-broker", "source", "binding is missing required property \"k1_renamed\" for Kamelet \"k1\""))
+	assert.Assert(t, util.ContainsAll(output, "binding uses unknown property \"k1_prop\" for Kamelet \"k1\""))
+
+	recorder.Validate()
+}
+
+func TestBindingCheckSteps(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	binding := createKameletBindingInNamespace("k1-to-broker", "k1", "current", brokerRef("current"))
+	step := binding.Spec.Source.DeepCopy()
+	step.Ref.Name = "a1"
+	assert.NilError(t, setBindingSteps(binding, []v1alpha1.Endpoint{*step}))
+	recorder.GetKameletBinding(binding, nil)
+	recorder.Get(createKameletInNamespace("k1", "current"), nil)
+	recorder.Get(createActionKameletInNamespace("a1", "current"), nil)
+
+	output, err := runBindingCheckCmd(mockClient, []runtime.Object{readyBroker("current")}, "k1-to-broker", "-n", "current")
+	assert.Error(t, err, "found 2 problem(s) in 1 of 1 kamelet binding(s)")
+	assert.Assert(t, util.ContainsAll(output, "step1", "binding is missing required property \"a1_prop\" for Kamelet \"a1\""))
+	assert.Assert(t, util.ContainsAll(output, "binding uses unknown property \"k1_prop\" for Kamelet \"a1\""))
+
+	recorder.Validate()
+}
+
+func TestBindingCheckAllNamespaces(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.ListBindings(&v1alpha1.KameletBindingList{
+		Items: []v1alpha1.KameletBinding{
+			*createKameletBindingInNamespace("k1-to-broker", "k1", "current", brokerRef("current")),
+			*createKameletBindingInNamespace("k2-to-broker", "k2", "other", brokerRef("other")),
+			*createKameletBindingInNamespace("k1-to-other-broker", "k1", "current", brokerRef("other")),
+		},
+	}, nil)
+	recorder.Get(createKameletInNamespace("k1", "current"), nil)
+	recorder.Get(nil, k8serrors.NewNotFound(v1alpha1.Resource("kamelets"), "k2"))
+
+	output, err := runBindingCheckCmd(mockClient, []runtime.Object{readyBroker("current")}, "--all", "--all-namespaces")
+	assert.Error(t, err, "found 3 problem(s) in 2 of 3 kamelet binding(s)")
+	assert.Assert(t, util.ContainsAll(output, "NAMESPACE", "NAME", "ENDPOINT", "PROBLEM"))
+	assert.Assert(t, util.ContainsNone(output, "k1-to-broker"))
+	assert.Assert(t, util.ContainsAll(output, "other", "k2-to-broker", "source", "kamelet \"k2\" not found in namespace \"other\""))
+	assert.Assert(t, util.ContainsAll(output, "sink", "Broker \"default\" not found in namespace \"other\""))
+	assert.Assert(t, util.ContainsAll(output, "current", "k1-to-other-broker"))
+
+	recorder.Validate()
+}
+
+func TestBindingCheckKameletNotFoundOnce(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.ListBindings(&v1alpha1.KameletBindingList{
+		Items: []v1alpha1.KameletBinding{
+			*createKameletBindingInNamespace("k2-to-broker", "k2", "current", brokerRef("current")),
+			*createKameletBindingInNamespace("k2-to-other-broker", "k2", "current", brokerRef("current")),
+		},
+	}, nil)
+	// the missing Kamelet is fetched only once for both bindings
+	recorder.Get(nil, k8serrors.NewNotFound(v1alpha1.Resource("kamelets"), "k2"))
+
+	output, err := runBindingCheckCmd(mockClient, []runtime.Object{readyBroker("current")}, "--all", "-n", "current")
+	assert.Error(t, err, "found 2 problem(s) in 2 of 2 kamelet binding(s)")
+	assert.Assert(t, util.ContainsAll(output, "k2-to-broker", "k2-to-other-broker", "kamelet \"k2\" not found in namespace \"current\""))
+
+	recorder.Validate()
+}
+
+func TestBindingCheckNoBindings(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.ListBindings(&v1alpha1.KameletBindingList{}, nil)
+
+	output, err := runBindingCheckCmd(mockClient, nil, "--all", "-n", "current")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "No resources found.\n")
+
+	recorder.Validate()
+}
+
+func readyBroker(namespace string) *eventingv1.Broker {
+	return &eventingv1.Broker{
+		ObjectMeta: v1.ObjectMeta{Name: "default", Namespace: namespace},
+		Status: eventingv1.BrokerStatus{
+			AddressStatus: duckv1.AddressStatus{
+				Address: &duckv1.Addressable{URL: apis.HTTP("broker-ingress.knative-eventing.svc.cluster.local")},
+			},
+		},
+	}
+}
+
+func runBindingCheckCmd(c *client.MockClient, objects []runtime.Object, options ...string) (string, error) {
+	p := KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewKameletClient: func() (camelkv1alpha1.CamelV1alpha1Interface, error) {
+			return c, nil
+		},
+	}
+	p.NewDynamicClient = newFakeDynamicClient(objects...)
+
+	checkCmd, _, output := commands.CreateSourcesTestKnCommand(newBindingCheckCommand(&p), p.KnParams)
+
+	args := []string{"check"}
+	args = append(args, options...)
+	checkCmd.SetArgs(args)
+	err := checkCmd.Execute()
+
+	return output.String(), err
+}
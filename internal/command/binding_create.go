@@ -21,6 +21,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"path/filepath"
 	"strings"
 
@@ -32,8 +34,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"knative.dev/client-pkg/pkg/commands"
 	knerrors "knative.dev/client-pkg/pkg/errors"
+	clienteventingv1 "knative.dev/client-pkg/pkg/eventing/v1"
+	clienteventingv1beta1 "knative.dev/client-pkg/pkg/eventing/v1beta1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
 )
 
 var bindingCreateExample = `
@@ -41,12 +48,40 @@ var bindingCreateExample = `
   kn source kamelet binding create NAME
 
   # Add a binding properties
-  kn source kamelet binding create NAME --kamelet=name --sink|broker|channel|service=<name> --property=<key>=<value>`
+  kn source kamelet binding create NAME --kamelet=name --sink|broker|channel|service=<name> --property=<key>=<value>
+
+  # Bind source to an HTTP endpoint
+  kn source kamelet binding create NAME --kamelet=name --sink=https://example.com/events
+
+  # Bind source to an addressable resource given by its kind or resource name and verify the sink
+  kn source kamelet binding create NAME --kamelet=name --sink=<apiVersion>:<kind>:<name> --verify-sink
+
+  # Bind a broker to a sink Kamelet
+  kn source kamelet binding create NAME --kamelet=<sink-kamelet> --source=broker:<name> --property=<key>=<value>
+
+  # Read properties from a file and reference a Secret key instead of passing the value
+  kn source kamelet binding create NAME --kamelet=name --broker=<name> --property-file=props.yaml --property-from-secret=<secret>:<key>
+
+  # Print the binding as YAML without creating it on the cluster
+  kn source kamelet binding create NAME --kamelet=name --broker=<name> --property=<key>=<value> --dry-run=client -o yaml
+
+  # Validate the binding on the cluster without persisting it
+  kn source kamelet binding create NAME --kamelet=name --broker=<name> --property=<key>=<value> --dry-run=server
+
+  # Print the binding for a Kamelet of a local catalog without cluster access
+  kn source kamelet binding create NAME --kamelet=name --broker=<name> --property=<key>=<value> --catalog-dir=./kamelets --dry-run=client
+
+  # Register a Knative EventType for the events the binding sends to the broker
+  kn source kamelet binding create NAME --kamelet=name --broker=<name> --property=<key>=<value> --ce-type=<type> --register-event-type
+
+  # Process the events with an action Kamelet between source and sink, requires camel.apache.org/v1 Pipes
+  kn source kamelet binding create NAME --kamelet=name --broker=<name> --property=<key>=<value> --step=<action-kamelet> --step-property=step1.<key>=<value>`
 
 // newBindingCreateCommand implements 'kn-source-kamelet binding create' command
 func newBindingCreateCommand(p *KameletPluginParams) *cobra.Command {
-	var properties []string
+	var propertyFlags PropertyFlags
 	var source string
+	var bindingSource string
 	var sink string
 	var broker string
 	var channel string
@@ -54,6 +89,17 @@ func newBindingCreateCommand(p *KameletPluginParams) *cobra.Command {
 	var cloudEventsOverride []string
 	var cloudEventsSpecVersion string
 	var cloudEventsType string
+	var verifySink bool
+	var waitFlags WaitFlags
+	var kameletFileFlags KameletFileFlags
+	var integrationFlags IntegrationFlags
+	var errorHandlerFlags ErrorHandlerFlags
+	var eventTypeFlags EventTypeFlags
+	var stepFlags StepFlags
+	var registerEventType bool
+	var subscriber string
+	var filters []string
+	outputFlags := NewOutputFlags("")
 	var force bool
 
 	cmd := &cobra.Command{
@@ -71,7 +117,33 @@ func newBindingCreateCommand(p *KameletPluginParams) *cobra.Command {
 				return err
 			}
 
-			client, err := p.NewKameletClient()
+			dryRun, err := outputFlags.dryRunStrategy()
+			if err != nil {
+				return err
+			}
+			printer, err := outputFlags.printer(dryRun)
+			if err != nil {
+				return err
+			}
+
+			// local Kamelets and a client side dry run do not need any cluster access
+			offline := kameletFileFlags.specified() && dryRun == dryRunClient
+			client, err := kameletFileFlags.client(p.NewKameletClient, offline)
+			if err != nil {
+				return err
+			}
+
+			resolver := &sinkResolver{}
+			if offline && verifySink {
+				return errors.New("--verify-sink requires cluster access and can not be used with local Kamelets and --dry-run=client")
+			} else if !offline {
+				resolver, err = p.newSinkResolver(verifySink)
+				if err != nil {
+					return err
+				}
+			}
+
+			properties, err := propertyFlags.resolve(p.Context, p.NewDynamicClient, namespace)
 			if err != nil {
 				return err
 			}
@@ -79,6 +151,7 @@ func newBindingCreateCommand(p *KameletPluginParams) *cobra.Command {
 			options := CreateBindingOptions{
 				Name:                   name,
 				Source:                 source,
+				BindingSource:          bindingSource,
 				SourceProperties:       properties,
 				Sink:                   sink,
 				CloudEventsOverride:    cloudEventsOverride,
@@ -88,10 +161,24 @@ func newBindingCreateCommand(p *KameletPluginParams) *cobra.Command {
 				Channel:                channel,
 				Service:                service,
 				Force:                  force,
+				Wait:                   waitFlags.Wait,
+				WaitTimeout:            waitFlags.TimeoutInSeconds,
+				DryRun:                 dryRun,
+				Printer:                printer,
+				Pipes:                  p.pipesInUse(client, offline),
+				Integration:            &integrationFlags,
+				ErrorHandler:           &errorHandlerFlags,
+				EventTypes:             &eventTypeFlags,
+				Steps:                  &stepFlags,
+				RegisterEventType:      registerEventType,
+				NewEventTypeClient:     p.NewEventingV1beta1Client,
+				Subscriber:             subscriber,
+				Filters:                filters,
+				NewTriggerClient:       p.NewEventingClient,
 				CmdOut:                 cmd.OutOrStdout(),
 			}
 
-			err = createBinding(client, p.Context, namespace, options)
+			err = createBinding(client, resolver, p.Context, namespace, options)
 			if err != nil {
 				return err
 			}
@@ -102,39 +189,297 @@ func newBindingCreateCommand(p *KameletPluginParams) *cobra.Command {
 	flags := cmd.Flags()
 	commands.AddNamespaceFlags(flags, false)
 
-	flags.StringVar(&source, "kamelet", "", "Kamelet source.")
-	flags.StringVarP(&sink, "sink", "s", "", "Sink expression to define the binding sink.")
+	flags.StringVar(&source, "kamelet", "", "Kamelet source or sink.")
+	flags.StringVarP(&sink, "sink", "s", "", "Sink expression to define the binding sink. Use an http or https URI to send events to an endpoint address.")
 	flags.StringVar(&broker, "broker", "", "Uses a broker as binding sink.")
 	flags.StringVar(&channel, "channel", "", "Uses a channel as binding sink.")
 	flags.StringVar(&service, "service", "", "Uses a Knative service as binding sink.")
 	flags.BoolVar(&force, "force", false, "Apply the changes even if the binding already exists.")
-	flags.StringArrayVar(&properties, "property", nil, `Add a source property in the form of "<key>=<value>"`)
+	flags.StringVar(&bindingSource, "source", "", "Source expression to define the binding source when binding a sink Kamelet, e.g. broker:<name> or channel:<name>.")
+	propertyFlags.AddFlags(flags)
+	kameletFileFlags.AddFlags(flags)
 	flags.StringVar(&cloudEventsSpecVersion, "ce-spec", "", "Customize cloud events spec version provided to the binding sink.")
 	flags.StringVar(&cloudEventsType, "ce-type", "", "Customize cloud events type provided to the binding sink.")
 	flags.StringArrayVar(&cloudEventsOverride, "ce-override", nil, `Customize cloud events property in the form of "<key>=<value>"`)
+	flags.BoolVar(&verifySink, "verify-sink", false, "Verify that the sink exists and is addressable before applying the binding.")
+	integrationFlags.AddFlags(flags, false)
+	errorHandlerFlags.AddFlags(flags)
+	eventTypeFlags.AddFlags(flags)
+	stepFlags.AddFlags(flags)
+	flags.StringVar(&subscriber, "subscriber", "", "Create a Trigger on the broker sink delivering the events of the binding to the given subscriber, e.g. ksvc:<name>. The Trigger is deleted along with the binding.")
+	flags.StringArrayVar(&filters, "filter", nil, `Filter the events of the Trigger created with --subscriber in the form of "<attribute>=<value>", defaults to the cloud events type of the binding.`)
+	flags.BoolVar(&registerEventType, "register-event-type", false, "Register a Knative EventType for the events the binding sends to its broker sink, it is deleted along with the binding.")
+	waitFlags.AddFlags(flags)
+	outputFlags.AddFlags(cmd)
 	return cmd
 }
 
-func createBinding(client camelkv1alpha1.CamelV1alpha1Interface, ctx context.Context, namespace string, options CreateBindingOptions) error {
+func createBinding(client camelkv1alpha1.CamelV1alpha1Interface, resolver *sinkResolver, ctx context.Context, namespace string, options CreateBindingOptions) error {
 	kamelet, err := client.Kamelets(namespace).Get(ctx, options.Source, v1.GetOptions{})
 	if err != nil {
 		return knerrors.GetError(err)
 	}
-
-	if !isEventSourceType(kamelet) {
-		return fmt.Errorf("kamelet %s is not an event source", options.Source)
+	binding, err := buildKameletBinding(kamelet, resolver, ctx, namespace, options)
+	if err != nil {
+		return err
 	}
+	name := binding.Name
 
-	sourceProps, err := parseProperties(options.SourceProperties)
+	steps, err := options.Steps.resolve(ctx, client, namespace)
 	if err != nil {
+		return err
+	}
+	if err := setBindingSteps(binding, steps); err != nil {
+		return err
+	}
+
+	var eventType *eventingv1beta1.EventType
+	if options.RegisterEventType {
+		eventType, err = bindingEventType(kamelet, binding)
+		if err != nil {
+			return err
+		}
+		if binding.Annotations == nil {
+			binding.Annotations = make(map[string]string)
+		}
+		binding.Annotations[eventTypeAnnotation] = eventType.Name
+	}
+
+	var trigger *eventingv1.Trigger
+	if options.Subscriber != "" {
+		trigger, err = bindingTrigger(ctx, resolver, binding, options.Subscriber, options.Filters)
+		if err != nil {
+			return knerrors.GetError(err)
+		}
+		if binding.Labels == nil {
+			binding.Labels = make(map[string]string)
+		}
+		binding.Labels[bindingLabel] = binding.Name
+	} else if len(options.Filters) > 0 {
+		return errors.New("--filter requires --subscriber")
+	}
+
+	if options.DryRun == dryRunClient {
+		updateKameletBindingGvk(binding)
+		return writeBindingResult(binding, "created (dry run)", options)
+	}
+
+	resources := &bindingResources{
+		eventType:          eventType,
+		trigger:            trigger,
+		newEventTypeClient: options.NewEventTypeClient,
+		newTriggerClient:   options.NewTriggerClient,
+	}
+	if options.DryRun == "" {
+		if err := resources.check(ctx, resolver, binding); err != nil {
+			return err
+		}
+	}
+
+	createOptions := v1.CreateOptions{}
+	updateOptions := v1.UpdateOptions{}
+	status := "created"
+	if options.DryRun == dryRunServer {
+		createOptions.DryRun = []string{v1.DryRunAll}
+		updateOptions.DryRun = []string{v1.DryRunAll}
+		status = "created (server dry run)"
+	}
+
+	result, err := client.KameletBindings(namespace).Create(ctx, binding, createOptions)
+	created := err == nil
+	if err != nil && k8serrors.IsAlreadyExists(err) {
+		if options.Force {
+			existing, err := client.KameletBindings(namespace).Get(ctx, binding.Name, v1.GetOptions{})
+			if err != nil {
+				return knerrors.GetError(err)
+			}
+			// Update the custom resource, labels and annotations not managed by the command are kept
+			binding.ResourceVersion = existing.ResourceVersion
+			binding.Labels = mergeStringMaps(withoutKeys(existing.Labels, bindingLabel), binding.Labels)
+			binding.Annotations = mergeStringMaps(withoutKeys(existing.Annotations, managedBindingAnnotations...), binding.Annotations)
+			resources.previous = existing
+			result, err = client.KameletBindings(namespace).Update(ctx, binding, updateOptions)
+			if err != nil {
+				return knerrors.GetError(err)
+			}
+			status = strings.Replace(status, "created", "updated", 1)
+		} else {
+			return fmt.Errorf("kamelet binding with name %q already exists. Use --force to recreate the binding", binding.Name)
+		}
+	} else if err != nil {
 		return knerrors.GetError(err)
 	}
-	sourceEndpointProps, err := asEndpointProperties(sourceProps)
+
+	if result == nil {
+		result = binding
+	}
+	updateKameletBindingGvk(result)
+	if err := writeBindingResult(result, status, options); err != nil {
+		return err
+	}
+
+	// keep the output of the printer parsable
+	out := options.CmdOut
+	if options.Printer != nil {
+		out = io.Discard
+	}
+	if options.DryRun == "" {
+		if err := resources.apply(ctx, binding, out); err != nil {
+			if created {
+				return resources.rollback(client, ctx, binding, err, out)
+			}
+			return fmt.Errorf("kamelet binding %q has been updated, but its event type and trigger are incomplete: %w", name, err)
+		}
+	}
+
+	if options.Wait && options.DryRun == "" {
+		return waitForBindingReady(client, ctx, namespace, name, result.ResourceVersion, options.WaitTimeout, out)
+	}
+
+	return nil
+}
+
+// managedBindingAnnotations are the annotations the create command sets on a binding. They are dropped from the
+// existing binding when it gets recreated with --force.
+var managedBindingAnnotations = []string{eventTypeAnnotation, stepsAnnotation, errorHandlerAnnotation}
+
+// bindingResources are the EventType and Trigger created along with a binding. The resources of the previous
+// binding that are no longer requested get deleted.
+type bindingResources struct {
+	eventType *eventingv1beta1.EventType
+	trigger   *eventingv1.Trigger
+	// previous is the binding replaced with --force
+	previous *v1alpha1.KameletBinding
+
+	newEventTypeClient func(namespace string) (clienteventingv1beta1.KnEventingV1Beta1Client, error)
+	newTriggerClient   func(namespace string) (clienteventingv1.KnEventingClient, error)
+}
+
+// check makes sure the broker and subscriber exist and that the EventType and Trigger do not belong to another
+// binding, before the binding gets created
+func (r *bindingResources) check(ctx context.Context, resolver *sinkResolver, binding *v1alpha1.KameletBinding) error {
+	if r.eventType == nil && r.trigger == nil {
+		return nil
+	}
+	if resolver.newDynamicClient != nil {
+		if err := resolver.verifySink(ctx, *binding.Spec.Sink.Ref); err != nil {
+			return err
+		}
+		if r.trigger != nil && r.trigger.Spec.Subscriber.Ref != nil {
+			subscriber := r.trigger.Spec.Subscriber.Ref
+			ref := corev1.ObjectReference{APIVersion: subscriber.APIVersion, Kind: subscriber.Kind, Name: subscriber.Name, Namespace: subscriber.Namespace}
+			if err := resolver.verifySink(ctx, ref); err != nil {
+				return fmt.Errorf("invalid subscriber: %w", err)
+			}
+		}
+	}
+
+	if r.eventType != nil {
+		client, err := r.newEventTypeClient(binding.Namespace)
+		if err != nil {
+			return err
+		}
+		if err := checkBindingEventType(client, ctx, r.eventType); err != nil {
+			return err
+		}
+	}
+	if r.trigger != nil {
+		client, err := r.newTriggerClient(binding.Namespace)
+		if err != nil {
+			return err
+		}
+		if err := checkBindingTrigger(client, ctx, r.trigger); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply creates the EventType and Trigger of the binding and deletes those of the previous binding that are no
+// longer requested
+func (r *bindingResources) apply(ctx context.Context, binding *v1alpha1.KameletBinding, out io.Writer) error {
+	if r.eventType != nil {
+		client, err := r.newEventTypeClient(binding.Namespace)
+		if err != nil {
+			return err
+		}
+		if err := createBindingEventType(client, ctx, r.eventType, out); err != nil {
+			return err
+		}
+	}
+	if r.trigger != nil {
+		client, err := r.newTriggerClient(binding.Namespace)
+		if err != nil {
+			return err
+		}
+		if err := createBindingTrigger(client, ctx, r.trigger, out); err != nil {
+			return err
+		}
+	}
+	if r.previous == nil {
+		return nil
+	}
+
+	if name := r.previous.Annotations[eventTypeAnnotation]; name != "" && (r.eventType == nil || r.eventType.Name != name) {
+		client, err := r.newEventTypeClient(binding.Namespace)
+		if err != nil {
+			return err
+		}
+		if err := deleteBindingEventType(client, ctx, r.previous, out); err != nil {
+			return err
+		}
+	}
+	if r.previous.Labels[bindingLabel] != "" && r.trigger == nil {
+		client, err := r.newTriggerClient(binding.Namespace)
+		if err != nil {
+			return err
+		}
+		if err := deleteBindingTriggers(client, ctx, r.previous, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollback deletes the binding created before the given error occurred along with its EventType
+func (r *bindingResources) rollback(client camelkv1alpha1.CamelV1alpha1Interface, ctx context.Context, binding *v1alpha1.KameletBinding, cause error, out io.Writer) error {
+	if err := deleteBinding(client, ctx, binding.Name, binding.Namespace, out); err != nil {
+		return fmt.Errorf("%v - unable to delete the kamelet binding %q created before: %v", cause, binding.Name, err)
+	}
+	if r.eventType != nil {
+		eventTypeClient, err := r.newEventTypeClient(binding.Namespace)
+		if err == nil {
+			err = deleteBindingEventType(eventTypeClient, ctx, binding, out)
+		}
+		if err != nil {
+			return fmt.Errorf("%v - unable to delete the event type %q registered before: %v", cause, r.eventType.Name, err)
+		}
+	}
+	return fmt.Errorf("%v - the kamelet binding %q has been deleted again", cause, binding.Name)
+}
+
+// buildBinding creates the binding for the given options. The Kamelet properties are validated against the
+// Kamelet definition and the binding source or sink is resolved.
+func buildBinding(client camelkv1alpha1.CamelV1alpha1Interface, resolver *sinkResolver, ctx context.Context, namespace string, options CreateBindingOptions) (*v1alpha1.KameletBinding, error) {
+	kamelet, err := client.Kamelets(namespace).Get(ctx, options.Source, v1.GetOptions{})
 	if err != nil {
-		return knerrors.GetError(err)
+		return nil, knerrors.GetError(err)
+	}
+	return buildKameletBinding(kamelet, resolver, ctx, namespace, options)
+}
+
+// buildKameletBinding creates the binding for the given Kamelet and options
+func buildKameletBinding(kamelet *v1alpha1.Kamelet, resolver *sinkResolver, ctx context.Context, namespace string, options CreateBindingOptions) (*v1alpha1.KameletBinding, error) {
+	kameletProps, err := parseProperties(options.SourceProperties)
+	if err != nil {
+		return nil, knerrors.GetError(err)
 	}
-	sourceEndpoint := v1alpha1.Endpoint{
-		Properties: &sourceEndpointProps,
+	kameletEndpointProps, err := asEndpointProperties(kameletProps)
+	if err != nil {
+		return nil, knerrors.GetError(err)
+	}
+	kameletEndpoint := v1alpha1.Endpoint{
+		Properties: &kameletEndpointProps,
 		Ref: &corev1.ObjectReference{
 			Kind:       v1alpha1.KameletKind,
 			APIVersion: v1alpha1.SchemeGroupVersion.String(),
@@ -143,93 +488,141 @@ func createBinding(client camelkv1alpha1.CamelV1alpha1Interface, ctx context.Con
 		},
 	}
 
-	if err := verifyProperties(kamelet, sourceEndpoint); err != nil {
-		return knerrors.GetError(err)
+	if !isEventSourceType(kamelet) && !isEventSinkType(kamelet) {
+		return nil, fmt.Errorf("kamelet %s is not an event source or sink", options.Source)
 	}
 
-	var sinkRef corev1.ObjectReference
-	if options.Sink != "" {
-		sinkRef, err = decodeSink(options.Sink)
-	} else if options.Broker != "" {
-		sinkRef, err = decodeSink("broker:" + options.Broker)
-	} else if options.Channel != "" {
-		sinkRef, err = decodeSink("channel:" + options.Channel)
-	} else if options.Service != "" {
-		sinkRef, err = decodeSink("ksvc:" + options.Service)
-	} else {
-		err = fmt.Errorf("missing sink for binding - please use one of --sink, --broker, --channel, --service")
+	if err := coerceEndpointProperties(kamelet, kameletEndpoint.Properties); err != nil {
+		return nil, knerrors.GetError(err)
 	}
 
+	if err := verifyProperties(kamelet, kameletEndpoint); err != nil {
+		return nil, knerrors.GetError(err)
+	}
+
+	kameletEndpoint.Types, err = options.EventTypes.endpointTypes(kamelet)
 	if err != nil {
-		return knerrors.GetError(err)
+		return nil, err
 	}
 
-	if sinkRef.Namespace == "" {
-		sinkRef.Namespace = namespace
+	var sourceEndpoint v1alpha1.Endpoint
+	var sinkEndpoint v1alpha1.Endpoint
+	if isEventSourceType(kamelet) {
+		if options.BindingSource != "" {
+			return nil, fmt.Errorf("kamelet %s is an event source - please use one of --sink, --broker, --channel, --service to define the binding sink", options.Source)
+		}
+		sourceEndpoint = kameletEndpoint
+		sinkEndpoint, err = resolveBindingSink(ctx, resolver, namespace, options)
+	} else {
+		if sinkExpressionFor(options.Sink, options.Broker, options.Channel, options.Service) != "" {
+			return nil, fmt.Errorf("kamelet %s is an event sink - please use --source to define the binding source", options.Source)
+		}
+		sourceEndpoint, err = resolveBindingSource(ctx, resolver, namespace, options)
+		sinkEndpoint = kameletEndpoint
 	}
 
-	sinkProps, err := getSinkProperties(options)
 	if err != nil {
-		return knerrors.GetError(err)
+		return nil, knerrors.GetError(err)
 	}
-	sinkEndpointProps, err := asEndpointProperties(sinkProps)
+
+	integration, err := options.Integration.apply(nil)
 	if err != nil {
-		return knerrors.GetError(err)
-	}
-	sinkEndpoint := v1alpha1.Endpoint{
-		Properties: &sinkEndpointProps,
-		Ref:        &sinkRef,
+		return nil, err
 	}
 
-	name := nameFor(options.Name, options.Source, sinkRef)
+	name := nameFor(options.Name, sourceEndpoint, sinkEndpoint)
 
-	binding := v1alpha1.KameletBinding{
+	binding := &v1alpha1.KameletBinding{
 		ObjectMeta: v1.ObjectMeta{
 			Namespace: namespace,
 			Name:      name,
 		},
 		Spec: v1alpha1.KameletBindingSpec{
-			Source: sourceEndpoint,
-			Sink:   sinkEndpoint,
+			Integration: integration,
+			Source:      sourceEndpoint,
+			Sink:        sinkEndpoint,
 		},
 	}
+	if err := options.ErrorHandler.apply(ctx, resolver, binding, options.Pipes); err != nil {
+		return nil, err
+	}
+	return binding, nil
+}
 
-	existed := false
-	_, err = client.KameletBindings(namespace).Create(ctx, &binding, v1.CreateOptions{})
-	if err != nil && k8serrors.IsAlreadyExists(err) {
-		if options.Force {
-			existed = true
-
-			existing, err := client.KameletBindings(namespace).Get(ctx, binding.Name, v1.GetOptions{})
-			if err != nil {
-				return knerrors.GetError(err)
-			}
-			// Update the custom resource
-			binding.ResourceVersion = existing.ResourceVersion
-			_, err = client.KameletBindings(namespace).Update(ctx, &binding, v1.UpdateOptions{})
-			if err != nil {
-				return knerrors.GetError(err)
-			}
+// writeBindingResult prints the binding with the printer given in the options or writes the given status message.
+// The binding is printed as Pipe when Pipes are in use.
+func writeBindingResult(binding *v1alpha1.KameletBinding, status string, options CreateBindingOptions) error {
+	if options.Printer != nil {
+		var obj runtime.Object
+		var err error
+		if options.Pipes {
+			obj, err = toCamelV1Object(binding)
 		} else {
-			return fmt.Errorf("kamelet binding with name %q already exists. Use --force to recreate the binding", binding.Name)
+			obj, err = toCamelV1alpha1Object(binding)
+		}
+		if err != nil {
+			return err
 		}
+		return options.Printer.PrintObj(obj, options.CmdOut)
 	}
+	_, _ = fmt.Fprintf(options.CmdOut, "kamelet binding %q %s\n", binding.Name, status)
+	return nil
+}
 
-	if existed {
-		_, _ = fmt.Fprintf(options.CmdOut, "kamelet binding %q updated\n", name)
-	} else {
-		_, _ = fmt.Fprintf(options.CmdOut, "kamelet binding %q created\n", name)
+// resolveBindingSink creates the sink endpoint for a binding with a Kamelet source. The cloud events
+// settings are added as sink endpoint properties.
+func resolveBindingSink(ctx context.Context, resolver *sinkResolver, namespace string, options CreateBindingOptions) (v1alpha1.Endpoint, error) {
+	sink := sinkExpressionFor(options.Sink, options.Broker, options.Channel, options.Service)
+	if sink == "" {
+		return v1alpha1.Endpoint{}, fmt.Errorf("missing sink for binding - please use one of --sink, --broker, --channel, --service")
 	}
 
-	return nil
+	sinkEndpoint, err := resolver.resolve(ctx, sink, namespace)
+	if err != nil {
+		return v1alpha1.Endpoint{}, err
+	}
+
+	sinkProps, err := getSinkProperties(options)
+	if err != nil {
+		return v1alpha1.Endpoint{}, err
+	}
+	sinkEndpointProps, err := asEndpointProperties(sinkProps)
+	if err != nil {
+		return v1alpha1.Endpoint{}, err
+	}
+	sinkEndpoint.Properties = &sinkEndpointProps
+
+	return sinkEndpoint, nil
+}
+
+// resolveBindingSource creates the source endpoint for a binding with a Kamelet sink such as
+// a broker or channel
+func resolveBindingSource(ctx context.Context, resolver *sinkResolver, namespace string, options CreateBindingOptions) (v1alpha1.Endpoint, error) {
+	if options.BindingSource == "" {
+		return v1alpha1.Endpoint{}, fmt.Errorf("missing source for binding - please use --source to define the binding source for Kamelet %s", options.Source)
+	}
+	if isURISink(options.BindingSource) {
+		return v1alpha1.Endpoint{}, fmt.Errorf("unsupported binding source %q - URIs can only be used as binding sink", options.BindingSource)
+	}
+	if options.CloudEventsSpecVersion != "" || options.CloudEventsType != "" || len(options.CloudEventsOverride) > 0 {
+		return v1alpha1.Endpoint{}, fmt.Errorf("cloud events settings are not supported for Kamelet sink %s", options.Source)
+	}
+
+	sourceEndpoint, err := resolver.resolve(ctx, options.BindingSource, namespace)
+	if err != nil {
+		return v1alpha1.Endpoint{}, err
+	}
+	sourceEndpoint.Properties = &v1alpha1.EndpointProperties{}
+
+	return sourceEndpoint, nil
 }
 
-func nameFor(name, source string, sinkRef corev1.ObjectReference) string {
+func nameFor(name string, source v1alpha1.Endpoint, sink v1alpha1.Endpoint) string {
 	if name != "" {
 		return name
 	}
 
-	generated := fmt.Sprintf("%s-to-%s-%s", source, sinkRef.Kind, sinkRef.Name)
+	generated := fmt.Sprintf("%s-to-%s", endpointName(source), endpointName(sink))
 
 	generated = filepath.Base(generated)
 	generated = strings.Split(generated, ".")[0]
@@ -240,71 +633,77 @@ func nameFor(name, source string, sinkRef corev1.ObjectReference) string {
 	return generated
 }
 
-func decodeSink(sink string) (corev1.ObjectReference, error) {
-	ref := corev1.ObjectReference{}
-
-	if sinkExpression.MatchString(sink) {
-		groupNames := sinkExpression.SubexpNames()
-		for _, match := range sinkExpression.FindAllStringSubmatch(sink, -1) {
-			for idx, text := range match {
-				groupName := groupNames[idx]
-				switch groupName {
-				case "apiVersion":
-					ref.APIVersion = text
-				case "namespace":
-					ref.Namespace = text
-				case "kind":
-					ref.Kind = text
-				case "name":
-					ref.Name = text
-				}
-			}
-		}
-
-		if sinkType, ok := sinkTypes[ref.Kind]; ok {
-			if sinkType.Kind != "" {
-				ref.Kind = sinkType.Kind
-			}
-			if ref.APIVersion == "" && sinkType.APIVersion != "" {
-				ref.APIVersion = sinkType.APIVersion
-			}
-		} else {
-			return ref, fmt.Errorf("unsupported sink type %q", ref.Kind)
+// endpointName returns a short name for the given endpoint that is used in generated binding names
+func endpointName(endpoint v1alpha1.Endpoint) string {
+	switch {
+	case endpoint.Ref != nil && endpoint.Ref.Kind == v1alpha1.KameletKind:
+		return endpoint.Ref.Name
+	case endpoint.Ref != nil:
+		return fmt.Sprintf("%s-%s", endpoint.Ref.Kind, endpoint.Ref.Name)
+	case endpoint.URI != nil:
+		host := *endpoint.URI
+		if u, err := url.Parse(*endpoint.URI); err == nil {
+			host = strings.ReplaceAll(u.Hostname(), ".", "-")
 		}
-	} else {
-		return ref, fmt.Errorf("unsupported sink expression %q - please use format <kind>:<name>", sink)
+		return "uri-" + host
 	}
+	return ""
+}
 
-	return ref, nil
+// sinkExpressionFor returns the sink expression given by one of the sink options or
+// an empty string when no sink option has been set
+func sinkExpressionFor(sink, broker, channel, service string) string {
+	switch {
+	case sink != "":
+		return sink
+	case broker != "":
+		return "broker:" + broker
+	case channel != "":
+		return "channel:" + channel
+	case service != "":
+		return "ksvc:" + service
+	}
+	return ""
 }
 
+// verifyProperties checks the endpoint properties against the Kamelet definition and reports all violations at once
 func verifyProperties(kamelet *v1alpha1.Kamelet, endpoint v1alpha1.Endpoint) error {
-	pMap, err := endpoint.Properties.GetPropertyMap()
+	violations, err := propertyViolations(kamelet, endpoint)
+	if err != nil {
+		return err
+	}
+	return errors.Join(violations...)
+}
 
-	if kamelet.Spec.Definition != nil && len(kamelet.Spec.Definition.Required) > 0 {
-		if err != nil {
-			return err
-		}
-		for _, reqProp := range kamelet.Spec.Definition.Required {
-			found := false
-			if endpoint.Properties != nil {
-				if _, contains := pMap[reqProp]; contains {
-					found = true
-				}
-			}
-			if !found {
-				return fmt.Errorf("binding is missing required property %q for Kamelet %q", reqProp, kamelet.Name)
-			}
+// propertyViolations returns each violation of the Kamelet definition by the endpoint properties
+func propertyViolations(kamelet *v1alpha1.Kamelet, endpoint v1alpha1.Endpoint) ([]error, error) {
+	pMap, err := decodeEndpointProperties(endpoint.Properties)
+	if err != nil {
+		return nil, err
+	}
+	if kamelet.Spec.Definition == nil {
+		return nil, nil
+	}
+
+	var violations []error
+	for _, reqProp := range kamelet.Spec.Definition.Required {
+		if _, contains := pMap[reqProp]; !contains {
+			violations = append(violations, fmt.Errorf("binding is missing required property %q for Kamelet %q", reqProp, kamelet.Name))
 		}
 	}
 
-	for propName := range pMap {
-		if _, ok := kamelet.Spec.Definition.Properties[propName]; !ok {
-			return fmt.Errorf("binding uses unknown property %q for Kamelet %q", propName, kamelet.Name)
+	for _, propName := range sortedKeys(pMap) {
+		schema, ok := kamelet.Spec.Definition.Properties[propName]
+		if !ok {
+			violations = append(violations, fmt.Errorf("binding uses unknown property %q for Kamelet %q", propName, kamelet.Name))
+			continue
+		}
+		for _, violation := range validateProperty(propName, schema, pMap[propName]) {
+			violations = append(violations, fmt.Errorf("binding %s for Kamelet %q", violation, kamelet.Name))
 		}
 	}
 
-	return nil
+	return violations, nil
 }
 
 func parseProperties(properties []string) (map[string]string, error) {
@@ -344,11 +743,11 @@ func getSinkProperties(options CreateBindingOptions) (map[string]string, error)
 	props := make(map[string]string)
 
 	if options.CloudEventsSpecVersion != "" {
-		props["cloudEventsSpecVersion"] = options.CloudEventsSpecVersion
+		props[cloudEventsSpecVersionProperty] = options.CloudEventsSpecVersion
 	}
 
 	if options.CloudEventsType != "" {
-		props["cloudEventsType"] = options.CloudEventsType
+		props[cloudEventsTypeProperty] = options.CloudEventsType
 	}
 
 	overrideProps, err := parseProperties(options.CloudEventsOverride)
@@ -357,7 +756,7 @@ func getSinkProperties(options CreateBindingOptions) (map[string]string, error)
 	}
 
 	for key, prop := range overrideProps {
-		props["ce.override."+key] = prop
+		props[cloudEventsOverridePrefix+key] = prop
 	}
 
 	return props, nil
@@ -19,9 +19,13 @@ package command
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
+	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
 	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
@@ -29,7 +33,10 @@ import (
 	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
 	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/watch"
 	"knative.dev/client-pkg/pkg/commands"
+	clienteventingv1beta1 "knative.dev/client-pkg/pkg/eventing/v1beta1"
+	"knative.dev/client-pkg/pkg/util"
 	"knative.dev/kn-plugin-source-kamelet/internal/client"
 
 	"gotest.tools/v3/assert"
@@ -72,12 +79,12 @@ func TestBindingCreateErrorCaseNoEventSource(t *testing.T) {
 
 	kamelet := createKamelet("k1")
 	kamelet.Labels = map[string]string{
-		KameletTypeLabel: "sink",
+		KameletTypeLabel: "action",
 	}
 	recorder.Get(kamelet, nil)
 
 	err := runBindingCreateCmd(mockClient, "k1-to-sink", "--kamelet", "k1", "--channel", "test")
-	assert.Error(t, err, "kamelet k1 is not an event source")
+	assert.Error(t, err, "kamelet k1 is not an event source or sink")
 	recorder.Validate()
 }
 
@@ -168,7 +175,7 @@ func TestBindingCreateToChannel(t *testing.T) {
 		Namespace:  namespace,
 		Name:       "test",
 	}), nil)
-	err := runBindingCreateCmd(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo")
+	err := runBindingCreateCmd(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo", "--no-wait")
 	assert.NilError(t, err)
 
 	recorder.Validate()
@@ -189,10 +196,10 @@ func TestBindingCreateToBroker(t *testing.T) {
 		Name:       "test",
 	})
 
-	binding.Spec.Source.Properties.RawMessage = []byte("{\"k2_optional\":\"bar\",\"k2_prop\":\"foo\"}")
+	binding.Spec.Source.Properties.RawMessage = []byte("{\"k2_optional\":true,\"k2_prop\":\"foo\"}")
 
 	recorder.CreateKameletBinding(binding, nil)
-	err := runBindingCreateCmd(mockClient, "k2-to-broker", "--kamelet", "k2", "--broker", "test", "--property", "k2_prop=foo", "--property", "k2_optional=bar")
+	err := runBindingCreateCmd(mockClient, "k2-to-broker", "--kamelet", "k2", "--broker", "test", "--property", "k2_prop=foo", "--property", "k2_optional=true", "--no-wait")
 	assert.NilError(t, err)
 
 	recorder.Validate()
@@ -212,7 +219,7 @@ func TestBindingCreateToService(t *testing.T) {
 		Namespace:  namespace,
 		Name:       "test",
 	}), nil)
-	err := runBindingCreateCmd(mockClient, "k3-to-service", "--kamelet", "k3", "--service", "test", "--property", "k3_prop=foo")
+	err := runBindingCreateCmd(mockClient, "k3-to-service", "--kamelet", "k3", "--service", "test", "--property", "k3_prop=foo", "--no-wait")
 	assert.NilError(t, err)
 
 	recorder.Validate()
@@ -236,27 +243,661 @@ func TestBindingCreateWithCloudEventsSettings(t *testing.T) {
 	binding.Spec.Sink.Properties.RawMessage = []byte("{\"ce.override.subject\":\"custom\",\"cloudEventsSpecVersion\":\"1.0.1\",\"cloudEventsType\":\"custom-type\"}")
 
 	recorder.CreateKameletBinding(binding, nil)
-	err := runBindingCreateCmd(mockClient, "k4-to-channel", "--kamelet", "k4", "--channel", "test", "--property", "k4_prop=foo", "--ce-spec", "1.0.1", "--ce-type", "custom-type", "--ce-override", "subject=custom")
+	err := runBindingCreateCmd(mockClient, "k4-to-channel", "--kamelet", "k4", "--channel", "test", "--property", "k4_prop=foo", "--ce-spec", "1.0.1", "--ce-type", "custom-type", "--ce-override", "subject=custom", "--no-wait")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+}
+
+func TestBindingCreateWithIntegrationSettings(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	kamelet := createKameletInNamespace("k1", namespace)
+	recorder.Get(kamelet, nil)
+
+	replicas := int32(2)
+	binding := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	binding.Spec.Integration = &camelv1.IntegrationSpec{
+		Replicas:           &replicas,
+		Dependencies:       []string{"mvn:org.example:library:1.0"},
+		Profile:            camelv1.TraitProfileKnative,
+		ServiceAccountName: "runner",
+		Traits: map[string]camelv1.TraitSpec{
+			"container":  {Configuration: camelv1.TraitConfiguration{RawMessage: []byte(`{"limit-cpu":"500m"}`)}},
+			"prometheus": {Configuration: camelv1.TraitConfiguration{RawMessage: []byte(`{"enabled":true,"port":9779}`)}},
+			"mount":      {Configuration: camelv1.TraitConfiguration{RawMessage: []byte(`{"configs":["configmap:a","configmap:b"]}`)}},
+		},
+	}
+
+	recorder.CreateKameletBinding(binding, nil)
+	err := runBindingCreateCmd(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo",
+		"--trait", "prometheus.enabled=true", "--trait", "prometheus.port=9779", "--trait", "container.limit-cpu=500m",
+		"--trait", "mount.configs=configmap:a", "--trait", "mount.configs=configmap:b",
+		"--profile", "knative", "--dependency", "mvn:org.example:library:1.0", "--service-account", "runner", "--replicas", "2", "--no-wait")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+}
+
+func TestBindingCreateWithErrorSink(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	kamelet := createKameletInNamespace("k1", namespace)
+	recorder.Get(kamelet, nil)
+
+	binding := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	binding.Spec.Integration = &camelv1.IntegrationSpec{
+		Traits: map[string]camelv1.TraitSpec{
+			"knative":       {Configuration: camelv1.TraitConfiguration{RawMessage: []byte(`{"channelSinks":["knative:channel/errors?apiVersion=messaging.knative.dev%2Fv1\u0026kind=Channel"]}`)}},
+			"error-handler": {Configuration: camelv1.TraitConfiguration{RawMessage: []byte(`{"ref":"defaultErrorHandler"}`)}},
+		},
+		Configuration: []camelv1.ConfigurationSpec{
+			{Type: "property", Value: "camel.beans.defaultErrorHandler=#class:org.apache.camel.builder.DeadLetterChannelBuilder"},
+			{Type: "property", Value: "camel.beans.defaultErrorHandler.deadLetterUri=knative:channel/errors?apiVersion=messaging.knative.dev%2Fv1&kind=Channel"},
+			{Type: "property", Value: "camel.beans.defaultErrorHandler.maximumRedeliveries=3"},
+			{Type: "property", Value: "camel.beans.defaultErrorHandler.redeliveryDelay=2000"},
+		},
+	}
+
+	recorder.CreateKameletBinding(binding, nil)
+	err := runBindingCreateCmd(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo",
+		"--error-sink", "channel:errors", "--error-max-redeliveries", "3", "--error-redelivery-delay", "2s", "--no-wait")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+}
+
+func TestBindingCreateWithLogErrorHandler(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	kamelet := createKameletInNamespace("k1", namespace)
+	recorder.Get(kamelet, nil)
+
+	binding := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	binding.Spec.Integration = &camelv1.IntegrationSpec{
+		Traits: map[string]camelv1.TraitSpec{
+			"error-handler": {Configuration: camelv1.TraitConfiguration{RawMessage: []byte(`{"ref":"defaultErrorHandler"}`)}},
+		},
+		Configuration: []camelv1.ConfigurationSpec{
+			{Type: "property", Value: "camel.beans.defaultErrorHandler=#class:org.apache.camel.builder.DefaultErrorHandlerBuilder"},
+		},
+	}
+
+	recorder.CreateKameletBinding(binding, nil)
+	err := runBindingCreateCmd(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo",
+		"--error-sink", "log", "--no-wait")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+}
+
+func TestBindingCreateErrorCaseRedeliveriesWithoutErrorSink(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKameletInNamespace("k1", "current"), nil)
+
+	err := runBindingCreateCmd(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo",
+		"--error-max-redeliveries", "3")
+	assert.Error(t, err, "--error-max-redeliveries and --error-redelivery-delay require --error-sink")
+
+	recorder.Validate()
+}
+
+func TestBindingCreateWithEventTypes(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	kamelet := createKameletInNamespace("k1", namespace)
+	kamelet.Spec.Types = map[v1alpha1.EventSlot]v1alpha1.EventTypeSpec{
+		v1alpha1.EventSlotOut: {MediaType: "application/json", Schema: eventSchema("string")},
+	}
+	recorder.Get(kamelet, nil)
+
+	schemaFile := filepath.Join(t.TempDir(), "schema.json")
+	assert.NilError(t, os.WriteFile(schemaFile, []byte(`{"type":"object","required":["id"],"properties":{"id":{"type":"string","description":"Event id"}}}`), 0o600))
+
+	binding := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	binding.Spec.Source.Types = map[v1alpha1.EventSlot]v1alpha1.EventTypeSpec{
+		v1alpha1.EventSlotOut: {MediaType: "application/json", Schema: eventSchema("string")},
+	}
+
+	recorder.CreateKameletBinding(binding, nil)
+	err := runBindingCreateCmd(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo",
+		"--out-media-type", "application/json", "--out-schema", schemaFile, "--no-wait")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+}
+
+func TestBindingCreateErrorCaseMediaTypeMismatch(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKameletInNamespace("k1", "current")
+	kamelet.Spec.Types = map[v1alpha1.EventSlot]v1alpha1.EventTypeSpec{
+		v1alpha1.EventSlotOut: {MediaType: "application/json"},
+	}
+	recorder.Get(kamelet, nil)
+
+	err := runBindingCreateCmd(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo",
+		"--out-media-type", "text/plain")
+	assert.Error(t, err, "media type \"text/plain\" does not match media type \"application/json\" of the out slot of Kamelet k1")
+
+	recorder.Validate()
+}
+
+func TestBindingCreateErrorCaseSchemaMismatch(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKameletInNamespace("k1", "current")
+	kamelet.Spec.Types = map[v1alpha1.EventSlot]v1alpha1.EventTypeSpec{
+		v1alpha1.EventSlotOut: {Schema: eventSchema("string")},
+	}
+	recorder.Get(kamelet, nil)
+
+	schemaFile := filepath.Join(t.TempDir(), "schema.yaml")
+	assert.NilError(t, os.WriteFile(schemaFile, []byte("type: object\nproperties:\n  id:\n    type: integer\n"), 0o600))
+
+	err := runBindingCreateCmd(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo",
+		"--out-schema", schemaFile)
+	assert.Error(t, err, "schema does not match the schema of the out slot of Kamelet k1: id is of type integer instead of string")
+
+	recorder.Validate()
+}
+
+func TestBindingCreateErrorCaseSchemaMismatchDraft07(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKameletInNamespace("k1", "current")
+	kamelet.Spec.Types = map[v1alpha1.EventSlot]v1alpha1.EventTypeSpec{
+		v1alpha1.EventSlotOut: {Schema: eventSchema("string")},
+	}
+	recorder.Get(kamelet, nil)
+
+	schemaFile := filepath.Join(t.TempDir(), "schema.json")
+	assert.NilError(t, os.WriteFile(schemaFile, []byte(`{"$schema": "http://json-schema.org/draft-07/schema#", "$id": "https://example.com/event.json",
+"type": "object", "examples": [{"id": 1}], "properties": {"id": {"type": "integer", "const": 1}}}`), 0o600))
+
+	err := runBindingCreateCmd(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo",
+		"--out-schema", schemaFile)
+	assert.Error(t, err, "schema does not match the schema of the out slot of Kamelet k1: id is of type integer instead of string")
+
+	recorder.Validate()
+}
+
+func TestBindingCreateErrorCaseInMediaTypeForSource(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKameletInNamespace("k1", "current"), nil)
+
+	err := runBindingCreateCmd(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo",
+		"--in-media-type", "application/json")
+	assert.Error(t, err, "kamelet k1 is not an event sink - --in-media-type is only supported for sink Kamelets")
+
+	recorder.Validate()
+}
+
+func eventSchema(idType string) *v1alpha1.JSONSchemaProps {
+	return &v1alpha1.JSONSchemaProps{
+		Type:     "object",
+		Required: []string{"id"},
+		Properties: map[string]v1alpha1.JSONSchemaProps{
+			"id": {Type: idType, Description: "Event id"},
+		},
+	}
+}
+
+func TestBindingCreateRegisterEventType(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	kamelet := createKameletInNamespace("k1", namespace)
+	kamelet.Spec.Types = map[v1alpha1.EventSlot]v1alpha1.EventTypeSpec{
+		v1alpha1.EventSlotOut: {MediaType: "application/json", Schema: eventSchema("string")},
+	}
+	recorder.Get(kamelet, nil)
+
+	binding := createKameletBindingInNamespace("k1-to-broker", "k1", namespace, &corev1.ObjectReference{
+		Kind:       "Broker",
+		APIVersion: eventingv1.SchemeGroupVersion.String(),
+		Namespace:  namespace,
+		Name:       "default",
+	})
+	binding.Annotations = map[string]string{eventTypeAnnotation: "k1-to-broker"}
+	binding.Spec.Sink.Properties.RawMessage = []byte("{\"ce.override.source\":\"/orders\",\"cloudEventsType\":\"org.example.order\"}")
+
+	recorder.CreateKameletBinding(binding, nil)
+	eventTypeClient := newFakeEventTypeClient()
+	output, err := runBindingCreateCmdWithEventTypes(mockClient, eventTypeClient, "k1-to-broker", "--kamelet", "k1", "--broker", "default",
+		"--property", "k1_prop=foo", "--ce-type", "org.example.order", "--ce-override", "source=/orders", "--register-event-type", "--no-wait")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(output, "kamelet binding \"k1-to-broker\" created", "event type \"k1-to-broker\" registered"))
+
+	eventType, ok := eventTypeClient.eventTypes["k1-to-broker"]
+	assert.Assert(t, ok)
+	assert.Equal(t, eventType.Labels[bindingLabel], "k1-to-broker")
+	assert.Equal(t, eventType.Spec.Type, "org.example.order")
+	assert.Equal(t, eventType.Spec.Source.String(), "/orders")
+	assert.Equal(t, eventType.Spec.Reference.Kind, "Broker")
+	assert.Equal(t, eventType.Spec.Reference.Name, "default")
+	assert.Check(t, util.ContainsAll(eventType.Spec.SchemaData, "\"type\":\"object\"", "\"required\":[\"id\"]"))
+
+	recorder.Validate()
+}
+
+func TestBindingCreateRegisterEventTypeDryRun(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKameletInNamespace("k1", "current"), nil)
+
+	eventTypeClient := newFakeEventTypeClient()
+	_, err := runBindingCreateCmdWithEventTypes(mockClient, eventTypeClient, "k1-to-broker", "--kamelet", "k1", "--broker", "default",
+		"--property", "k1_prop=foo", "--ce-type", "org.example.order", "--register-event-type", "--dry-run=client")
+	assert.NilError(t, err)
+	assert.Equal(t, len(eventTypeClient.eventTypes), 0)
+
+	recorder.Validate()
+}
+
+func TestBindingCreateErrorCaseRegisterEventTypeWithoutBroker(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKameletInNamespace("k1", "current"), nil)
+
+	err := runBindingCreateCmd(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo",
+		"--ce-type", "org.example.order", "--register-event-type")
+	assert.Error(t, err, "--register-event-type requires a broker as binding sink")
+
+	recorder.Validate()
+}
+
+func TestBindingCreateErrorCaseRegisterEventTypeWithoutType(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKameletInNamespace("k1", "current"), nil)
+
+	err := runBindingCreateCmd(mockClient, "k1-to-broker", "--kamelet", "k1", "--broker", "default", "--property", "k1_prop=foo",
+		"--register-event-type")
+	assert.Error(t, err, "--register-event-type requires the cloud events type of the binding given with --ce-type")
+
+	recorder.Validate()
+}
+
+func TestBindingCreateErrorCaseUnknownTrait(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKameletInNamespace("k1", "current"), nil)
+
+	err := runBindingCreateCmd(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo",
+		"--trait", "metrics.enabled=true")
+	assert.ErrorContains(t, err, "unknown trait \"metrics\" - please use one of 3scale, affinity")
+
+	recorder.Validate()
+}
+
+func TestBindingCreateErrorCaseInvalidProfile(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKameletInNamespace("k1", "current"), nil)
+
+	err := runBindingCreateCmd(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo",
+		"--profile", "serverless")
+	assert.Error(t, err, "unsupported trait profile \"serverless\" - please use one of Kubernetes, Knative, OpenShift")
+
+	recorder.Validate()
+}
+
+func TestBindingCreateToFullyQualifiedSink(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	kamelet := createKameletInNamespace("k1", namespace)
+	recorder.Get(kamelet, nil)
+
+	binding := createKameletBindingInNamespace("k1-to-kafka", "k1", namespace, &corev1.ObjectReference{
+		Kind:       "KafkaChannel",
+		APIVersion: "messaging.knative.dev/v1beta1",
+		Namespace:  "other",
+		Name:       "kc",
+	})
+
+	recorder.CreateKameletBinding(binding, nil)
+	err := runBindingCreateCmd(mockClient, "k1-to-kafka", "--kamelet", "k1", "--sink", "messaging.knative.dev/v1beta1:KafkaChannel:other/kc", "--property", "k1_prop=foo", "--no-wait")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+}
+
+func TestBindingCreateToURI(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	kamelet := createKameletInNamespace("k1", namespace)
+	recorder.Get(kamelet, nil)
+
+	uri := "https://example.com/events?token=abc"
+	binding := createKameletBindingInNamespace("k1-to-uri", "k1", namespace, nil)
+	binding.Spec.Sink.URI = &uri
+	binding.Spec.Sink.Properties.RawMessage = []byte("{\"ce.override.subject\":\"custom\",\"cloudEventsType\":\"custom-type\"}")
+
+	recorder.CreateKameletBinding(binding, nil)
+	err := runBindingCreateCmd(mockClient, "k1-to-uri", "--kamelet", "k1", "--sink", uri, "--property", "k1_prop=foo", "--ce-type", "custom-type", "--ce-override", "subject=custom", "--no-wait")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+}
+
+func TestBindingCreateErrorCaseInvalidURI(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	recorder.Get(kamelet, nil)
+
+	err := runBindingCreateCmd(mockClient, "k1-to-uri", "--kamelet", "k1", "--sink", "http:///events", "--property", "k1_prop=foo")
+	assert.Error(t, err, "invalid sink URI \"http:///events\" - missing host")
+
+	recorder.Get(kamelet, nil)
+
+	err = runBindingCreateCmd(mockClient, "k1-to-uri", "--kamelet", "k1", "--sink", "http://example.com:port", "--property", "k1_prop=foo")
+	assert.ErrorContains(t, err, "invalid sink URI \"http://example.com:port\"")
+
+	recorder.Validate()
+}
+
+func TestBindingCreateBrokerToSinkKamelet(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	kamelet := createSinkKameletInNamespace("log-sink", namespace)
+	recorder.Get(kamelet, nil)
+
+	recorder.CreateKameletBinding(&v1alpha1.KameletBinding{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: namespace,
+			Name:      "broker-to-log",
+		},
+		Spec: v1alpha1.KameletBindingSpec{
+			Source: v1alpha1.Endpoint{
+				Properties: &v1alpha1.EndpointProperties{},
+				Ref: &corev1.ObjectReference{
+					Kind:       "Broker",
+					APIVersion: eventingv1.SchemeGroupVersion.String(),
+					Namespace:  namespace,
+					Name:       "default",
+				},
+			},
+			Sink: v1alpha1.Endpoint{
+				Properties: &v1alpha1.EndpointProperties{
+					RawMessage: []byte("{\"log-sink_prop\":\"foo\"}"),
+				},
+				Ref: &corev1.ObjectReference{
+					Kind:       v1alpha1.KameletKind,
+					APIVersion: v1alpha1.SchemeGroupVersion.String(),
+					Namespace:  namespace,
+					Name:       "log-sink",
+				},
+			},
+		},
+	}, nil)
+
+	err := runBindingCreateCmd(mockClient, "broker-to-log", "--kamelet", "log-sink", "--source", "broker:default", "--property", "log-sink_prop=foo", "--no-wait")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+}
+
+func TestBindingCreateErrorCaseSinkKameletMissingSource(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createSinkKameletInNamespace("log-sink", "current"), nil)
+
+	err := runBindingCreateCmd(mockClient, "broker-to-log", "--kamelet", "log-sink", "--property", "log-sink_prop=foo")
+	assert.Error(t, err, "missing source for binding - please use --source to define the binding source for Kamelet log-sink")
+
+	recorder.Get(createSinkKameletInNamespace("log-sink", "current"), nil)
+
+	err = runBindingCreateCmd(mockClient, "broker-to-log", "--kamelet", "log-sink", "--channel", "test", "--property", "log-sink_prop=foo")
+	assert.Error(t, err, "kamelet log-sink is an event sink - please use --source to define the binding source")
+
+	recorder.Validate()
+}
+
+func TestBindingCreateErrorCaseSinkKameletMissingRequiredProperty(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createSinkKameletInNamespace("log-sink", "current"), nil)
+
+	err := runBindingCreateCmd(mockClient, "broker-to-log", "--kamelet", "log-sink", "--source", "broker:default")
+	assert.Error(t, err, "binding is missing required property \"log-sink_prop\" for Kamelet \"log-sink\"")
+
+	recorder.Validate()
+}
+
+func TestBindingCreateErrorCaseSourceKameletWithSource(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKameletInNamespace("k1", "current"), nil)
+
+	err := runBindingCreateCmd(mockClient, "k1-to-channel", "--kamelet", "k1", "--source", "broker:default", "--property", "k1_prop=foo")
+	assert.Error(t, err, "kamelet k1 is an event source - please use one of --sink, --broker, --channel, --service to define the binding sink")
+
+	recorder.Validate()
+}
+
+func TestBindingCreateWaitForReady(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	kamelet := createKameletInNamespace("k1", namespace)
+	recorder.Get(kamelet, nil)
+
+	binding := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	recorder.CreateKameletBinding(binding, nil)
+
+	creating := binding.DeepCopy()
+	creating.Status.Phase = v1alpha1.KameletBindingPhaseCreating
+	ready := binding.DeepCopy()
+	ready.Status = statusReady()
+
+	watcher := watch.NewFakeWithChanSize(2, false)
+	watcher.Add(creating)
+	watcher.Modify(ready)
+	recorder.WatchKameletBinding(watcher, nil)
+
+	// waiting is the default
+	err := runBindingCreateCmd(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+}
+
+func TestBindingCreateWaitErrorCaseFailed(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	kamelet := createKameletInNamespace("k1", namespace)
+	recorder.Get(kamelet, nil)
+
+	binding := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	recorder.CreateKameletBinding(binding, nil)
+
+	failed := binding.DeepCopy()
+	failed.Status = v1alpha1.KameletBindingStatus{
+		Phase: v1alpha1.KameletBindingPhaseError,
+		Conditions: []v1alpha1.KameletBindingCondition{
+			{
+				Type:    v1alpha1.KameletBindingConditionReady,
+				Status:  corev1.ConditionFalse,
+				Reason:  "IntegrationError",
+				Message: "sink not found",
+			},
+		},
+	}
+
+	watcher := watch.NewFakeWithChanSize(1, false)
+	watcher.Modify(failed)
+	recorder.WatchKameletBinding(watcher, nil)
+
+	err := runBindingCreateCmd(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo", "--wait")
+	assert.Error(t, err, "kamelet binding \"k1-to-channel\" failed: IntegrationError : sink not found")
+
+	recorder.Validate()
+}
+
+func TestBindingCreateWaitErrorCaseTimeout(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	kamelet := createKameletInNamespace("k1", namespace)
+	recorder.Get(kamelet, nil)
+
+	binding := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	recorder.CreateKameletBinding(binding, nil)
+	recorder.WatchKameletBinding(watch.NewFake(), nil)
+
+	err := runBindingCreateCmd(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo", "--wait", "--wait-timeout", "1")
+	assert.Error(t, err, "timeout: kamelet binding \"k1-to-channel\" not ready after 1 seconds")
+
+	recorder.Validate()
+}
+
+func TestBindingCreateErrorCaseWaitAndNoWait(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	err := runBindingCreateCmd(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--wait", "--no-wait")
+	assert.Error(t, err, "only one of --no-wait and --wait may be specified")
+
+	recorder.Validate()
+}
+
+func TestBindingCreateDryRunClient(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+
+	output, err := runBindingCreateCmdWithOutput(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo", "--dry-run=client")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "apiVersion: camel.apache.org/v1alpha1", "kind: KameletBinding", "name: k1-to-channel", "k1_prop: foo"))
+	assert.Assert(t, util.ContainsNone(output, "created"))
+
+	recorder.Validate()
+}
+
+func TestBindingCreateDryRunClientJSON(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+
+	output, err := runBindingCreateCmdWithOutput(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo", "--dry-run", "-o", "json")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, `"kind": "KameletBinding"`, `"name": "k1-to-channel"`))
+
+	recorder.Validate()
+}
+
+func TestBindingCreateDryRunServer(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+	recorder.CreateKameletBinding(createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace)), nil)
+
+	output, err := runBindingCreateCmdWithOutput(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo", "--dry-run=server", "--wait")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kamelet binding \"k1-to-channel\" created (server dry run)"))
+
+	recorder.Validate()
+}
+
+func TestBindingCreateOutputYAML(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+	recorder.CreateKameletBinding(createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace)), nil)
+
+	output, err := runBindingCreateCmdWithOutput(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo", "-o", "yaml", "--no-wait")
 	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kind: KameletBinding", "name: k1-to-channel"))
+
+	recorder.Validate()
+}
+
+func TestBindingCreateErrorCaseInvalidDryRun(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	err := runBindingCreateCmd(mockClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--dry-run=all")
+	assert.Error(t, err, "invalid dry run strategy \"all\" - please use one of none, client, server")
 
 	recorder.Validate()
 }
 
 func runBindingCreateCmd(c *client.MockClient, options ...string) error {
+	_, err := runBindingCreateCmdWithOutput(c, options...)
+	return err
+}
+
+func runBindingCreateCmdWithOutput(c *client.MockClient, options ...string) (string, error) {
+	return runBindingCreateCmdWithEventTypes(c, nil, options...)
+}
+
+func runBindingCreateCmdWithEventTypes(c *client.MockClient, eventTypeClient *fakeEventTypeClient, options ...string) (string, error) {
 	p := KameletPluginParams{
-		KnParams: &commands.KnParams{},
-		Context:  context.TODO(),
+		KnParams: &commands.KnParams{
+			NewEventingV1beta1Client: func(namespace string) (clienteventingv1beta1.KnEventingV1Beta1Client, error) {
+				if eventTypeClient == nil {
+					return nil, errors.New("no event type client")
+				}
+				return eventTypeClient, nil
+			},
+		},
+		Context: context.TODO(),
 		NewKameletClient: func() (camelkv1alpha1.CamelV1alpha1Interface, error) {
 			return c, nil
 		},
 	}
 
-	command, _, _ := commands.CreateSourcesTestKnCommand(newBindingCreateCommand(&p), p.KnParams)
+	command, _, output := commands.CreateSourcesTestKnCommand(newBindingCreateCommand(&p), p.KnParams)
 
 	args := []string{"create"}
 	args = append(args, options...)
 	command.SetArgs(args)
 	err := command.Execute()
 
-	return err
+	return output.String(), err
 }
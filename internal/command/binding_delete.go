@@ -23,6 +23,8 @@ import (
 	"io"
 
 	knerrors "knative.dev/client-pkg/pkg/errors"
+	clienteventingv1 "knative.dev/client-pkg/pkg/eventing/v1"
+	clienteventingv1beta1 "knative.dev/client-pkg/pkg/eventing/v1beta1"
 
 	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
 
@@ -57,7 +59,7 @@ func newBindingDeleteCommand(p *KameletPluginParams) *cobra.Command {
 				return err
 			}
 
-			err = deleteBinding(client, p.Context, name, namespace, cmd.OutOrStdout())
+			err = deleteBindingWithResources(client, p.NewEventingV1beta1Client, p.NewEventingClient, p.Context, name, namespace, cmd.OutOrStdout())
 			if err != nil {
 				return err
 			}
@@ -71,6 +73,40 @@ func newBindingDeleteCommand(p *KameletPluginParams) *cobra.Command {
 	return cmd
 }
 
+// deleteBindingWithResources deletes the binding along with the resources created for it, its EventType and Triggers
+func deleteBindingWithResources(client camelkv1alpha1.CamelV1alpha1Interface, newEventTypeClient func(namespace string) (clienteventingv1beta1.KnEventingV1Beta1Client, error),
+	newTriggerClient func(namespace string) (clienteventingv1.KnEventingClient, error), ctx context.Context, name string, namespace string, cmdOut io.Writer) error {
+	binding, err := client.KameletBindings(namespace).Get(ctx, name, v1.GetOptions{})
+	if err != nil {
+		return knerrors.GetError(err)
+	}
+
+	if err := deleteBinding(client, ctx, name, namespace, cmdOut); err != nil {
+		return err
+	}
+
+	if binding.Annotations[eventTypeAnnotation] != "" {
+		eventTypeClient, err := newEventTypeClient(namespace)
+		if err != nil {
+			return err
+		}
+		if err := deleteBindingEventType(eventTypeClient, ctx, binding, cmdOut); err != nil {
+			return err
+		}
+	}
+	if binding.Labels[bindingLabel] != "" {
+		triggerClient, err := newTriggerClient(namespace)
+		if err != nil {
+			return err
+		}
+		if err := deleteBindingTriggers(triggerClient, ctx, binding, cmdOut); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func deleteBinding(client camelkv1alpha1.CamelV1alpha1Interface, ctx context.Context, name string, namespace string, cmdOut io.Writer) error {
 	err := client.KameletBindings(namespace).Delete(ctx, name, v1.DeleteOptions{})
 	if err != nil {
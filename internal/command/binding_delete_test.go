@@ -19,10 +19,17 @@ package command
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/client-pkg/pkg/commands"
+	clienteventingv1 "knative.dev/client-pkg/pkg/eventing/v1"
+	clienteventingv1beta1 "knative.dev/client-pkg/pkg/eventing/v1beta1"
+	"knative.dev/client-pkg/pkg/util"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	"knative.dev/kn-plugin-source-kamelet/internal/client"
 
 	"gotest.tools/v3/assert"
@@ -51,7 +58,7 @@ func TestBindingDeleteErrorCaseNotExists(t *testing.T) {
 	mockClient := client.NewMockClient(t)
 	recorder := mockClient.Recorder()
 
-	recorder.DeleteKameletBinding("k1-to-x", errors.New("not found"))
+	recorder.GetKameletBinding(nil, errors.New("not found"))
 	err := runBindingDeleteCmd(mockClient, "k1-to-x")
 	assert.Error(t, err, "not found")
 
@@ -62,6 +69,7 @@ func TestBindingDelete(t *testing.T) {
 	mockClient := client.NewMockClient(t)
 	recorder := mockClient.Recorder()
 
+	recorder.GetKameletBinding(createKameletBinding("k1-to-foo", "k1", channelRef("current")), nil)
 	recorder.DeleteKameletBinding("k1-to-foo", nil)
 	err := runBindingDeleteCmd(mockClient, "k1-to-foo")
 	assert.NilError(t, err)
@@ -69,21 +77,106 @@ func TestBindingDelete(t *testing.T) {
 	recorder.Validate()
 }
 
+func TestBindingDeleteWithEventType(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	binding := createKameletBinding("k1-to-broker", "k1", nil)
+	binding.Annotations = map[string]string{eventTypeAnnotation: "k1-to-broker"}
+	recorder.GetKameletBinding(binding, nil)
+	recorder.DeleteKameletBinding("k1-to-broker", nil)
+
+	eventTypeClient := newFakeEventTypeClient(&eventingv1beta1.EventType{
+		ObjectMeta: v1.ObjectMeta{Name: "k1-to-broker", Namespace: "current", Labels: map[string]string{bindingLabel: "k1-to-broker"}},
+	})
+	output, err := runBindingDeleteCmdWithEventTypes(mockClient, eventTypeClient, "k1-to-broker")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(output, "kamelet binding \"k1-to-broker\" deleted", "event type \"k1-to-broker\" deleted"))
+	assert.Equal(t, len(eventTypeClient.eventTypes), 0)
+
+	recorder.Validate()
+}
+
+func TestBindingDeleteWithEventTypeAlreadyGone(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	binding := createKameletBinding("k1-to-broker", "k1", nil)
+	binding.Annotations = map[string]string{eventTypeAnnotation: "k1-to-broker"}
+	recorder.GetKameletBinding(binding, nil)
+	recorder.DeleteKameletBinding("k1-to-broker", nil)
+
+	output, err := runBindingDeleteCmdWithEventTypes(mockClient, newFakeEventTypeClient(), "k1-to-broker")
+	assert.NilError(t, err)
+	assert.Assert(t, !strings.Contains(output, "event type"))
+
+	recorder.Validate()
+}
+
+func TestBindingDeleteWithTrigger(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	binding := createKameletBinding("k1-to-broker", "k1", nil)
+	binding.Labels = map[string]string{bindingLabel: "k1-to-broker"}
+	recorder.GetKameletBinding(binding, nil)
+	recorder.DeleteKameletBinding("k1-to-broker", nil)
+
+	triggerClient := newFakeTriggerClient(
+		&eventingv1.Trigger{ObjectMeta: v1.ObjectMeta{Name: "k1-to-broker", Labels: map[string]string{bindingLabel: "k1-to-broker"}}},
+		&eventingv1.Trigger{ObjectMeta: v1.ObjectMeta{Name: "other"}},
+	)
+	p := KameletPluginParams{
+		KnParams: &commands.KnParams{
+			NewEventingClient: func(namespace string) (clienteventingv1.KnEventingClient, error) {
+				return triggerClient, nil
+			},
+		},
+		Context: context.TODO(),
+		NewKameletClient: func() (camelkv1alpha1.CamelV1alpha1Interface, error) {
+			return mockClient, nil
+		},
+	}
+	command, _, output := commands.CreateSourcesTestKnCommand(newBindingDeleteCommand(&p), p.KnParams)
+	command.SetArgs([]string{"delete", "k1-to-broker"})
+	assert.NilError(t, command.Execute())
+
+	assert.Check(t, util.ContainsAll(output.String(), "kamelet binding \"k1-to-broker\" deleted", "trigger \"k1-to-broker\" deleted"))
+	_, ok := triggerClient.triggers["k1-to-broker"]
+	assert.Assert(t, !ok)
+	_, ok = triggerClient.triggers["other"]
+	assert.Assert(t, ok)
+
+	recorder.Validate()
+}
+
 func runBindingDeleteCmd(c *client.MockClient, options ...string) error {
+	_, err := runBindingDeleteCmdWithEventTypes(c, nil, options...)
+	return err
+}
+
+func runBindingDeleteCmdWithEventTypes(c *client.MockClient, eventTypeClient *fakeEventTypeClient, options ...string) (string, error) {
 	p := KameletPluginParams{
-		KnParams: &commands.KnParams{},
-		Context:  context.TODO(),
+		KnParams: &commands.KnParams{
+			NewEventingV1beta1Client: func(namespace string) (clienteventingv1beta1.KnEventingV1Beta1Client, error) {
+				if eventTypeClient == nil {
+					return nil, errors.New("no event type client")
+				}
+				return eventTypeClient, nil
+			},
+		},
+		Context: context.TODO(),
 		NewKameletClient: func() (camelkv1alpha1.CamelV1alpha1Interface, error) {
 			return c, nil
 		},
 	}
 
-	command, _, _ := commands.CreateSourcesTestKnCommand(newBindingDeleteCommand(&p), p.KnParams)
+	command, _, output := commands.CreateSourcesTestKnCommand(newBindingDeleteCommand(&p), p.KnParams)
 
 	args := []string{"delete"}
 	args = append(args, options...)
 	command.SetArgs(args)
 	err := command.Execute()
 
-	return err
+	return output.String(), err
 }
@@ -0,0 +1,390 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"knative.dev/client-pkg/pkg/commands"
+	knerrors "knative.dev/client-pkg/pkg/errors"
+	"knative.dev/client-pkg/pkg/printers"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	maskedPropertyValue = "*****"
+)
+
+var (
+	// secretPropertyName matches the names of properties unknown to the Kamelet definition that hold secrets
+	secretPropertyName = regexp.MustCompile(`(?i)(password|passphrase|secret|credentials?$|token$|(api|access|private)[-_]?key$)`)
+)
+
+var bindingDescribeExample = `
+  # Describe given Kamelet binding
+  kn source kamelet binding describe NAME
+
+  # Describe given Kamelet binding in YAML output format
+  kn source kamelet binding describe NAME -o yaml`
+
+// newBindingDescribeCommand implements 'kn-source-kamelet binding describe' command
+func newBindingDescribeCommand(p *KameletPluginParams) *cobra.Command {
+	printFlags := genericclioptions.NewPrintFlags("")
+
+	cmd := &cobra.Command{
+		Use:     "describe NAME",
+		Short:   "Show details of given Kamelet binding.",
+		Example: bindingDescribeExample,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) != 1 {
+				return errors.New("'kn-source-kamelet binding describe' requires the binding name as argument")
+			}
+			name := args[0]
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := p.NewKameletClient()
+			if err != nil {
+				return err
+			}
+
+			binding, err := client.KameletBindings(namespace).Get(p.Context, name, v1.GetOptions{})
+			if err != nil {
+				return knerrors.GetError(err)
+			}
+			updateKameletBindingGvk(binding)
+
+			out := cmd.OutOrStdout()
+
+			if printFlags.OutputFlagSpecified() {
+				if strings.ToLower(*printFlags.OutputFormat) == "url" {
+					fmt.Fprintf(out, "%s\n", binding.GetSelfLink())
+					return nil
+				}
+				printer, err := printFlags.ToPrinter()
+				if err != nil {
+					return err
+				}
+				obj, err := printableObject(client, binding)
+				if err != nil {
+					return err
+				}
+				return printer.PrintObj(obj, out)
+			}
+
+			dw := printers.NewPrefixWriter(out)
+
+			printDetails, err := cmd.Flags().GetBool("verbose")
+			if err != nil {
+				return err
+			}
+
+			kamelets := bindingKamelets(p.Context, client, binding)
+			writeBinding(dw, binding, kamelets, printDetails)
+			dw.WriteLine()
+			if err := dw.Flush(); err != nil {
+				return err
+			}
+
+			// Condition info
+			commands.WriteConditions(dw, asBindingApiConditions(binding.Status.Conditions), printDetails)
+			if err := dw.Flush(); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	commands.AddNamespaceFlags(flags, false)
+	flags.BoolP("verbose", "v", false, "More output.")
+	printFlags.AddFlags(cmd)
+	cmd.Flag("output").Usage = fmt.Sprintf("Output format. One of: %s.", strings.Join(append(printFlags.AllowedFormats(), "url"), "|"))
+	return cmd
+}
+
+// bindingKamelets fetches the Kamelets referenced by the endpoints of the binding. Kamelets that can not be
+// fetched are left out, their properties are masked by name only.
+func bindingKamelets(ctx context.Context, client camelkv1alpha1.CamelV1alpha1Interface, binding *v1alpha1.KameletBinding) map[types.NamespacedName]*v1alpha1.Kamelet {
+	steps, _ := bindingSteps(binding)
+	endpoints := append([]v1alpha1.Endpoint{binding.Spec.Source}, steps...)
+	endpoints = append(endpoints, binding.Spec.Sink)
+
+	kamelets := make(map[types.NamespacedName]*v1alpha1.Kamelet)
+	for _, endpoint := range endpoints {
+		if !isKameletEndpoint(endpoint) {
+			continue
+		}
+		key := kameletKey(endpoint, binding.Namespace)
+		if _, ok := kamelets[key]; ok {
+			continue
+		}
+		kamelet, err := client.Kamelets(key.Namespace).Get(ctx, key.Name, v1.GetOptions{})
+		if err != nil {
+			kamelet = nil
+		}
+		kamelets[key] = kamelet
+	}
+	return kamelets
+}
+
+// kameletKey returns the namespace and name of the Kamelet referenced by the endpoint
+func kameletKey(endpoint v1alpha1.Endpoint, bindingNamespace string) types.NamespacedName {
+	key := types.NamespacedName{Namespace: endpoint.Ref.Namespace, Name: endpoint.Ref.Name}
+	if key.Namespace == "" {
+		key.Namespace = bindingNamespace
+	}
+	return key
+}
+
+func writeBinding(dw printers.PrefixWriter, binding *v1alpha1.KameletBinding, kamelets map[types.NamespacedName]*v1alpha1.Kamelet, printDetails bool) {
+	kameletOf := func(endpoint v1alpha1.Endpoint) *v1alpha1.Kamelet {
+		if !isKameletEndpoint(endpoint) {
+			return nil
+		}
+		return kamelets[kameletKey(endpoint, binding.Namespace)]
+	}
+
+	commands.WriteMetadata(dw, &binding.ObjectMeta, printDetails)
+	dw.WriteAttribute("Phase", string(binding.Status.Phase))
+
+	dw.WriteLine()
+	writeBindingSource(dw, binding.Spec.Source, kameletOf(binding.Spec.Source))
+
+	steps, _ := bindingSteps(binding)
+	for i, step := range steps {
+		dw.WriteLine()
+		writeBindingStep(dw, i+1, step, kameletOf(step))
+	}
+
+	dw.WriteLine()
+	writeBindingSink(dw, binding.Spec.Sink, kameletOf(binding.Spec.Sink))
+
+	if handler := errorHandlerOf(binding); handler != nil {
+		dw.WriteLine()
+		writeBindingErrorHandler(dw, handler)
+	}
+
+	if binding.Spec.Integration != nil {
+		dw.WriteLine()
+		writeBindingIntegration(dw, binding, printDetails)
+	}
+}
+
+func writeBindingSource(dw printers.PrefixWriter, source v1alpha1.Endpoint, kamelet *v1alpha1.Kamelet) {
+	section := dw.WriteAttribute("Source", "")
+	writeEndpointRef(section, source)
+
+	writeEndpointProperties(section, endpointPropertyValues(source.Properties), kamelet)
+	writeEventTypes(section, source.Types)
+}
+
+func writeBindingStep(dw printers.PrefixWriter, index int, step v1alpha1.Endpoint, kamelet *v1alpha1.Kamelet) {
+	section := dw.WriteAttribute(fmt.Sprintf("Step %d", index), "")
+	writeEndpointRef(section, step)
+
+	writeEndpointProperties(section, endpointPropertyValues(step.Properties), kamelet)
+}
+
+func writeBindingSink(dw printers.PrefixWriter, sink v1alpha1.Endpoint, kamelet *v1alpha1.Kamelet) {
+	section := dw.WriteAttribute("Sink", "")
+	writeEndpointRef(section, sink)
+
+	props := endpointPropertyValues(sink.Properties)
+	specVersion := props[cloudEventsSpecVersionProperty]
+	ceType := props[cloudEventsTypeProperty]
+	delete(props, cloudEventsSpecVersionProperty)
+	delete(props, cloudEventsTypeProperty)
+
+	overrides := make(map[string]string)
+	for key, value := range props {
+		if strings.HasPrefix(key, cloudEventsOverridePrefix) {
+			overrides[strings.TrimPrefix(key, cloudEventsOverridePrefix)] = value
+			delete(props, key)
+		}
+	}
+	writeEndpointProperties(section, props, kamelet)
+	writeEventTypes(section, sink.Types)
+
+	if specVersion == "" && ceType == "" && len(overrides) == 0 {
+		return
+	}
+
+	ceSection := section.WriteAttribute("CloudEvents", "")
+	if specVersion != "" {
+		ceSection.WriteAttribute("Spec Version", specVersion)
+	}
+	if ceType != "" {
+		ceSection.WriteAttribute("Type", ceType)
+	}
+	if len(overrides) > 0 {
+		overrideSection := ceSection.WriteAttribute("Overrides", "")
+		for _, key := range sortedKeys(overrides) {
+			overrideSection.WriteAttribute(key, overrides[key])
+		}
+	}
+}
+
+func writeBindingErrorHandler(dw printers.PrefixWriter, handler *bindingErrorHandler) {
+	section := dw.WriteAttribute("Error Handler", "")
+	section.WriteAttribute("Type", handler.Type)
+	if handler.Sink != nil {
+		writeEndpointRef(section, *handler.Sink)
+	}
+	if handler.MaxRedeliveries != "" {
+		section.WriteAttribute("Max Redeliveries", handler.MaxRedeliveries)
+	}
+	if handler.RedeliveryDelay != "" {
+		delay := handler.RedeliveryDelay
+		if millis, err := strconv.ParseInt(delay, 10, 64); err == nil {
+			delay = (time.Duration(millis) * time.Millisecond).String()
+		}
+		section.WriteAttribute("Redelivery Delay", delay)
+	}
+}
+
+func writeEndpointRef(dw printers.PrefixWriter, endpoint v1alpha1.Endpoint) {
+	if endpoint.Ref != nil {
+		if endpoint.Ref.Kind == v1alpha1.KameletKind {
+			dw.WriteAttribute("Kamelet", endpoint.Ref.Name)
+		} else {
+			dw.WriteAttribute("Kind", endpoint.Ref.Kind)
+			dw.WriteAttribute("Name", endpoint.Ref.Name)
+		}
+		if endpoint.Ref.Namespace != "" {
+			dw.WriteAttribute("Namespace", endpoint.Ref.Namespace)
+		}
+		if endpoint.Ref.APIVersion != "" {
+			dw.WriteAttribute("APIVersion", endpoint.Ref.APIVersion)
+		}
+	}
+	if endpoint.URI != nil {
+		dw.WriteAttribute("URI", *endpoint.URI)
+	}
+}
+
+// endpointPropertyValues returns the properties of the endpoint in the same notation as accepted by --property
+func endpointPropertyValues(props *v1alpha1.EndpointProperties) map[string]string {
+	values := make(map[string]string)
+	decoded, err := decodeEndpointProperties(props)
+	if err != nil {
+		return values
+	}
+	for key, value := range decoded {
+		values[key] = propertyArgValue(value)
+	}
+	return values
+}
+
+func writeEndpointProperties(dw printers.PrefixWriter, props map[string]string, kamelet *v1alpha1.Kamelet) {
+	if len(props) == 0 {
+		return
+	}
+
+	section := dw.WriteAttribute("Properties", "")
+	for _, key := range sortedKeys(props) {
+		section.WriteAttribute(key, maskPropertyValue(kamelet, key, props[key]))
+	}
+}
+
+func writeBindingIntegration(dw printers.PrefixWriter, binding *v1alpha1.KameletBinding, printDetails bool) {
+	integration := binding.Spec.Integration
+	section := dw.WriteAttribute("Integration", "")
+	if integration.Profile != "" {
+		section.WriteAttribute("Profile", string(integration.Profile))
+	}
+	if integration.Replicas != nil {
+		section.WriteAttribute("Replicas", strconv.Itoa(int(*integration.Replicas)))
+	}
+	if integration.ServiceAccountName != "" {
+		section.WriteAttribute("Service Account", integration.ServiceAccountName)
+	}
+
+	traits := make([]string, 0, len(integration.Traits))
+	for name := range integration.Traits {
+		traits = append(traits, name)
+	}
+	sort.Strings(traits)
+	commands.WriteSliceDesc(section, traits, "Traits", printDetails)
+	commands.WriteSliceDesc(section, integration.Dependencies, "Dependencies", printDetails)
+
+	configuration := make([]string, 0, len(integration.Configuration))
+	for _, c := range integration.Configuration {
+		configuration = append(configuration, fmt.Sprintf("%s:%s", c.Type, c.Value))
+	}
+	commands.WriteSliceDesc(section, configuration, "Configuration", printDetails)
+}
+
+// maskPropertyValue hides the value of properties the Kamelet declares as password. Properties unknown to the
+// Kamelet definition are hidden when their name looks like they are holding secret information.
+func maskPropertyValue(kamelet *v1alpha1.Kamelet, key string, value string) string {
+	if kamelet != nil && kamelet.Spec.Definition != nil {
+		if prop, ok := kamelet.Spec.Definition.Properties[key]; ok {
+			if isPasswordProperty(prop) {
+				return maskedPropertyValue
+			}
+			return value
+		}
+	}
+	if secretPropertyName.MatchString(key) {
+		return maskedPropertyValue
+	}
+	return value
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func asBindingApiConditions(conditions []v1alpha1.KameletBindingCondition) apis.Conditions {
+	var aConditions apis.Conditions
+
+	for _, condition := range conditions {
+		aConditions = append(aConditions, apis.Condition{
+			Type:   apis.ConditionType(condition.Type),
+			Status: condition.Status,
+			LastTransitionTime: apis.VolatileTime{
+				Inner: condition.LastTransitionTime,
+			},
+			Reason:  condition.Reason,
+			Message: condition.Message,
+		})
+	}
+
+	return aConditions
+}
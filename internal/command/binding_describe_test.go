@@ -0,0 +1,223 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	"knative.dev/client-pkg/pkg/commands"
+	"knative.dev/client-pkg/pkg/util"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBindingDescribeSetup(t *testing.T) {
+	p := KameletPluginParams{
+		Context: context.TODO(),
+	}
+
+	describeCmd := newBindingDescribeCommand(&p)
+	assert.Equal(t, describeCmd.Use, "describe NAME")
+	assert.Equal(t, describeCmd.Short, "Show details of given Kamelet binding.")
+	assert.Assert(t, describeCmd.RunE != nil)
+}
+
+func TestBindingDescribeErrorCaseMissingArgument(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	_, err := runBindingDescribeCmd(mockClient)
+	assert.Error(t, err, "'kn-source-kamelet binding describe' requires the binding name as argument")
+	recorder.Validate()
+}
+
+func TestBindingDescribeErrorCaseNotFound(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.GetKameletBinding(&v1alpha1.KameletBinding{}, errors.New("not found"))
+
+	_, err := runBindingDescribeCmd(mockClient, "k1-to-broker")
+	assert.Error(t, err, "not found")
+	recorder.Validate()
+}
+
+func TestBindingDescribeOutput(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	binding := createKameletBinding("k1-to-broker", "k1", &corev1.ObjectReference{
+		Kind:       "Broker",
+		APIVersion: eventingv1.SchemeGroupVersion.String(),
+		Namespace:  "default",
+		Name:       "b1",
+	})
+	binding.Spec.Source.Properties.RawMessage = []byte(`{"k1_prop":"foo","accessKey":"my-secret-key","k1_connection":"user:pass",` +
+		`"routingKey":"events","batchSize":1000000,"offset":9007199254740993,"tags":["a","b"]}`)
+	binding.Spec.Sink.Properties.RawMessage = []byte("{\"ce.override.subject\":\"custom\",\"cloudEventsSpecVersion\":\"1.0\",\"cloudEventsType\":\"custom-type\"}")
+	binding.Status = statusReady()
+	recorder.GetKameletBinding(binding, nil)
+
+	// the Kamelet declares which of its properties hold secrets
+	kamelet := createKamelet("k1")
+	kamelet.Spec.Definition.Properties["k1_connection"] = v1alpha1.JSONSchemaProps{Type: "string", Format: "password"}
+	recorder.Get(kamelet, nil)
+
+	output, err := runBindingDescribeCmd(mockClient, "k1-to-broker")
+	assert.NilError(t, err)
+
+	outputLines := strings.Split(output, "\n")
+
+	assert.Check(t, util.ContainsAll(outputLines[0], "Name:", "k1-to-broker"))
+	assert.Check(t, util.ContainsAll(outputLines[1], "Namespace:", "default"))
+	assert.Check(t, util.ContainsAll(outputLines[2], "Age:"))
+	assert.Check(t, util.ContainsAll(outputLines[3], "Phase:", "Ready"))
+
+	assert.Check(t, util.ContainsAll(outputLines[5], "Source:"))
+	assert.Check(t, util.ContainsAll(outputLines[6], "Kamelet:", "k1"))
+	assert.Check(t, util.ContainsAll(outputLines[7], "Namespace:", "default"))
+	assert.Check(t, util.ContainsAll(outputLines[8], "APIVersion:", "camel.apache.org/v1alpha1"))
+	assert.Check(t, util.ContainsAll(outputLines[9], "Properties:"))
+	assert.Check(t, util.ContainsAll(outputLines[10], "accessKey:", "*****"))
+	assert.Check(t, !strings.Contains(output, "my-secret-key"))
+	assert.Check(t, util.ContainsAll(outputLines[11], "batchSize:", "1000000"))
+	assert.Check(t, util.ContainsAll(outputLines[12], "k1_connection:", "*****"))
+	assert.Check(t, !strings.Contains(output, "user:pass"))
+	assert.Check(t, util.ContainsAll(outputLines[13], "k1_prop:", "foo"))
+	assert.Check(t, util.ContainsAll(outputLines[14], "offset:", "9007199254740993"))
+	assert.Check(t, util.ContainsAll(outputLines[15], "routingKey:", "events"))
+	assert.Check(t, util.ContainsAll(outputLines[16], "tags:", `["a","b"]`))
+
+	assert.Check(t, util.ContainsAll(outputLines[18], "Sink:"))
+	assert.Check(t, util.ContainsAll(outputLines[19], "Kind:", "Broker"))
+	assert.Check(t, util.ContainsAll(outputLines[20], "Name:", "b1"))
+	assert.Check(t, util.ContainsAll(outputLines[21], "Namespace:", "default"))
+	assert.Check(t, util.ContainsAll(outputLines[22], "APIVersion:", "eventing.knative.dev/v1"))
+	assert.Check(t, util.ContainsAll(outputLines[23], "CloudEvents:"))
+	assert.Check(t, util.ContainsAll(outputLines[24], "Spec Version:", "1.0"))
+	assert.Check(t, util.ContainsAll(outputLines[25], "Type:", "custom-type"))
+	assert.Check(t, util.ContainsAll(outputLines[26], "Overrides:"))
+	assert.Check(t, util.ContainsAll(outputLines[27], "subject:", "custom"))
+
+	assert.Check(t, util.ContainsAll(outputLines[29], "Conditions:"))
+	assert.Check(t, util.ContainsAll(outputLines[30], "OK", "TYPE", "AGE", "REASON"))
+	assert.Check(t, util.ContainsAll(outputLines[31], "++", "Ready"))
+
+	recorder.Validate()
+}
+
+func TestBindingDescribeIntegrationOutput(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	uri := "https://example.com/events"
+	replicas := int32(2)
+	binding := createKameletBinding("k1-to-uri", "k1", nil)
+	binding.Spec.Sink.URI = &uri
+	binding.Spec.Integration = &camelv1.IntegrationSpec{
+		Profile:  camelv1.TraitProfileKnative,
+		Replicas: &replicas,
+		Traits: map[string]camelv1.TraitSpec{
+			"prometheus": {},
+			"container":  {},
+		},
+	}
+	recorder.GetKameletBinding(binding, nil)
+	recorder.Get(createKamelet("k1"), nil)
+
+	output, err := runBindingDescribeCmd(mockClient, "k1-to-uri")
+	assert.NilError(t, err)
+
+	assert.Check(t, util.ContainsAll(output, "Sink:", "URI:", uri))
+	assert.Check(t, util.ContainsAll(output, "Integration:", "Profile:", "Knative", "Replicas:", "2", "Traits:", "container, prometheus"))
+
+	recorder.Validate()
+}
+
+func TestBindingDescribeErrorHandlerOutput(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	binding := createKameletBinding("k1-to-channel", "k1", channelRef("default"))
+	binding.Spec.Integration = &camelv1.IntegrationSpec{
+		Configuration: []camelv1.ConfigurationSpec{
+			{Type: "property", Value: "camel.beans.defaultErrorHandler=#class:org.apache.camel.builder.DeadLetterChannelBuilder"},
+			{Type: "property", Value: "camel.beans.defaultErrorHandler.deadLetterUri=knative:channel/errors?apiVersion=messaging.knative.dev%2Fv1&kind=Channel"},
+			{Type: "property", Value: "camel.beans.defaultErrorHandler.maximumRedeliveries=3"},
+			{Type: "property", Value: "camel.beans.defaultErrorHandler.redeliveryDelay=2000"},
+		},
+	}
+	recorder.GetKameletBinding(binding, nil)
+	recorder.Get(createKamelet("k1"), nil)
+
+	output, err := runBindingDescribeCmd(mockClient, "k1-to-channel")
+	assert.NilError(t, err)
+
+	assert.Check(t, util.ContainsAll(output, "Error Handler:", "Type:", "sink", "Kind:", "Channel", "Name:", "errors",
+		"APIVersion:", "messaging.knative.dev/v1", "Max Redeliveries:", "3", "Redelivery Delay:", "2s"))
+
+	recorder.Validate()
+}
+
+func TestBindingDescribeYAML(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	binding := createKameletBinding("k1-to-broker", "k1", &corev1.ObjectReference{
+		Kind:       "Broker",
+		APIVersion: eventingv1.SchemeGroupVersion.String(),
+		Namespace:  "default",
+		Name:       "b1",
+	})
+	recorder.GetKameletBinding(binding, nil)
+
+	output, err := runBindingDescribeCmd(mockClient, "k1-to-broker", "-o", "yaml")
+	assert.NilError(t, err)
+
+	assert.Check(t, util.ContainsAll(output, "apiVersion: camel.apache.org/v1alpha1", "kind: KameletBinding", "name: k1-to-broker"))
+
+	recorder.Validate()
+}
+
+func runBindingDescribeCmd(c *client.MockClient, options ...string) (string, error) {
+	p := KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewKameletClient: func() (camelkv1alpha1.CamelV1alpha1Interface, error) {
+			return c, nil
+		},
+	}
+
+	describeCmd, _, output := commands.CreateSourcesTestKnCommand(newBindingDescribeCommand(&p), p.KnParams)
+
+	args := []string{"describe"}
+	args = append(args, options...)
+	describeCmd.SetArgs(args)
+	err := describeCmd.Execute()
+
+	return output.String(), err
+}
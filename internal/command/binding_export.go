@@ -0,0 +1,487 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/client-pkg/pkg/commands"
+	knerrors "knative.dev/client-pkg/pkg/errors"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	exportFormatYAML      = "yaml"
+	exportFormatKustomize = "kustomize"
+	exportFormatCommand   = "command"
+)
+
+// server populated metadata fields removed on export
+var exportedMetadataFields = []string{"creationTimestamp", "deletionTimestamp", "deletionGracePeriodSeconds", "generation",
+	"managedFields", "namespace", "ownerReferences", "resourceVersion", "selfLink", "uid"}
+
+var bindingExportExample = `
+  # Export a Kamelet binding as YAML manifest
+  kn source kamelet binding export NAME
+
+  # Export all Kamelet bindings of the namespace as Kustomize base
+  kn source kamelet binding export --all --format kustomize --output-dir ./base
+
+  # Print the command that recreates the Kamelet binding
+  kn source kamelet binding export NAME --format command`
+
+// newBindingExportCommand implements 'kn-source-kamelet binding export' command
+func newBindingExportCommand(p *KameletPluginParams) *cobra.Command {
+	var all bool
+	var format string
+	var outputDir string
+
+	cmd := &cobra.Command{
+		Use:     "export NAME|--all",
+		Short:   "Export Kamelet bindings as manifests, Kustomize base or command.",
+		Example: bindingExportExample,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if (len(args) != 1 && !all) || (len(args) > 0 && all) {
+				return errors.New("'kn-source-kamelet binding export' requires the binding name as argument or --all")
+			}
+
+			switch format {
+			case exportFormatYAML, exportFormatCommand:
+			case exportFormatKustomize:
+				if outputDir == "" {
+					return errors.New("--format kustomize requires an output directory given with --output-dir")
+				}
+			default:
+				return fmt.Errorf("unsupported export format %q - please use one of yaml, kustomize, command", format)
+			}
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := p.NewKameletClient()
+			if err != nil {
+				return err
+			}
+
+			var bindings []v1alpha1.KameletBinding
+			if all {
+				list, err := client.KameletBindings(namespace).List(p.Context, v1.ListOptions{})
+				if err != nil {
+					return knerrors.GetError(err)
+				}
+				bindings = list.Items
+				sort.Slice(bindings, func(i, j int) bool {
+					return bindings[i].Name < bindings[j].Name
+				})
+			} else {
+				binding, err := client.KameletBindings(namespace).Get(p.Context, args[0], v1.GetOptions{})
+				if err != nil {
+					return knerrors.GetError(err)
+				}
+				bindings = append(bindings, *binding)
+			}
+
+			// manifests of clusters serving Pipes are exported as Pipes
+			pipes := servesPipes(client)
+			out := cmd.OutOrStdout()
+			switch format {
+			case exportFormatCommand:
+				for i := range bindings {
+					var trigger *eventingv1.Trigger
+					if bindings[i].Labels[bindingLabel] != "" {
+						triggerClient, err := p.NewEventingClient(bindings[i].Namespace)
+						if err != nil {
+							return err
+						}
+						trigger, err = bindingTriggerOf(triggerClient, p.Context, &bindings[i])
+						if err != nil {
+							return knerrors.GetError(err)
+						}
+					}
+					command, err := bindingCommand(&bindings[i], namespace, trigger)
+					if err != nil {
+						return err
+					}
+					_, _ = fmt.Fprintln(out, command)
+				}
+				return nil
+			case exportFormatKustomize:
+				return writeKustomizeBase(bindings, namespace, pipes, outputDir, out)
+			}
+
+			if outputDir != "" {
+				_, err := writeBindingManifests(bindings, namespace, pipes, outputDir, out)
+				return err
+			}
+			for i := range bindings {
+				data, err := exportBinding(&bindings[i], namespace, pipes)
+				if err != nil {
+					return err
+				}
+				if i > 0 {
+					_, _ = fmt.Fprintln(out, "---")
+				}
+				_, _ = out.Write(data)
+			}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	commands.AddNamespaceFlags(flags, false)
+	flags.BoolVar(&all, "all", false, "Export all Kamelet bindings of the namespace.")
+	flags.StringVar(&format, "format", exportFormatYAML, "Export format. One of: yaml|kustomize|command.")
+	flags.StringVar(&outputDir, "output-dir", "", "Directory to write a manifest file per binding to instead of printing the manifests.")
+	return cmd
+}
+
+// exportBinding returns the YAML manifest of the binding without status and server populated metadata. Object
+// references into the namespace of the binding lose their namespace, so the manifest can be applied to any namespace.
+// With pipes set the manifest is a camel.apache.org/v1 Pipe.
+func exportBinding(binding *v1alpha1.KameletBinding, namespace string, pipes bool) ([]byte, error) {
+	content, err := exportContent(binding, namespace, pipes)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(content)
+}
+
+// exportContent returns the unstructured content of the manifest written by exportBinding
+func exportContent(binding *v1alpha1.KameletBinding, namespace string, pipes bool) (map[string]interface{}, error) {
+	exported := binding.DeepCopy()
+	updateKameletBindingGvk(exported)
+	delete(exported.Annotations, corev1.LastAppliedConfigAnnotation)
+	steps, err := bindingSteps(exported)
+	if err != nil {
+		return nil, err
+	}
+	endpoints := []*v1alpha1.Endpoint{&exported.Spec.Source, &exported.Spec.Sink}
+	for i := range steps {
+		endpoints = append(endpoints, &steps[i])
+	}
+	for _, endpoint := range endpoints {
+		if endpoint.Ref != nil && (endpoint.Ref.Namespace == namespace || endpoint.Ref.Namespace == exported.Namespace) {
+			endpoint.Ref.Namespace = ""
+		}
+	}
+	if err := setBindingSteps(exported, steps); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(exported)
+	if err != nil {
+		return nil, err
+	}
+	content := make(map[string]interface{})
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, err
+	}
+	delete(content, "status")
+	if pipes {
+		setCamelV1Kind(content, pipeKind)
+	} else {
+		stepsToSpec(content)
+		errorHandlerToSpec(content)
+	}
+	if metadata, ok := content["metadata"].(map[string]interface{}); ok {
+		for _, field := range exportedMetadataFields {
+			delete(metadata, field)
+		}
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok && len(annotations) == 0 {
+			delete(metadata, "annotations")
+		}
+	}
+	pruned, _ := pruneEmpty(content).(map[string]interface{})
+	return pruned, nil
+}
+
+func writeBindingManifests(bindings []v1alpha1.KameletBinding, namespace string, pipes bool, outputDir string, out io.Writer) ([]string, error) {
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return nil, err
+	}
+	files := make([]string, 0, len(bindings))
+	for i := range bindings {
+		data, err := exportBinding(&bindings[i], namespace, pipes)
+		if err != nil {
+			return nil, err
+		}
+		file := bindings[i].Name + ".yaml"
+		if err := os.WriteFile(filepath.Join(outputDir, file), data, 0o644); err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+		_, _ = fmt.Fprintf(out, "kamelet binding %q exported to %s\n", bindings[i].Name, filepath.Join(outputDir, file))
+	}
+	return files, nil
+}
+
+// writeKustomizeBase writes the binding manifests and a kustomization listing them to the output directory
+func writeKustomizeBase(bindings []v1alpha1.KameletBinding, namespace string, pipes bool, outputDir string, out io.Writer) error {
+	files, err := writeBindingManifests(bindings, namespace, pipes, outputDir, out)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(map[string]interface{}{
+		"apiVersion": "kustomize.config.k8s.io/v1beta1",
+		"kind":       "Kustomization",
+		"resources":  files,
+	})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outputDir, "kustomization.yaml"), data, 0o644)
+}
+
+// bindingCommand returns the 'binding create' command line that recreates the given binding and its Trigger
+func bindingCommand(binding *v1alpha1.KameletBinding, namespace string, trigger *eventingv1.Trigger) (string, error) {
+	args := []string{"kn", "source", "kamelet", "binding", "create", binding.Name}
+	if binding.Namespace != "" && binding.Namespace != namespace {
+		args = append(args, "--namespace", binding.Namespace)
+	}
+
+	var kameletEndpoint *v1alpha1.Endpoint
+	switch {
+	case isKameletEndpoint(binding.Spec.Source):
+		kameletEndpoint = &binding.Spec.Source
+		args = append(args, "--kamelet", kameletEndpoint.Ref.Name)
+		sink, err := endpointExpression(binding.Spec.Sink, binding.Namespace)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, "--sink", sink)
+	case isKameletEndpoint(binding.Spec.Sink):
+		kameletEndpoint = &binding.Spec.Sink
+		args = append(args, "--kamelet", kameletEndpoint.Ref.Name)
+		source, err := endpointExpression(binding.Spec.Source, binding.Namespace)
+		if err != nil {
+			return "", err
+		}
+		args = append(args, "--source", source)
+	default:
+		return "", fmt.Errorf("kamelet binding %q does not reference a Kamelet", binding.Name)
+	}
+
+	props, err := decodeEndpointProperties(kameletEndpoint.Properties)
+	if err != nil {
+		return "", err
+	}
+	for _, key := range sortedKeys(props) {
+		value := propertyArgValue(props[key])
+		if strings.HasPrefix(value, "@") {
+			// escape values that would be read from a file otherwise
+			value = "@" + value
+		}
+		args = append(args, "--property", key+"="+value)
+	}
+
+	steps, err := bindingSteps(binding)
+	if err != nil {
+		return "", err
+	}
+	for i, step := range steps {
+		if !isKameletEndpoint(step) {
+			return "", fmt.Errorf("step %d of kamelet binding %q can not be expressed as command option", i+1, binding.Name)
+		}
+		args = append(args, "--step", step.Ref.Name)
+		stepProps, err := decodeEndpointProperties(step.Properties)
+		if err != nil {
+			return "", err
+		}
+		for _, key := range sortedKeys(stepProps) {
+			value := propertyArgValue(stepProps[key])
+			args = append(args, "--step-property", fmt.Sprintf("%s%d.%s=%s", stepPropertyPrefix, i+1, key, value))
+		}
+	}
+
+	if kameletEndpoint == &binding.Spec.Source {
+		sinkProps, err := decodeEndpointProperties(binding.Spec.Sink.Properties)
+		if err != nil {
+			return "", err
+		}
+		for _, key := range sortedKeys(sinkProps) {
+			value := propertyArgValue(sinkProps[key])
+			switch {
+			case key == cloudEventsSpecVersionProperty:
+				args = append(args, "--ce-spec", value)
+			case key == cloudEventsTypeProperty:
+				args = append(args, "--ce-type", value)
+			case strings.HasPrefix(key, cloudEventsOverridePrefix):
+				args = append(args, "--ce-override", strings.TrimPrefix(key, cloudEventsOverridePrefix)+"="+value)
+			default:
+				return "", fmt.Errorf("sink property %q of kamelet binding %q can not be expressed as command option", key, binding.Name)
+			}
+		}
+	}
+
+	for _, slot := range eventSlots {
+		eventType, ok := kameletEndpoint.Types[slot]
+		switch {
+		case !ok:
+			continue
+		case eventType.Schema != nil:
+			return "", fmt.Errorf("schema of the %s slot of kamelet binding %q can not be expressed as command option", slot, binding.Name)
+		case eventType.MediaType == "":
+			continue
+		case slot == v1alpha1.EventSlotOut:
+			args = append(args, "--out-media-type", eventType.MediaType)
+		case slot == v1alpha1.EventSlotIn:
+			args = append(args, "--in-media-type", eventType.MediaType)
+		default:
+			return "", fmt.Errorf("media type of the %s slot of kamelet binding %q can not be expressed as command option", slot, binding.Name)
+		}
+	}
+
+	if binding.Annotations[eventTypeAnnotation] != "" {
+		args = append(args, "--register-event-type")
+	}
+
+	if trigger != nil {
+		subscriber, err := subscriberExpression(trigger, binding.Namespace)
+		if err != nil {
+			return "", fmt.Errorf("subscriber of trigger %q can not be expressed as command option: %v", trigger.Name, err)
+		}
+		args = append(args, "--subscriber", subscriber)
+		if trigger.Spec.Filter != nil {
+			for _, key := range sortedKeys(trigger.Spec.Filter.Attributes) {
+				args = append(args, "--filter", key+"="+trigger.Spec.Filter.Attributes[key])
+			}
+		}
+	}
+
+	integrationArgs, err := integrationCommandArgs(binding)
+	if err != nil {
+		return "", err
+	}
+	args = append(args, integrationArgs...)
+
+	for i := range args {
+		args[i] = shellQuote(args[i])
+	}
+	return strings.Join(args, " "), nil
+}
+
+// integrationCommandArgs returns the error handler options as well as the --trait, --profile, --dependency,
+// --service-account and --replicas options for the integration settings of the binding
+func integrationCommandArgs(binding *v1alpha1.KameletBinding) ([]string, error) {
+	var args []string
+	if handler := errorHandlerOf(binding); handler != nil {
+		handlerArgs, err := errorHandlerCommandArgs(handler, binding.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, handlerArgs...)
+	}
+	if binding.Spec.Integration == nil {
+		return args, nil
+	}
+	spec := binding.Spec.Integration.DeepCopy()
+	if _, ok := binding.Annotations[errorHandlerAnnotation]; !ok {
+		if err := removeErrorHandler(spec); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(spec.Sources) > 0 || len(spec.Flows) > 0 || len(spec.Resources) > 0 || spec.Kit != "" ||
+		len(spec.Configuration) > 0 || len(spec.Repositories) > 0 {
+		return nil, fmt.Errorf("integration settings of kamelet binding %q can not be expressed as command options", binding.Name)
+	}
+
+	for _, name := range sortedKeys(spec.Traits) {
+		configuration, err := traitConfiguration(name, spec.Traits[name])
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range sortedKeys(configuration) {
+			values, ok := configuration[key].([]interface{})
+			if !ok {
+				values = []interface{}{configuration[key]}
+			}
+			for _, value := range values {
+				args = append(args, "--trait", name+"."+key+"="+propertyArgValue(value))
+			}
+		}
+	}
+	if spec.Profile != "" {
+		args = append(args, "--profile", string(spec.Profile))
+	}
+	for _, dependency := range spec.Dependencies {
+		args = append(args, "--dependency", dependency)
+	}
+	if spec.ServiceAccountName != "" {
+		args = append(args, "--service-account", spec.ServiceAccountName)
+	}
+	if spec.Replicas != nil {
+		args = append(args, "--replicas", strconv.Itoa(int(*spec.Replicas)))
+	}
+	return args, nil
+}
+
+// endpointExpression returns the sink expression for the given endpoint as accepted by --sink and --source
+func endpointExpression(endpoint v1alpha1.Endpoint, namespace string) (string, error) {
+	if endpoint.URI != nil {
+		return *endpoint.URI, nil
+	}
+	if endpoint.Ref == nil {
+		return "", errors.New("endpoint has neither a reference nor an URI")
+	}
+
+	name := endpoint.Ref.Name
+	if endpoint.Ref.Namespace != "" && endpoint.Ref.Namespace != namespace {
+		name = endpoint.Ref.Namespace + "/" + name
+	}
+	for alias, sinkType := range sinkTypes {
+		if sinkType.Kind == endpoint.Ref.Kind && sinkType.APIVersion == endpoint.Ref.APIVersion {
+			return alias + ":" + name, nil
+		}
+	}
+	return endpoint.Ref.APIVersion + ":" + endpoint.Ref.Kind + ":" + name, nil
+}
+
+// propertyArgValue returns the value as given on the command line, non string values are JSON encoded
+func propertyArgValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// shellQuote quotes the argument with single quotes when it contains characters interpreted by the shell
+func shellQuote(arg string) string {
+	if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_.:/=@,+%") == "" {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'"'"'`) + "'"
+}
@@ -0,0 +1,346 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/client-pkg/pkg/commands"
+	clienteventingv1 "knative.dev/client-pkg/pkg/eventing/v1"
+	"knative.dev/client-pkg/pkg/util"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBindingExportYAML(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	binding := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	binding.UID = "4711"
+	binding.ResourceVersion = "42"
+	binding.CreationTimestamp = v1.Now()
+	binding.ManagedFields = []v1.ManagedFieldsEntry{{Manager: "kn"}}
+	binding.Labels = map[string]string{"team": "integration"}
+	binding.Status = statusReady()
+	recorder.GetKameletBinding(binding, nil)
+
+	output, err := runBindingExportCmd(mockClient, "k1-to-channel")
+	assert.NilError(t, err)
+	assert.Equal(t, output, `apiVersion: camel.apache.org/v1alpha1
+kind: KameletBinding
+metadata:
+  labels:
+    team: integration
+  name: k1-to-channel
+spec:
+  sink:
+    ref:
+      apiVersion: messaging.knative.dev/v1
+      kind: Channel
+      name: test
+  source:
+    properties:
+      k1_prop: foo
+    ref:
+      apiVersion: camel.apache.org/v1alpha1
+      kind: Kamelet
+      name: k1
+`)
+
+	recorder.Validate()
+}
+
+func TestBindingExportYAMLSteps(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	binding := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	step := binding.Spec.Source.DeepCopy()
+	step.Ref.Name = "a1"
+	step.Properties.RawMessage = []byte(`{"a1_prop":"bar"}`)
+	assert.NilError(t, setBindingSteps(binding, []v1alpha1.Endpoint{*step}))
+	recorder.GetKameletBinding(binding, nil)
+
+	output, err := runBindingExportCmd(mockClient, "k1-to-channel", "-n", namespace)
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kind: KameletBinding", `  steps:
+  - properties:
+      a1_prop: bar
+    ref:
+      apiVersion: camel.apache.org/v1alpha1
+      kind: Kamelet
+      name: a1
+`))
+	assert.Assert(t, util.ContainsNone(output, stepsAnnotation, "annotations:", "namespace:"))
+
+	recorder.Validate()
+}
+
+func TestBindingExportCommand(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	binding := createKameletBindingInNamespace("k1-to-broker", "k1", namespace, &corev1.ObjectReference{
+		Kind:       "Broker",
+		APIVersion: "eventing.knative.dev/v1",
+		Namespace:  "other",
+		Name:       "default",
+	})
+	binding.Spec.Source.Properties.RawMessage = []byte(`{"k1_prop":"hello world","k1_optional":true,"k1_file":"@file"}`)
+	binding.Spec.Sink.Properties.RawMessage = []byte(`{"cloudEventsType":"org.example.event","ce.override.ce-source":"example"}`)
+	recorder.GetKameletBinding(binding, nil)
+
+	output, err := runBindingExportCmd(mockClient, "k1-to-broker", "--format", "command")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "kn source kamelet binding create k1-to-broker --kamelet k1 --sink broker:other/default "+
+		"--property k1_file=@@file --property k1_optional=true --property 'k1_prop=hello world' "+
+		"--ce-override ce-source=example --ce-type org.example.event\n")
+
+	recorder.Validate()
+}
+
+func TestBindingExportCommandSteps(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	binding := createKameletBindingInNamespace("k1-to-broker", "k1", namespace, brokerRef(namespace))
+	step := binding.Spec.Source.DeepCopy()
+	step.Ref.Name = "a1"
+	step.Properties.RawMessage = []byte(`{"a1_prop":"hello world"}`)
+	assert.NilError(t, setBindingSteps(binding, []v1alpha1.Endpoint{*step}))
+	recorder.GetKameletBinding(binding, nil)
+
+	output, err := runBindingExportCmd(mockClient, "k1-to-broker", "--format", "command", "-n", namespace)
+	assert.NilError(t, err)
+	assert.Equal(t, output, "kn source kamelet binding create k1-to-broker --kamelet k1 --sink broker:default "+
+		"--property k1_prop=foo --step a1 --step-property 'step1.a1_prop=hello world'\n")
+
+	recorder.Validate()
+}
+
+func TestBindingExportCommandTrigger(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	binding := createKameletBindingInNamespace("k1-to-broker", "k1", namespace, brokerRef(namespace))
+	binding.Labels = map[string]string{bindingLabel: "k1-to-broker"}
+	binding.Spec.Sink.Properties.RawMessage = []byte(`{"cloudEventsType":"org.example.order"}`)
+	recorder.GetKameletBinding(binding, nil)
+
+	trigger := clienteventingv1.NewTriggerBuilder("k1-to-broker").
+		Namespace(namespace).
+		Broker("default").
+		Filters(map[string]string{"type": "org.example.order", "source": "/orders"}).
+		Subscriber(&duckv1.Destination{Ref: &duckv1.KReference{APIVersion: "serving.knative.dev/v1", Kind: "Service", Name: "handler", Namespace: namespace}}).
+		Build()
+	trigger.Labels = map[string]string{bindingLabel: "k1-to-broker"}
+
+	output, err := runBindingExportCmdWithTriggers(mockClient, newFakeTriggerClient(trigger), "k1-to-broker", "--format", "command")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "kn source kamelet binding create k1-to-broker --kamelet k1 --sink broker:default --property k1_prop=foo "+
+		"--ce-type org.example.order --subscriber ksvc:handler --filter source=/orders --filter type=org.example.order\n")
+
+	// the exported command recreates the binding and its Trigger
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+	recorder.CreateKameletBinding(binding, nil)
+	triggerClient := newFakeTriggerClient()
+	p := KameletPluginParams{
+		KnParams: &commands.KnParams{
+			NewEventingClient: func(namespace string) (clienteventingv1.KnEventingClient, error) {
+				return triggerClient, nil
+			},
+		},
+		Context: context.TODO(),
+		NewKameletClient: func() (camelkv1alpha1.CamelV1alpha1Interface, error) {
+			return mockClient, nil
+		},
+	}
+	createCmd, _, _ := commands.CreateSourcesTestKnCommand(newBindingCreateCommand(&p), p.KnParams)
+	args := strings.Fields(strings.TrimPrefix(output, "kn source kamelet binding "))
+	createCmd.SetArgs(append(args, "-n", namespace, "--no-wait"))
+	assert.NilError(t, createCmd.Execute())
+	assert.DeepEqual(t, triggerClient.triggers["k1-to-broker"].Spec, trigger.Spec)
+
+	recorder.Validate()
+}
+
+func TestBindingExportCommandIntegrationSettings(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	replicas := int32(2)
+	binding := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	binding.Spec.Integration = &camelv1.IntegrationSpec{
+		Replicas:           &replicas,
+		Dependencies:       []string{"mvn:org.example:library:1.0"},
+		Profile:            camelv1.TraitProfileKnative,
+		ServiceAccountName: "runner",
+		Traits: map[string]camelv1.TraitSpec{
+			"prometheus": {Configuration: camelv1.TraitConfiguration{RawMessage: []byte(`{"enabled":true,"port":9779}`)}},
+			"mount":      {Configuration: camelv1.TraitConfiguration{RawMessage: []byte(`{"configs":["configmap:a","configmap:b"]}`)}},
+		},
+	}
+	recorder.GetKameletBinding(binding, nil)
+
+	output, err := runBindingExportCmd(mockClient, "k1-to-channel", "--format", "command")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "kn source kamelet binding create k1-to-channel --kamelet k1 --sink channel:test --property k1_prop=foo "+
+		"--trait mount.configs=configmap:a --trait mount.configs=configmap:b --trait prometheus.enabled=true --trait prometheus.port=9779 "+
+		"--profile Knative --dependency mvn:org.example:library:1.0 --service-account runner --replicas 2\n")
+
+	recorder.Validate()
+}
+
+func TestBindingExportCommandErrorHandler(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	binding := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	binding.Spec.Integration = &camelv1.IntegrationSpec{
+		Traits: map[string]camelv1.TraitSpec{
+			"knative":       {Configuration: camelv1.TraitConfiguration{RawMessage: []byte(`{"channelSinks":["knative:channel/errors?apiVersion=messaging.knative.dev%2Fv1\u0026kind=Channel"]}`)}},
+			"error-handler": {Configuration: camelv1.TraitConfiguration{RawMessage: []byte(`{"ref":"defaultErrorHandler"}`)}},
+			"prometheus":    {Configuration: camelv1.TraitConfiguration{RawMessage: []byte(`{"enabled":true}`)}},
+		},
+		Configuration: []camelv1.ConfigurationSpec{
+			{Type: "property", Value: "camel.beans.defaultErrorHandler=#class:org.apache.camel.builder.DeadLetterChannelBuilder"},
+			{Type: "property", Value: "camel.beans.defaultErrorHandler.deadLetterUri=knative:channel/errors?apiVersion=messaging.knative.dev%2Fv1&kind=Channel"},
+			{Type: "property", Value: "camel.beans.defaultErrorHandler.redeliveryDelay=2000"},
+		},
+	}
+	recorder.GetKameletBinding(binding, nil)
+
+	output, err := runBindingExportCmd(mockClient, "k1-to-channel", "--format", "command")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "kn source kamelet binding create k1-to-channel --kamelet k1 --sink channel:test --property k1_prop=foo "+
+		"--error-sink channel:errors --error-redelivery-delay 2s --trait prometheus.enabled=true\n")
+
+	recorder.Validate()
+}
+
+func TestBindingExportCommandSinkKamelet(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	binding := createKameletBindingInNamespace("channel-to-log", "log-sink", namespace, nil)
+	binding.Spec.Sink = binding.Spec.Source
+	binding.Spec.Source = v1alpha1.Endpoint{Ref: channelRef(namespace)}
+	recorder.GetKameletBinding(binding, nil)
+
+	output, err := runBindingExportCmd(mockClient, "channel-to-log", "--format", "command")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "kn source kamelet binding create channel-to-log --kamelet log-sink --source channel:test --property log-sink_prop=foo\n")
+
+	recorder.Validate()
+}
+
+func TestBindingExportKustomize(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	dir := filepath.Join(t.TempDir(), "base")
+	recorder.ListBindings(&v1alpha1.KameletBindingList{Items: []v1alpha1.KameletBinding{
+		*createKameletBindingInNamespace("k2-to-channel", "k2", namespace, channelRef(namespace)),
+		*createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace)),
+	}}, nil)
+
+	output, err := runBindingExportCmd(mockClient, "--all", "--format", "kustomize", "--output-dir", dir)
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kamelet binding \"k1-to-channel\" exported", "kamelet binding \"k2-to-channel\" exported"))
+
+	kustomization, err := os.ReadFile(filepath.Join(dir, "kustomization.yaml"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(kustomization), "apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nresources:\n- k1-to-channel.yaml\n- k2-to-channel.yaml\n")
+
+	manifest, err := os.ReadFile(filepath.Join(dir, "k2-to-channel.yaml"))
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(string(manifest), "kind: KameletBinding", "name: k2-to-channel"))
+
+	recorder.Validate()
+}
+
+func TestBindingExportErrorCaseMissingArgument(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	_, err := runBindingExportCmd(mockClient)
+	assert.Error(t, err, "'kn-source-kamelet binding export' requires the binding name as argument or --all")
+
+	recorder.Validate()
+}
+
+func TestBindingExportErrorCaseUnsupportedFormat(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	_, err := runBindingExportCmd(mockClient, "k1-to-channel", "--format", "helm")
+	assert.Error(t, err, "unsupported export format \"helm\" - please use one of yaml, kustomize, command")
+
+	recorder.Validate()
+}
+
+func runBindingExportCmd(c *client.MockClient, options ...string) (string, error) {
+	return runBindingExportCmdWithTriggers(c, nil, options...)
+}
+
+func runBindingExportCmdWithTriggers(c *client.MockClient, triggerClient *fakeTriggerClient, options ...string) (string, error) {
+	p := KameletPluginParams{
+		KnParams: &commands.KnParams{
+			NewEventingClient: func(namespace string) (clienteventingv1.KnEventingClient, error) {
+				if triggerClient == nil {
+					return nil, errors.New("no trigger client")
+				}
+				return triggerClient, nil
+			},
+		},
+		Context: context.TODO(),
+		NewKameletClient: func() (camelkv1alpha1.CamelV1alpha1Interface, error) {
+			return c, nil
+		},
+	}
+
+	exportCmd, _, output := commands.CreateSourcesTestKnCommand(newBindingExportCommand(&p), p.KnParams)
+
+	args := []string{"export"}
+	args = append(args, options...)
+	exportCmd.SetArgs(args)
+	err := exportCmd.Execute()
+
+	return output.String(), err
+}
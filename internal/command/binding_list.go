@@ -19,12 +19,14 @@ package command
 import (
 	"fmt"
 
+	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	camelkv1alpha1 "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"knative.dev/client-pkg/pkg/commands"
 	"knative.dev/client-pkg/pkg/commands/flags"
 	hprinters "knative.dev/client-pkg/pkg/printers"
@@ -39,7 +41,10 @@ var bindingListExample = `
 
 // newBindingCreateCommand implements 'kn-source-kamelet binding list' command
 func newBindingListCommand(p *KameletPluginParams) *cobra.Command {
-	listFlags := flags.NewListPrintFlags(ListBindingHandlers)
+	var integrations map[types.NamespacedName]camelv1.Integration
+	listFlags := flags.NewListPrintFlags(func(h hprinters.PrintHandler) {
+		listBindingHandlers(h, integrations)
+	})
 
 	cmd := &cobra.Command{
 		Use:     "list",
@@ -67,12 +72,26 @@ func newBindingListCommand(p *KameletPluginParams) *cobra.Command {
 			}
 			updateKameletBindingListGvk(bindingList)
 
+			// the table shows the actual replicas of the integrations running the bindings
+			if !listFlags.GenericPrintFlags.OutputFlagSpecified() {
+				integrations = listIntegrations(p, namespace)
+			}
+
 			// empty namespace indicates all-namespaces flag is specified
 			if namespace == "" {
 				listFlags.EnsureWithNamespace()
 			}
 
-			err = listFlags.Print(bindingList, cmd.OutOrStdout())
+			// bindings are printed as Pipes when the cluster serves them
+			var obj runtime.Object = bindingList
+			if listFlags.GenericPrintFlags.OutputFlagSpecified() {
+				obj, err = printableObject(kameletClient, bindingList)
+				if err != nil {
+					return err
+				}
+			}
+
+			err = listFlags.Print(obj, cmd.OutOrStdout())
 			if err != nil {
 				return err
 			}
@@ -87,26 +106,36 @@ func newBindingListCommand(p *KameletPluginParams) *cobra.Command {
 
 // ListBindingHandlers handles printing human readable table for `kn-source-kamelet binding list` command's output
 func ListBindingHandlers(h hprinters.PrintHandler) {
+	listBindingHandlers(h, nil)
+}
+
+// listBindingHandlers handles printing the table with the actual replicas taken from the given integrations
+func listBindingHandlers(h hprinters.PrintHandler, integrations map[types.NamespacedName]camelv1.Integration) {
 	columnDefinitions := []metav1beta1.TableColumnDefinition{
 		{Name: "Namespace", Type: "string", Description: "Namespace of the Kamelet binding", Priority: 0},
 		{Name: "Name", Type: "string", Description: "Name of the Kamelet binding ", Priority: 1},
-		{Name: "Phase", Type: "string", Description: "Phase of the Kamelet binding ", Priority: 1},
+		{Name: "Phase", Type: "string", Description: "Phase of the Kamelet binding, Paused for paused bindings", Priority: 1},
+		{Name: "Replicas", Type: "string", Description: "Desired and actual replicas of the Kamelet binding", Priority: 1},
 		{Name: "Age", Type: "string", Description: "Age of the Kamelet binding ", Priority: 1},
 		{Name: "Conditions", Type: "string", Description: "Ready state conditions", Priority: 1},
 		{Name: "Ready", Type: "string", Description: "Ready state of the Kamelet binding ", Priority: 1},
 		{Name: "Reason", Type: "string", Description: "Reason if state is not Ready", Priority: 1},
 	}
-	h.TableHandler(columnDefinitions, printBinding)
-	h.TableHandler(columnDefinitions, printBindingList)
+	h.TableHandler(columnDefinitions, func(binding *camelkv1alpha1.KameletBinding, options hprinters.PrintOptions) ([]metav1beta1.TableRow, error) {
+		return printBinding(binding, integrations, options)
+	})
+	h.TableHandler(columnDefinitions, func(bindingList *camelkv1alpha1.KameletBindingList, options hprinters.PrintOptions) ([]metav1beta1.TableRow, error) {
+		return printBindingList(bindingList, integrations, options)
+	})
 }
 
 // printKameletList populates the Kamelet list table rows
-func printBindingList(bindingList *camelkv1alpha1.KameletBindingList, options hprinters.PrintOptions) ([]metav1beta1.TableRow, error) {
+func printBindingList(bindingList *camelkv1alpha1.KameletBindingList, integrations map[types.NamespacedName]camelv1.Integration, options hprinters.PrintOptions) ([]metav1beta1.TableRow, error) {
 	rows := make([]metav1beta1.TableRow, 0, len(bindingList.Items))
 
 	for i := range bindingList.Items {
 		binding := &bindingList.Items[i]
-		r, err := printBinding(binding, options)
+		r, err := printBinding(binding, integrations, options)
 		if err != nil {
 			return nil, err
 		}
@@ -116,9 +145,10 @@ func printBindingList(bindingList *camelkv1alpha1.KameletBindingList, options hp
 }
 
 // printBinding populates the Kamelet binding table rows
-func printBinding(binding *camelkv1alpha1.KameletBinding, options hprinters.PrintOptions) ([]metav1beta1.TableRow, error) {
+func printBinding(binding *camelkv1alpha1.KameletBinding, integrations map[types.NamespacedName]camelv1.Integration, options hprinters.PrintOptions) ([]metav1beta1.TableRow, error) {
 	name := binding.Name
-	phase := binding.Status.Phase
+	phase := bindingPhaseValue(binding)
+	replicas := bindingReplicasValue(binding, integrations)
 	age := commands.TranslateTimestampSince(binding.CreationTimestamp)
 	conditions := bindingConditionsValue(binding.Status.Conditions)
 	ready := bindingReadyCondition(binding.Status.Conditions)
@@ -135,6 +165,7 @@ func printBinding(binding *camelkv1alpha1.KameletBinding, options hprinters.Prin
 	row.Cells = append(row.Cells,
 		name,
 		phase,
+		replicas,
 		age,
 		conditions,
 		ready,
@@ -142,6 +173,47 @@ func printBinding(binding *camelkv1alpha1.KameletBinding, options hprinters.Prin
 	return []metav1beta1.TableRow{row}, nil
 }
 
+// bindingPhaseValue returns the phase of the binding, Paused for bindings paused with 'kn-source-kamelet binding pause'
+func bindingPhaseValue(binding *camelkv1alpha1.KameletBinding) string {
+	if isPaused(binding) {
+		return "Paused"
+	}
+	return string(binding.Status.Phase)
+}
+
+// listIntegrations returns the integrations of the namespace by name. The actual replicas are unknown without
+// access to the integrations, so nil is returned when they can not be listed.
+func listIntegrations(p *KameletPluginParams, namespace string) map[types.NamespacedName]camelv1.Integration {
+	integrationClient, err := p.NewIntegrationClient()
+	if err != nil {
+		return nil
+	}
+	items, err := integrationClient.ListIntegrations(p.Context, namespace)
+	if err != nil {
+		return nil
+	}
+	integrations := make(map[types.NamespacedName]camelv1.Integration, len(items))
+	for _, integration := range items {
+		integrations[types.NamespacedName{Namespace: integration.Namespace, Name: integration.Name}] = integration
+	}
+	return integrations
+}
+
+// bindingReplicasValue returns the desired and the actual replicas of the integration owned by the binding
+func bindingReplicasValue(binding *camelkv1alpha1.KameletBinding, integrations map[types.NamespacedName]camelv1.Integration) string {
+	desired := desiredReplicas(binding)
+	if integrations == nil {
+		return fmt.Sprintf("%d/<unknown>", desired)
+	}
+
+	var actual int32
+	integration, ok := integrations[types.NamespacedName{Namespace: binding.Namespace, Name: binding.Name}]
+	if ok && ownedBy(integration.OwnerReferences, binding) && integration.Status.Replicas != nil {
+		actual = *integration.Status.Replicas
+	}
+	return fmt.Sprintf("%d/%d", desired, actual)
+}
+
 // bindingConditionsValue returns the True conditions count among total conditions
 func bindingConditionsValue(conditions []camelkv1alpha1.KameletBindingCondition) string {
 	var ok int
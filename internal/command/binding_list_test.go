@@ -18,6 +18,7 @@ package command
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 
@@ -26,6 +27,7 @@ import (
 	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
+	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
 	camelkapis "github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
 	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
 	"knative.dev/client-pkg/pkg/commands"
@@ -74,7 +76,7 @@ func TestBindingListOutput(t *testing.T) {
 	bindingList := &camelkapis.KameletBindingList{Items: []camelkapis.KameletBinding{*binding1, *binding2, *binding3}}
 	recorder.ListBindings(bindingList, nil)
 
-	output, err := runBindingListCmd(mockClient)
+	output, err := runBindingListCmd(mockClient, &fakeIntegrationClient{})
 	assert.NilError(t, err)
 
 	outputLines := strings.Split(output, "\n")
@@ -92,7 +94,7 @@ func TestBindingListEmpty(t *testing.T) {
 	recorder := mockClient.Recorder()
 
 	recorder.ListBindings(&camelkapis.KameletBindingList{}, nil)
-	output, err := runBindingListCmd(mockClient)
+	output, err := runBindingListCmd(mockClient, &fakeIntegrationClient{})
 	assert.NilError(t, err)
 
 	assert.Assert(t, util.ContainsAll(output, "No", "resources", "found"))
@@ -139,7 +141,7 @@ func TestBindingListNoReadyReasonOutput(t *testing.T) {
 	bindingList := &camelkapis.KameletBindingList{Items: []camelkapis.KameletBinding{*binding1, *binding2, *binding3}}
 	recorder.ListBindings(bindingList, nil)
 
-	output, err := runBindingListCmd(mockClient)
+	output, err := runBindingListCmd(mockClient, &fakeIntegrationClient{})
 	assert.NilError(t, err)
 
 	outputLines := strings.Split(output, "\n")
@@ -180,7 +182,7 @@ func TestBindingListAllNamespace(t *testing.T) {
 	bindingList := &camelkapis.KameletBindingList{Items: []camelkapis.KameletBinding{*binding1, *binding2, *binding3}}
 	recorder.ListBindings(bindingList, nil)
 
-	output, err := runBindingListCmd(mockClient, "--all-namespaces")
+	output, err := runBindingListCmd(mockClient, &fakeIntegrationClient{}, "--all-namespaces")
 	assert.NilError(t, err)
 
 	outputLines := strings.Split(output, "\n")
@@ -192,13 +194,79 @@ func TestBindingListAllNamespace(t *testing.T) {
 	recorder.Validate()
 }
 
-func runBindingListCmd(c *client.MockClient, options ...string) (string, error) {
+func TestBindingListReplicas(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	zero := int32(0)
+	paused := createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current"))
+	paused.Annotations = map[string]string{pausedReplicasAnnotation: "2"}
+	paused.Spec.Integration = &camelv1.IntegrationSpec{Replicas: &zero}
+	paused.Status = statusReady()
+	three := int32(3)
+	scaled := createKameletBindingInNamespace("k2-to-channel", "k2", "current", channelRef("current"))
+	scaled.Spec.Integration = &camelv1.IntegrationSpec{Replicas: &three}
+	scaled.Status = statusReady()
+	notRunning := createKameletBindingInNamespace("k3-to-channel", "k3", "current", channelRef("current"))
+	recorder.ListBindings(&camelkapis.KameletBindingList{Items: []camelkapis.KameletBinding{*paused, *scaled, *notRunning}}, nil)
+
+	two := int32(2)
+	pausedIntegration := createIntegration("k1-to-channel", "current", "")
+	pausedIntegration.Status.Replicas = &zero
+	scaledIntegration := createIntegration("k2-to-channel", "current", "")
+	scaledIntegration.Status.Replicas = &two
+	integrationClient := &fakeIntegrationClient{integrations: []camelv1.Integration{*pausedIntegration, *scaledIntegration}}
+
+	output, err := runBindingListCmd(mockClient, integrationClient, "-n", "current")
+	assert.NilError(t, err)
+
+	outputLines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(outputLines[0], "NAME", "PHASE", "REPLICAS", "AGE"))
+	assert.Check(t, util.ContainsAll(outputLines[1], "k1-to-channel", "Paused", "0/0"))
+	assert.Check(t, util.ContainsAll(outputLines[2], "k2-to-channel", "Ready", "3/2"))
+	assert.Check(t, util.ContainsAll(outputLines[3], "k3-to-channel", "1/0"))
+
+	recorder.Validate()
+}
+
+func TestBindingListReplicasUnknown(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	binding := createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current"))
+	binding.Status = statusReady()
+	list := &camelkapis.KameletBindingList{Items: []camelkapis.KameletBinding{*binding}}
+	recorder.ListBindings(list, nil)
+	recorder.ListBindings(list, nil)
+
+	// the integrations can not be listed
+	output, err := runBindingListCmd(mockClient, &fakeIntegrationClient{listErr: errors.New("forbidden")}, "-n", "current")
+	assert.NilError(t, err)
+	outputLines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(outputLines[1], "k1-to-channel", "Ready", "1/<unknown>"))
+
+	// the integration client can not be created
+	output, err = runBindingListCmd(mockClient, nil, "-n", "current")
+	assert.NilError(t, err)
+	outputLines = strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(outputLines[1], "k1-to-channel", "Ready", "1/<unknown>"))
+
+	recorder.Validate()
+}
+
+func runBindingListCmd(c *client.MockClient, integrationClient IntegrationClient, options ...string) (string, error) {
 	p := KameletPluginParams{
 		KnParams: &commands.KnParams{},
 		Context:  context.TODO(),
 		NewKameletClient: func() (camelkv1alpha1.CamelV1alpha1Interface, error) {
 			return c, nil
 		},
+		NewIntegrationClient: func() (IntegrationClient, error) {
+			if integrationClient == nil {
+				return nil, errors.New("no integration client")
+			}
+			return integrationClient, nil
+		},
 	}
 
 	command, _, output := commands.CreateSourcesTestKnCommand(newBindingListCommand(&p), p.KnParams)
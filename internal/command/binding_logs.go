@@ -0,0 +1,302 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/client-pkg/pkg/commands"
+	knerrors "knative.dev/client-pkg/pkg/errors"
+)
+
+const (
+	// integrationLabel is set by Camel K on all pods of an Integration
+	integrationLabel = "camel.apache.org/integration"
+	// integrationContainer is the name of the container running the Integration
+	integrationContainer = "integration"
+)
+
+// logsPollInterval is the interval for looking up new and restarted pods while following the logs
+var logsPollInterval = 2 * time.Second
+
+var bindingLogsExample = `
+  # Print the logs of all pods running the Kamelet binding
+  kn source kamelet binding logs NAME
+
+  # Follow the logs of the Kamelet binding starting with the last 10 lines of each pod
+  kn source kamelet binding logs NAME -f --tail 10
+
+  # Print the logs of the last 5 minutes
+  kn source kamelet binding logs NAME --since 5m`
+
+// LogsBindingOptions holding settings and options on the binding logs command
+type LogsBindingOptions struct {
+	Follow bool
+	Since  time.Duration
+	Tail   int64
+	CmdOut io.Writer
+}
+
+// newBindingLogsCommand implements 'kn-source-kamelet binding logs' command
+func newBindingLogsCommand(p *KameletPluginParams) *cobra.Command {
+	options := LogsBindingOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "logs NAME",
+		Short:   "Print the logs of the Integration running a Kamelet binding.",
+		Example: bindingLogsExample,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) != 1 {
+				return errors.New("'kn-source-kamelet binding logs' requires the binding name as argument")
+			}
+			name := args[0]
+			options.CmdOut = cmd.OutOrStdout()
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := p.NewKameletClient()
+			if err != nil {
+				return err
+			}
+
+			binding, err := client.KameletBindings(namespace).Get(p.Context, name, v1.GetOptions{})
+			if err != nil {
+				return knerrors.GetError(err)
+			}
+
+			logClient, err := p.NewIntegrationClient()
+			if err != nil {
+				return err
+			}
+
+			selector, err := integrationSelector(logClient, p.Context, binding)
+			if err != nil {
+				return err
+			}
+
+			if options.Follow {
+				return followLogs(logClient, p.Context, namespace, selector, options)
+			}
+			return printLogs(logClient, p.Context, binding.Name, namespace, selector, options)
+		},
+	}
+	flags := cmd.Flags()
+	commands.AddNamespaceFlags(flags, false)
+	flags.BoolVarP(&options.Follow, "follow", "f", false, "Follow the logs, including the logs of pods started later on.")
+	flags.DurationVar(&options.Since, "since", 0, "Only print logs newer than the given duration, e.g. 5s, 2m or 3h.")
+	flags.Int64Var(&options.Tail, "tail", -1, "Number of most recent lines to print per pod, -1 prints all lines.")
+	return cmd
+}
+
+// integrationSelector returns the label selector of the pods running the Integration owned by the binding
+func integrationSelector(client IntegrationClient, ctx context.Context, binding *v1alpha1.KameletBinding) (string, error) {
+	integration, err := client.GetIntegration(ctx, binding.Namespace, binding.Name)
+	if k8serrors.IsNotFound(err) {
+		return "", fmt.Errorf("no integration found for kamelet binding %q", binding.Name)
+	}
+	if err != nil {
+		return "", knerrors.GetError(err)
+	}
+	if !ownedBy(integration.OwnerReferences, binding) {
+		return "", fmt.Errorf("integration %q is not owned by kamelet binding %q", integration.Name, binding.Name)
+	}
+
+	if integration.Status.Selector != "" {
+		return integration.Status.Selector, nil
+	}
+	return integrationLabel + "=" + integration.Name, nil
+}
+
+func ownedBy(owners []v1.OwnerReference, binding *v1alpha1.KameletBinding) bool {
+	for _, owner := range owners {
+		if owner.Name != binding.Name || (owner.Kind != v1alpha1.KameletBindingKind && owner.Kind != pipeKind) {
+			continue
+		}
+		if binding.UID == "" || owner.UID == binding.UID {
+			return true
+		}
+	}
+	return false
+}
+
+// printLogs prints the logs of all pods one after another
+func printLogs(client IntegrationClient, ctx context.Context, name string, namespace string, selector string, options LogsBindingOptions) error {
+	pods, err := client.ListPods(ctx, namespace, selector)
+	if err != nil {
+		return knerrors.GetError(err)
+	}
+	if len(pods) == 0 {
+		return fmt.Errorf("no pods found for kamelet binding %q", name)
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+
+	out := &prefixLineWriter{out: options.CmdOut}
+	for i := range pods {
+		logOptions := podLogOptions(&pods[i], options)
+		if err := streamPodLogs(client, ctx, namespace, pods[i].Name, logOptions, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// followLogs streams the logs of all pods until the context is done. Pods started later on are picked up, restarted
+// containers are followed again from the time their previous stream ended.
+func followLogs(client IntegrationClient, ctx context.Context, namespace string, selector string, options LogsBindingOptions) error {
+	type podStream struct {
+		active   bool
+		restarts int32
+		ended    *v1.Time
+	}
+
+	out := &prefixLineWriter{out: options.CmdOut}
+	streams := make(map[string]*podStream)
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	ticker := time.NewTicker(logsPollInterval)
+	defer ticker.Stop()
+	for {
+		pods, err := client.ListPods(ctx, namespace, selector)
+		if err != nil && ctx.Err() == nil {
+			return knerrors.GetError(err)
+		}
+
+		lock.Lock()
+		for i := range pods {
+			pod := &pods[i]
+			if pod.Status.Phase != corev1.PodRunning {
+				continue
+			}
+			stream, ok := streams[pod.Name]
+			restarts := podRestarts(pod)
+			switch {
+			case !ok:
+				stream = &podStream{}
+				streams[pod.Name] = stream
+			case stream.active || stream.restarts == restarts:
+				continue
+			}
+
+			logOptions := podLogOptions(pod, options)
+			logOptions.Follow = true
+			if stream.ended != nil {
+				// the container restarted, continue where the previous stream ended
+				logOptions.SinceSeconds = nil
+				logOptions.TailLines = nil
+				logOptions.SinceTime = stream.ended
+			}
+			stream.active = true
+			stream.restarts = restarts
+
+			wg.Add(1)
+			go func(name string, stream *podStream) {
+				defer wg.Done()
+				err := streamPodLogs(client, ctx, namespace, name, logOptions, out)
+				lock.Lock()
+				defer lock.Unlock()
+				stream.active = false
+				now := v1.Now()
+				stream.ended = &now
+				if err != nil && ctx.Err() == nil {
+					// retry with the next poll
+					stream.restarts = -1
+				}
+			}(pod.Name, stream)
+		}
+		lock.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func podLogOptions(pod *corev1.Pod, options LogsBindingOptions) *corev1.PodLogOptions {
+	logOptions := &corev1.PodLogOptions{}
+	if len(pod.Spec.Containers) > 1 {
+		logOptions.Container = integrationContainer
+	}
+	if options.Since > 0 {
+		seconds := int64(options.Since.Seconds())
+		logOptions.SinceSeconds = &seconds
+	}
+	if options.Tail >= 0 {
+		tail := options.Tail
+		logOptions.TailLines = &tail
+	}
+	return logOptions
+}
+
+func podRestarts(pod *corev1.Pod) int32 {
+	var restarts int32
+	for _, status := range pod.Status.ContainerStatuses {
+		restarts += status.RestartCount
+	}
+	return restarts
+}
+
+// streamPodLogs copies the logs of the pod line by line to the given writer
+func streamPodLogs(client IntegrationClient, ctx context.Context, namespace string, pod string, options *corev1.PodLogOptions, out *prefixLineWriter) error {
+	stream, err := client.StreamLogs(ctx, namespace, pod, options)
+	if err != nil {
+		return knerrors.GetError(err)
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		out.writeLine(pod, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// prefixLineWriter writes complete lines of concurrent log streams prefixed with the pod name
+type prefixLineWriter struct {
+	out  io.Writer
+	lock sync.Mutex
+}
+
+func (w *prefixLineWriter) writeLine(pod string, line string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	_, _ = fmt.Fprintf(w.out, "[%s] %s\n", pod, line)
+}
@@ -0,0 +1,278 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/client-pkg/pkg/commands"
+	"knative.dev/client-pkg/pkg/util"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBindingLogs(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.GetKameletBinding(createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current")), nil)
+
+	logClient := &fakeIntegrationClient{
+		integration: createIntegration("k1-to-channel", "current", "camel.apache.org/integration=k1-to-channel"),
+		pods:        [][]corev1.Pod{{createPod("k1-to-channel-b", 0), createPod("k1-to-channel-a", 0)}},
+		logs: map[string][]string{
+			"k1-to-channel-a": {"started\nrunning\n"},
+			"k1-to-channel-b": {"started\n"},
+		},
+	}
+
+	output, err := runBindingLogsCmd(mockClient, logClient, context.TODO(), "k1-to-channel", "-n", "current", "--tail", "10", "--since", "5m")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "[k1-to-channel-a] started\n[k1-to-channel-a] running\n[k1-to-channel-b] started\n")
+	assert.Equal(t, logClient.selector, "camel.apache.org/integration=k1-to-channel")
+
+	options := logClient.options["k1-to-channel-a"][0]
+	assert.Equal(t, *options.TailLines, int64(10))
+	assert.Equal(t, *options.SinceSeconds, int64(300))
+	assert.Assert(t, !options.Follow)
+
+	recorder.Validate()
+}
+
+func TestBindingLogsFollow(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	defer func(interval time.Duration) { logsPollInterval = interval }(logsPollInterval)
+	logsPollInterval = 10 * time.Millisecond
+
+	recorder.GetKameletBinding(createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current")), nil)
+
+	logClient := &fakeIntegrationClient{
+		integration: createIntegration("k1-to-channel", "current", ""),
+		pods: [][]corev1.Pod{
+			{createPod("k1-to-channel-a", 0)},
+			{createPod("k1-to-channel-a", 1), createPod("k1-to-channel-b", 0)},
+		},
+		logs: map[string][]string{
+			"k1-to-channel-a": {"started\n", "restarted\n"},
+			"k1-to-channel-b": {"scaled out\n"},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	output, err := runBindingLogsCmd(mockClient, logClient, ctx, "k1-to-channel", "-n", "current", "-f")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "[k1-to-channel-a] started\n", "[k1-to-channel-a] restarted\n", "[k1-to-channel-b] scaled out\n"))
+	assert.Equal(t, logClient.selector, "camel.apache.org/integration=k1-to-channel")
+
+	logClient.lock.Lock()
+	defer logClient.lock.Unlock()
+	assert.Equal(t, len(logClient.options["k1-to-channel-a"]), 2)
+	assert.Assert(t, logClient.options["k1-to-channel-a"][0].Follow)
+	assert.Assert(t, logClient.options["k1-to-channel-a"][1].SinceTime != nil)
+	assert.Equal(t, len(logClient.options["k1-to-channel-b"]), 1)
+
+	recorder.Validate()
+}
+
+func TestBindingLogsErrorCaseNoIntegration(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.GetKameletBinding(createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current")), nil)
+
+	logClient := &fakeIntegrationClient{
+		integrationErr: k8serrors.NewNotFound(camelv1.Resource("integrations"), "k1-to-channel"),
+	}
+
+	_, err := runBindingLogsCmd(mockClient, logClient, context.TODO(), "k1-to-channel", "-n", "current")
+	assert.Error(t, err, "no integration found for kamelet binding \"k1-to-channel\"")
+
+	recorder.Validate()
+}
+
+func TestBindingLogsErrorCaseNotOwned(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.GetKameletBinding(createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current")), nil)
+
+	integration := createIntegration("k1-to-channel", "current", "")
+	integration.OwnerReferences = nil
+	logClient := &fakeIntegrationClient{integration: integration}
+
+	_, err := runBindingLogsCmd(mockClient, logClient, context.TODO(), "k1-to-channel", "-n", "current")
+	assert.Error(t, err, "integration \"k1-to-channel\" is not owned by kamelet binding \"k1-to-channel\"")
+
+	recorder.Validate()
+}
+
+func TestBindingLogsErrorCaseNoPods(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.GetKameletBinding(createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current")), nil)
+
+	logClient := &fakeIntegrationClient{integration: createIntegration("k1-to-channel", "current", "")}
+
+	_, err := runBindingLogsCmd(mockClient, logClient, context.TODO(), "k1-to-channel", "-n", "current")
+	assert.Error(t, err, "no pods found for kamelet binding \"k1-to-channel\"")
+
+	recorder.Validate()
+}
+
+func TestBindingLogsErrorCaseMissingArgument(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	_, err := runBindingLogsCmd(mockClient, &fakeIntegrationClient{}, context.TODO())
+	assert.Error(t, err, "'kn-source-kamelet binding logs' requires the binding name as argument")
+
+	recorder.Validate()
+}
+
+func createIntegration(name string, namespace string, selector string) *camelv1.Integration {
+	return &camelv1.Integration{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			OwnerReferences: []v1.OwnerReference{{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       v1alpha1.KameletBindingKind,
+				Name:       name,
+			}},
+		},
+		Status: camelv1.IntegrationStatus{
+			Selector: selector,
+		},
+	}
+}
+
+func createPod(name string, restarts int32) corev1.Pod {
+	return corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name: name,
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: integrationContainer}},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         integrationContainer,
+				RestartCount: restarts,
+			}},
+		},
+	}
+}
+
+// fakeIntegrationClient returns the given integrations, the given pod lists one per call repeating the last one and the logs of a pod one per call
+type fakeIntegrationClient struct {
+	integration    *camelv1.Integration
+	integrationErr error
+	integrations   []camelv1.Integration
+	listErr        error
+	pods           [][]corev1.Pod
+	logs           map[string][]string
+
+	lock     sync.Mutex
+	selector string
+	options  map[string][]*corev1.PodLogOptions
+}
+
+func (c *fakeIntegrationClient) GetIntegration(ctx context.Context, namespace string, name string) (*camelv1.Integration, error) {
+	return c.integration, c.integrationErr
+}
+
+func (c *fakeIntegrationClient) ListIntegrations(ctx context.Context, namespace string) ([]camelv1.Integration, error) {
+	if c.listErr != nil {
+		return nil, c.listErr
+	}
+	var integrations []camelv1.Integration
+	for _, integration := range c.integrations {
+		if namespace == "" || integration.Namespace == namespace {
+			integrations = append(integrations, integration)
+		}
+	}
+	return integrations, nil
+}
+
+func (c *fakeIntegrationClient) ListPods(ctx context.Context, namespace string, selector string) ([]corev1.Pod, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.selector = selector
+	if len(c.pods) == 0 {
+		return nil, nil
+	}
+	pods := c.pods[0]
+	if len(c.pods) > 1 {
+		c.pods = c.pods[1:]
+	}
+	return pods, nil
+}
+
+func (c *fakeIntegrationClient) StreamLogs(ctx context.Context, namespace string, pod string, options *corev1.PodLogOptions) (io.ReadCloser, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.options == nil {
+		c.options = make(map[string][]*corev1.PodLogOptions)
+	}
+	c.options[pod] = append(c.options[pod], options)
+
+	logs := ""
+	if len(c.logs[pod]) > 0 {
+		logs = c.logs[pod][0]
+		c.logs[pod] = c.logs[pod][1:]
+	}
+	return io.NopCloser(strings.NewReader(logs)), nil
+}
+
+func runBindingLogsCmd(c *client.MockClient, logClient IntegrationClient, ctx context.Context, options ...string) (string, error) {
+	p := KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  ctx,
+		NewKameletClient: func() (camelkv1alpha1.CamelV1alpha1Interface, error) {
+			return c, nil
+		},
+		NewIntegrationClient: func() (IntegrationClient, error) {
+			return logClient, nil
+		},
+	}
+
+	logsCmd, _, output := commands.CreateSourcesTestKnCommand(newBindingLogsCommand(&p), p.KnParams)
+
+	args := []string{"logs"}
+	args = append(args, options...)
+	logsCmd.SetArgs(args)
+	err := logsCmd.Execute()
+
+	return output.String(), err
+}
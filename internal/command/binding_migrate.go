@@ -0,0 +1,323 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/client-pkg/pkg/commands"
+	knerrors "knative.dev/client-pkg/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+var bindingMigrateExample = `
+  # Show the Pipe a Kamelet binding is migrated to without creating it
+  kn source kamelet binding migrate NAME --dry-run
+
+  # Migrate a Kamelet binding to a Pipe
+  kn source kamelet binding migrate NAME
+
+  # Migrate all Kamelet bindings of the namespace and delete each binding, its integration is taken over by the Pipe
+  kn source kamelet binding migrate --all --delete`
+
+// MigrateBindingOptions holding settings and options on the migrate binding command
+type MigrateBindingOptions struct {
+	DryRun      string
+	Delete      bool
+	WaitTimeout int
+	CmdOut      io.Writer
+}
+
+// newBindingMigrateCommand implements 'kn-source-kamelet binding migrate' command
+func newBindingMigrateCommand(p *KameletPluginParams) *cobra.Command {
+	var all bool
+	options := MigrateBindingOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "migrate NAME|--all",
+		Short:   "Migrate Kamelet bindings to Camel K Pipes.",
+		Example: bindingMigrateExample,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if (len(args) != 1 && !all) || (len(args) > 0 && all) {
+				return errors.New("'kn-source-kamelet binding migrate' requires the binding name as argument or --all")
+			}
+
+			dryRun, err := (&OutputFlags{DryRun: options.DryRun}).dryRunStrategy()
+			if err != nil {
+				return err
+			}
+			if dryRun != "" && options.Delete {
+				return errors.New("--delete can not be combined with --dry-run")
+			}
+			options.DryRun = dryRun
+			options.CmdOut = cmd.OutOrStdout()
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+
+			// the differences to the Pipe can be shown before Camel K is upgraded
+			if dryRun != dryRunClient {
+				resources, err := p.NewServerResources()
+				if err != nil {
+					return err
+				}
+				apiVersion, err := camelAPIVersion("", resources)
+				if err != nil {
+					return knerrors.GetError(err)
+				}
+				if apiVersion != camelAPIVersionV1 {
+					return fmt.Errorf("the cluster does not serve %s pipes - please upgrade Camel K before migrating kamelet bindings", camelV1GroupVersion)
+				}
+			}
+
+			bindingClient, err := p.NewCamelClient(camelAPIVersionV1alpha1)
+			if err != nil {
+				return err
+			}
+
+			var bindings []v1alpha1.KameletBinding
+			if all {
+				list, err := bindingClient.KameletBindings(namespace).List(p.Context, v1.ListOptions{})
+				if err != nil {
+					return knerrors.GetError(err)
+				}
+				bindings = list.Items
+				sort.Slice(bindings, func(i, j int) bool {
+					return bindings[i].Name < bindings[j].Name
+				})
+			} else {
+				binding, err := bindingClient.KameletBindings(namespace).Get(p.Context, args[0], v1.GetOptions{})
+				if err != nil {
+					return knerrors.GetError(err)
+				}
+				bindings = append(bindings, *binding)
+			}
+
+			if len(bindings) == 0 {
+				_, _ = fmt.Fprintf(options.CmdOut, "No kamelet bindings found in namespace %q.\n", namespace)
+				return nil
+			}
+
+			pipeClient, err := p.NewCamelClient(camelAPIVersionV1)
+			if err != nil {
+				return err
+			}
+			var integrationClient IntegrationClient
+			if dryRun == "" {
+				integrationClient, err = p.NewIntegrationClient()
+				if err != nil {
+					return err
+				}
+			}
+
+			for i := range bindings {
+				if err := migrateBinding(bindingClient, pipeClient, integrationClient, p.Context, &bindings[i], namespace, options); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	commands.AddNamespaceFlags(flags, false)
+	flags.BoolVar(&all, "all", false, "Migrate all Kamelet bindings of the namespace.")
+	flags.StringVar(&options.DryRun, "dry-run", dryRunNone,
+		`Must be "none", "client" or "server". Prints the differences between the binding and its Pipe instead of migrating it, with "server" the Pipe is submitted without being persisted.`)
+	cmd.Flag("dry-run").NoOptDefVal = dryRunClient
+	flags.BoolVar(&options.Delete, "delete", false, "Delete the Kamelet binding and hand its integration over to the Pipe.")
+	flags.IntVar(&options.WaitTimeout, "wait-timeout", 60, "Seconds to wait for the Pipe to become ready.")
+	return cmd
+}
+
+// migrateBinding creates the Pipe that replaces the given binding, waits for it to become ready and deletes the
+// binding when requested. A Pipe of the same name with the spec of the binding counts as migrated, a Pipe with a
+// different spec is left alone.
+//
+// The Pipe runs an integration of the same name as the binding. While the binding exists, it owns that integration
+// and the Pipe can not become ready, so such a binding is only migrated with --delete. It is deleted right after the
+// Pipe is created, orphaning its integration so the Pipe takes it over without downtime.
+func migrateBinding(bindingClient camelkv1alpha1.CamelV1alpha1Interface, pipeClient camelkv1alpha1.CamelV1alpha1Interface, integrationClient IntegrationClient,
+	ctx context.Context, binding *v1alpha1.KameletBinding, namespace string, options MigrateBindingOptions) error {
+	pipe := migratedPipe(binding)
+
+	if options.DryRun != "" {
+		if options.DryRun == dryRunServer {
+			_, err := pipeClient.KameletBindings(namespace).Create(ctx, pipe, v1.CreateOptions{DryRun: []string{v1.DryRunAll}})
+			if err != nil && !k8serrors.IsAlreadyExists(err) {
+				return knerrors.GetError(err)
+			}
+		}
+		diff, err := pipeDiff(binding, namespace)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprint(options.CmdOut, diff)
+		return nil
+	}
+
+	existing, err := pipeClient.KameletBindings(namespace).Get(ctx, pipe.Name, v1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return knerrors.GetError(err)
+	}
+	if err == nil {
+		same, err := samePipeSpec(existing, binding, namespace)
+		if err != nil {
+			return err
+		}
+		if !same {
+			return fmt.Errorf("pipe %q already exists with a spec different from kamelet binding %q - please compare them with 'kn source kamelet binding export'", pipe.Name, binding.Name)
+		}
+	}
+
+	integration, err := integrationClient.GetIntegration(ctx, namespace, binding.Name)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return knerrors.GetError(err)
+	}
+	handover := err == nil && integration != nil && ownedByBinding(integration.OwnerReferences, binding)
+	if handover && !options.Delete {
+		return fmt.Errorf("kamelet binding %q runs integration %q, which its pipe can only take over once the binding is deleted - please use --delete to migrate it", binding.Name, integration.Name)
+	}
+
+	resourceVersion := ""
+	if existing != nil {
+		_, _ = fmt.Fprintf(options.CmdOut, "kamelet binding %q already migrated to pipe %q\n", binding.Name, pipe.Name)
+	} else {
+		created, err := pipeClient.KameletBindings(namespace).Create(ctx, pipe, v1.CreateOptions{})
+		if err != nil {
+			return knerrors.GetError(err)
+		}
+		existing, resourceVersion = created, created.ResourceVersion
+		_, _ = fmt.Fprintf(options.CmdOut, "kamelet binding %q migrated to pipe %q\n", binding.Name, pipe.Name)
+	}
+
+	if handover {
+		if err := deleteMigratedBinding(bindingClient, ctx, binding, namespace, v1.DeletePropagationOrphan, options.CmdOut); err != nil {
+			return err
+		}
+	}
+	if existing.Status.Phase != v1alpha1.KameletBindingPhaseReady {
+		if err := waitForBindingReady(pipeClient, ctx, namespace, pipe.Name, resourceVersion, options.WaitTimeout, options.CmdOut); err != nil {
+			if options.Delete && !handover {
+				return fmt.Errorf("kamelet binding %q not deleted: %v", binding.Name, err)
+			}
+			return err
+		}
+	}
+	if options.Delete && !handover {
+		return deleteMigratedBinding(bindingClient, ctx, binding, namespace, v1.DeletePropagationBackground, options.CmdOut)
+	}
+	return nil
+}
+
+// samePipeSpec returns true when the existing Pipe has the spec the binding is migrated to
+func samePipeSpec(pipe *v1alpha1.KameletBinding, binding *v1alpha1.KameletBinding, namespace string) (bool, error) {
+	pipeContent, err := exportContent(pipe, namespace, true)
+	if err != nil {
+		return false, err
+	}
+	bindingContent, err := exportContent(binding, namespace, true)
+	if err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(pipeContent["spec"], bindingContent["spec"]), nil
+}
+
+// ownedByBinding returns true when the owner references point to the given binding
+func ownedByBinding(owners []v1.OwnerReference, binding *v1alpha1.KameletBinding) bool {
+	for _, owner := range owners {
+		if owner.Kind == v1alpha1.KameletBindingKind && owner.Name == binding.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteMigratedBinding deletes the binding with the given propagation policy, the orphan policy keeps its
+// integration running for the Pipe
+func deleteMigratedBinding(client camelkv1alpha1.CamelV1alpha1Interface, ctx context.Context, binding *v1alpha1.KameletBinding,
+	namespace string, propagation v1.DeletionPropagation, cmdOut io.Writer) error {
+	err := client.KameletBindings(namespace).Delete(ctx, binding.Name, v1.DeleteOptions{PropagationPolicy: &propagation})
+	if err != nil {
+		return knerrors.GetError(err)
+	}
+	if propagation == v1.DeletePropagationOrphan {
+		_, _ = fmt.Fprintf(cmdOut, "kamelet binding %q deleted, its integration is taken over by the pipe\n", binding.Name)
+		return nil
+	}
+	_, _ = fmt.Fprintf(cmdOut, "kamelet binding %q deleted\n", binding.Name)
+	return nil
+}
+
+// migratedPipe returns the Pipe replacing the given binding. It keeps the name, labels, annotations and spec, the
+// client converts the Kamelet references and trait configuration to the Pipe format.
+func migratedPipe(binding *v1alpha1.KameletBinding) *v1alpha1.KameletBinding {
+	pipe := &v1alpha1.KameletBinding{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        binding.Name,
+			Namespace:   binding.Namespace,
+			Labels:      binding.Labels,
+			Annotations: binding.Annotations,
+		},
+		Spec: binding.Spec,
+	}
+	pipe = pipe.DeepCopy()
+	delete(pipe.Annotations, corev1.LastAppliedConfigAnnotation)
+	return pipe
+}
+
+// pipeDiff returns the unified diff between the manifest of the binding and the manifest of its Pipe
+func pipeDiff(binding *v1alpha1.KameletBinding, namespace string) (string, error) {
+	bindingContent, err := exportContent(binding, namespace, false)
+	if err != nil {
+		return "", err
+	}
+	bindingManifest, err := yaml.Marshal(bindingContent)
+	if err != nil {
+		return "", err
+	}
+
+	pipeContent, err := exportContent(binding, namespace, true)
+	if err != nil {
+		return "", err
+	}
+	pipeManifest, err := yaml.Marshal(pipeContent)
+	if err != nil {
+		return "", err
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(bindingManifest)),
+		B:        difflib.SplitLines(string(pipeManifest)),
+		FromFile: v1alpha1.KameletBindingKind + "/" + binding.Name,
+		ToFile:   pipeKind + "/" + binding.Name,
+		Context:  3,
+	})
+}
@@ -0,0 +1,326 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"testing"
+
+	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"knative.dev/client-pkg/pkg/commands"
+	"knative.dev/client-pkg/pkg/util"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBindingMigrateDryRun(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	binding := createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current"))
+	binding.Spec.Integration = &camelv1.IntegrationSpec{
+		Traits: map[string]camelv1.TraitSpec{
+			"logging": {Configuration: camelv1.TraitConfiguration{RawMessage: []byte(`{"level":"DEBUG"}`)}},
+		},
+	}
+	recorder.GetKameletBinding(binding, nil)
+
+	dynamicClient := newFakeCamelV1DynamicClient()
+	output, err := runBindingMigrateCmd(mockClient, dynamicClient, "k1-to-channel", "--dry-run")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output,
+		"--- KameletBinding/k1-to-channel", "+++ Pipe/k1-to-channel",
+		"-apiVersion: camel.apache.org/v1alpha1", "+apiVersion: camel.apache.org/v1",
+		"-kind: KameletBinding", "+kind: Pipe",
+		"-        configuration:", "-          level: DEBUG", "+        level: DEBUG"))
+
+	pipes, err := dynamicClient.Resource(pipesV1Resource).Namespace("current").List(context.TODO(), v1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(pipes.Items), 0)
+
+	recorder.Validate()
+}
+
+func TestBindingMigrateDryRunPipesNotServed(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.GetKameletBinding(createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current")), nil)
+
+	output, err := runBindingMigrateCmdWith(mockClient, newFakeCamelV1DynamicClient(), &fakeIntegrationClient{}, &fakeServerResources{},
+		"k1-to-channel", "-n", "current", "--dry-run")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "+kind: Pipe"))
+
+	recorder.Validate()
+}
+
+func TestBindingMigrate(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	binding := createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current"))
+	binding.Labels = map[string]string{"team": "integration"}
+	binding.ResourceVersion = "42"
+	binding.Status = statusReady()
+	recorder.GetKameletBinding(binding, nil)
+
+	dynamicClient := newFakeCamelV1DynamicClient()
+	watchReadyPipe(t, dynamicClient, binding)
+	output, err := runBindingMigrateCmd(mockClient, dynamicClient, "k1-to-channel", "-n", "current")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output,
+		"kamelet binding \"k1-to-channel\" migrated to pipe \"k1-to-channel\"",
+		"Waiting for kamelet binding \"k1-to-channel\" to become ready"))
+	assert.Assert(t, util.ContainsNone(output, "deleted"))
+
+	pipe, err := dynamicClient.Resource(pipesV1Resource).Namespace("current").Get(context.TODO(), "k1-to-channel", v1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, pipe.GetKind(), pipeKind)
+	assert.DeepEqual(t, pipe.GetLabels(), map[string]string{"team": "integration"})
+	assert.Equal(t, pipe.GetResourceVersion(), "")
+	_, found, _ := unstructured.NestedString(pipe.Object, "status", "phase")
+	assert.Assert(t, !found)
+
+	recorder.Validate()
+}
+
+func TestBindingMigrateDelete(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	binding := createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current"))
+	recorder.GetKameletBinding(binding, nil)
+	recorder.DeleteKameletBinding("k1-to-channel", nil)
+
+	dynamicClient := newFakeCamelV1DynamicClient()
+	watchReadyPipe(t, dynamicClient, binding)
+	output, err := runBindingMigrateCmd(mockClient, dynamicClient, "k1-to-channel", "-n", "current", "--delete")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output,
+		"kamelet binding \"k1-to-channel\" migrated to pipe \"k1-to-channel\"",
+		"kamelet binding \"k1-to-channel\" ready",
+		"kamelet binding \"k1-to-channel\" deleted\n"))
+
+	recorder.Validate()
+}
+
+func TestBindingMigrateDeleteHandsOverIntegration(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	binding := createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current"))
+	recorder.GetKameletBinding(binding, nil)
+	recorder.DeleteKameletBinding("k1-to-channel", nil)
+
+	dynamicClient := newFakeCamelV1DynamicClient()
+	watchReadyPipe(t, dynamicClient, binding)
+	integrations := &fakeIntegrationClient{integration: bindingIntegration("k1-to-channel")}
+	output, err := runBindingMigrateCmdWith(mockClient, dynamicClient, integrations, pipesServed(),
+		"k1-to-channel", "-n", "current", "--delete")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output,
+		"kamelet binding \"k1-to-channel\" migrated to pipe \"k1-to-channel\"",
+		"kamelet binding \"k1-to-channel\" deleted, its integration is taken over by the pipe",
+		"kamelet binding \"k1-to-channel\" ready"))
+
+	recorder.Validate()
+}
+
+func TestBindingMigrateErrorCaseIntegrationOwnedByBinding(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.GetKameletBinding(createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current")), nil)
+
+	dynamicClient := newFakeCamelV1DynamicClient()
+	integrations := &fakeIntegrationClient{integration: bindingIntegration("k1-to-channel")}
+	_, err := runBindingMigrateCmdWith(mockClient, dynamicClient, integrations, pipesServed(), "k1-to-channel", "-n", "current")
+	assert.ErrorContains(t, err, "please use --delete to migrate it")
+
+	pipes, err := dynamicClient.Resource(pipesV1Resource).Namespace("current").List(context.TODO(), v1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(pipes.Items), 0)
+
+	recorder.Validate()
+}
+
+func TestBindingMigrateAllExistingPipe(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	pipeBinding := createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current"))
+	pipeBinding.Status = statusReady()
+	pipe, err := toCamelV1(pipeBinding, pipeKind)
+	assert.NilError(t, err)
+
+	recorder.ListBindings(&v1alpha1.KameletBindingList{Items: []v1alpha1.KameletBinding{
+		*createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current")),
+	}}, nil)
+	recorder.DeleteKameletBinding("k1-to-channel", nil)
+
+	output, err := runBindingMigrateCmd(mockClient, newFakeCamelV1DynamicClient(pipe), "--all", "-n", "current", "--delete")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "kamelet binding \"k1-to-channel\" already migrated to pipe \"k1-to-channel\"\n"+
+		"kamelet binding \"k1-to-channel\" deleted\n")
+
+	recorder.Validate()
+}
+
+func TestBindingMigrateErrorCaseDifferentPipe(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	pipe, err := toCamelV1(createKameletBindingInNamespace("k1-to-channel", "k2", "current", channelRef("current")), pipeKind)
+	assert.NilError(t, err)
+
+	recorder.GetKameletBinding(createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current")), nil)
+
+	_, err = runBindingMigrateCmd(mockClient, newFakeCamelV1DynamicClient(pipe), "k1-to-channel", "-n", "current", "--delete")
+	assert.ErrorContains(t, err, "pipe \"k1-to-channel\" already exists with a spec different from kamelet binding \"k1-to-channel\"")
+
+	recorder.Validate()
+}
+
+func TestBindingMigrateErrorCaseBindingNotDeleted(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.GetKameletBinding(createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current")), nil)
+
+	dynamicClient := newFakeCamelV1DynamicClient()
+	dynamicClient.PrependWatchReactor("pipes", func(action clienttesting.Action) (bool, watch.Interface, error) {
+		return true, watch.NewFake(), nil
+	})
+	_, err := runBindingMigrateCmd(mockClient, dynamicClient, "k1-to-channel", "-n", "current", "--delete", "--wait-timeout", "1")
+	assert.Error(t, err, "kamelet binding \"k1-to-channel\" not deleted: timeout: kamelet binding \"k1-to-channel\" not ready after 1 seconds")
+
+	recorder.Validate()
+}
+
+func TestBindingMigrateErrorCasePipesNotServed(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	_, err := runBindingMigrateCmdWith(mockClient, newFakeCamelV1DynamicClient(), &fakeIntegrationClient{}, &fakeServerResources{},
+		"k1-to-channel", "-n", "current")
+	assert.Error(t, err, "the cluster does not serve camel.apache.org/v1 pipes - please upgrade Camel K before migrating kamelet bindings")
+
+	recorder.Validate()
+}
+
+func TestBindingMigrateErrorCaseDeleteAndDryRun(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	_, err := runBindingMigrateCmd(mockClient, newFakeCamelV1DynamicClient(), "k1-to-channel", "--dry-run", "--delete")
+	assert.Error(t, err, "--delete can not be combined with --dry-run")
+
+	recorder.Validate()
+}
+
+func TestBindingMigrateAllEmpty(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.ListBindings(&v1alpha1.KameletBindingList{}, nil)
+
+	output, err := runBindingMigrateCmd(mockClient, newFakeCamelV1DynamicClient(), "--all", "-n", "current")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "No kamelet bindings found in namespace \"current\".\n")
+
+	recorder.Validate()
+}
+
+func TestBindingMigrateErrorCaseMissingArgument(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	_, err := runBindingMigrateCmd(mockClient, newFakeCamelV1DynamicClient())
+	assert.Error(t, err, "'kn-source-kamelet binding migrate' requires the binding name as argument or --all")
+
+	recorder.Validate()
+}
+
+func runBindingMigrateCmd(c *client.MockClient, dynamicClient *dynamicfake.FakeDynamicClient, options ...string) (string, error) {
+	return runBindingMigrateCmdWith(c, dynamicClient, &fakeIntegrationClient{}, pipesServed(), options...)
+}
+
+func runBindingMigrateCmdWith(c *client.MockClient, dynamicClient *dynamicfake.FakeDynamicClient, integrationClient IntegrationClient,
+	resources discovery.ServerResourcesInterface, options ...string) (string, error) {
+	p := KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewCamelClient: func(apiVersion string) (camelkv1alpha1.CamelV1alpha1Interface, error) {
+			if apiVersion == camelAPIVersionV1 {
+				return newCamelV1Client(dynamicClient), nil
+			}
+			return c, nil
+		},
+		NewIntegrationClient: func() (IntegrationClient, error) {
+			return integrationClient, nil
+		},
+		NewServerResources: func() (discovery.ServerResourcesInterface, error) {
+			return resources, nil
+		},
+	}
+
+	migrateCmd, _, output := commands.CreateSourcesTestKnCommand(newBindingMigrateCommand(&p), p.KnParams)
+
+	args := []string{"migrate"}
+	args = append(args, options...)
+	migrateCmd.SetArgs(args)
+	err := migrateCmd.Execute()
+
+	return output.String(), err
+}
+
+func pipesServed() *fakeServerResources {
+	return &fakeServerResources{resources: []v1.APIResource{{Name: "pipes"}}}
+}
+
+// watchReadyPipe lets the Pipe migrated from the binding become ready as soon as it is watched
+func watchReadyPipe(t *testing.T, dynamicClient *dynamicfake.FakeDynamicClient, binding *v1alpha1.KameletBinding) {
+	ready := migratedPipe(binding)
+	ready.ResourceVersion = "2"
+	ready.Status = statusReady()
+	pipe, err := toCamelV1(ready, pipeKind)
+	assert.NilError(t, err)
+	dynamicClient.PrependWatchReactor("pipes", func(action clienttesting.Action) (bool, watch.Interface, error) {
+		watcher := watch.NewFakeWithChanSize(1, false)
+		watcher.Modify(pipe)
+		return true, watcher, nil
+	})
+}
+
+// bindingIntegration returns the integration run by the binding of the given name
+func bindingIntegration(name string) *camelv1.Integration {
+	return &camelv1.Integration{ObjectMeta: v1.ObjectMeta{
+		Name:            name,
+		Namespace:       "current",
+		OwnerReferences: []v1.OwnerReference{{Kind: v1alpha1.KameletBindingKind, Name: name}},
+	}}
+}
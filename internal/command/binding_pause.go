@@ -0,0 +1,208 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+
+	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"knative.dev/client-pkg/pkg/commands"
+	knerrors "knative.dev/client-pkg/pkg/errors"
+)
+
+// pausedReplicasAnnotation marks a paused binding and remembers its replicas, empty when the replicas were not set
+const pausedReplicasAnnotation = "kamelet.knative.dev/paused-replicas"
+
+var bindingPauseExample = `
+  # Stop all pods of a Kamelet binding keeping its configuration
+  kn source kamelet binding pause NAME`
+
+var bindingResumeExample = `
+  # Start a paused Kamelet binding again with the replicas it had before
+  kn source kamelet binding resume NAME`
+
+// newBindingPauseCommand implements 'kn-source-kamelet binding pause' command
+func newBindingPauseCommand(p *KameletPluginParams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "pause NAME",
+		Short:   "Pause a Kamelet binding by scaling it to zero replicas.",
+		Example: bindingPauseExample,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) != 1 {
+				return errors.New("'kn-source-kamelet binding pause' requires the binding name as argument")
+			}
+			name := args[0]
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := p.NewKameletClient()
+			if err != nil {
+				return err
+			}
+
+			return pauseBinding(client, p.Context, name, namespace, cmd.OutOrStdout())
+		},
+	}
+	commands.AddNamespaceFlags(cmd.Flags(), false)
+	return cmd
+}
+
+// newBindingResumeCommand implements 'kn-source-kamelet binding resume' command
+func newBindingResumeCommand(p *KameletPluginParams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "resume NAME",
+		Short:   "Resume a paused Kamelet binding.",
+		Example: bindingResumeExample,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) != 1 {
+				return errors.New("'kn-source-kamelet binding resume' requires the binding name as argument")
+			}
+			name := args[0]
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := p.NewKameletClient()
+			if err != nil {
+				return err
+			}
+
+			return resumeBinding(client, p.Context, name, namespace, cmd.OutOrStdout())
+		},
+	}
+	commands.AddNamespaceFlags(cmd.Flags(), false)
+	return cmd
+}
+
+// pauseBinding scales the binding to zero replicas and remembers its replicas in the paused annotation
+func pauseBinding(client camelkv1alpha1.CamelV1alpha1Interface, ctx context.Context, name string, namespace string, cmdOut io.Writer) error {
+	alreadyPaused := false
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		binding, err := client.KameletBindings(namespace).Get(ctx, name, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		alreadyPaused = isPaused(binding)
+		if alreadyPaused {
+			return nil
+		}
+
+		replicas := ""
+		if binding.Spec.Integration != nil && binding.Spec.Integration.Replicas != nil {
+			replicas = strconv.Itoa(int(*binding.Spec.Integration.Replicas))
+		}
+		if binding.Annotations == nil {
+			binding.Annotations = make(map[string]string)
+		}
+		binding.Annotations[pausedReplicasAnnotation] = replicas
+		zero := int32(0)
+		setBindingReplicas(binding, &zero)
+
+		_, err = client.KameletBindings(namespace).Update(ctx, binding, v1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return knerrors.GetError(err)
+	}
+
+	if alreadyPaused {
+		_, _ = fmt.Fprintf(cmdOut, "kamelet binding %q is already paused\n", name)
+		return nil
+	}
+	_, _ = fmt.Fprintf(cmdOut, "kamelet binding %q paused\n", name)
+	return nil
+}
+
+// resumeBinding restores the replicas remembered in the paused annotation
+func resumeBinding(client camelkv1alpha1.CamelV1alpha1Interface, ctx context.Context, name string, namespace string, cmdOut io.Writer) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		binding, err := client.KameletBindings(namespace).Get(ctx, name, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if !isPaused(binding) {
+			return fmt.Errorf("kamelet binding %q is not paused", name)
+		}
+
+		var replicas *int32
+		if value := binding.Annotations[pausedReplicasAnnotation]; value != "" {
+			parsed, err := strconv.ParseInt(value, 10, 32)
+			if err != nil || parsed < 0 {
+				return fmt.Errorf("invalid replicas %q in annotation %s of kamelet binding %q", value, pausedReplicasAnnotation, name)
+			}
+			count := int32(parsed)
+			replicas = &count
+		}
+		delete(binding.Annotations, pausedReplicasAnnotation)
+		setBindingReplicas(binding, replicas)
+
+		_, err = client.KameletBindings(namespace).Update(ctx, binding, v1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return knerrors.GetError(err)
+	}
+
+	_, _ = fmt.Fprintf(cmdOut, "kamelet binding %q resumed\n", name)
+	return nil
+}
+
+// isPaused returns true when the binding got paused with 'kn-source-kamelet binding pause'
+func isPaused(binding *v1alpha1.KameletBinding) bool {
+	_, ok := binding.Annotations[pausedReplicasAnnotation]
+	return ok
+}
+
+// setBindingReplicas sets the replicas of the binding integration, unsetting the replicas drops an otherwise empty
+// integration spec
+func setBindingReplicas(binding *v1alpha1.KameletBinding, replicas *int32) {
+	if binding.Spec.Integration == nil {
+		if replicas == nil {
+			return
+		}
+		binding.Spec.Integration = &camelv1.IntegrationSpec{}
+	}
+	binding.Spec.Integration.Replicas = replicas
+	if reflect.DeepEqual(*binding.Spec.Integration, camelv1.IntegrationSpec{}) {
+		binding.Spec.Integration = nil
+	}
+}
+
+// desiredReplicas returns the replicas of the binding, Camel K runs a single pod when the replicas are not set
+func desiredReplicas(binding *v1alpha1.KameletBinding) int32 {
+	if binding.Spec.Integration != nil && binding.Spec.Integration.Replicas != nil {
+		return *binding.Spec.Integration.Replicas
+	}
+	return 1
+}
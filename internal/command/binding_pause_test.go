@@ -0,0 +1,150 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"testing"
+
+	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	"github.com/spf13/cobra"
+	"knative.dev/client-pkg/pkg/commands"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBindingPause(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	existing := createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current"))
+	replicas := int32(2)
+	existing.Spec.Integration = &camelv1.IntegrationSpec{Replicas: &replicas}
+	recorder.GetKameletBinding(existing.DeepCopy(), nil)
+
+	zero := int32(0)
+	expected := existing.DeepCopy()
+	expected.Annotations = map[string]string{pausedReplicasAnnotation: "2"}
+	expected.Spec.Integration.Replicas = &zero
+	recorder.UpdateKameletBinding(expected, nil)
+
+	output, err := runBindingScaleCmd(mockClient, newBindingPauseCommand, "pause", "k1-to-channel", "-n", "current")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "kamelet binding \"k1-to-channel\" paused\n")
+
+	recorder.Validate()
+}
+
+func TestBindingPauseAlreadyPaused(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	existing := createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current"))
+	existing.Annotations = map[string]string{pausedReplicasAnnotation: ""}
+	recorder.GetKameletBinding(existing, nil)
+
+	output, err := runBindingScaleCmd(mockClient, newBindingPauseCommand, "pause", "k1-to-channel", "-n", "current")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "kamelet binding \"k1-to-channel\" is already paused\n")
+
+	recorder.Validate()
+}
+
+func TestBindingPauseErrorCaseMissingArgument(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	_, err := runBindingScaleCmd(mockClient, newBindingPauseCommand, "pause")
+	assert.Error(t, err, "'kn-source-kamelet binding pause' requires the binding name as argument")
+
+	recorder.Validate()
+}
+
+func TestBindingResume(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	existing := createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current"))
+	zero := int32(0)
+	existing.Annotations = map[string]string{pausedReplicasAnnotation: "2"}
+	existing.Spec.Integration = &camelv1.IntegrationSpec{Replicas: &zero}
+	recorder.GetKameletBinding(existing.DeepCopy(), nil)
+
+	replicas := int32(2)
+	expected := existing.DeepCopy()
+	expected.Annotations = map[string]string{}
+	expected.Spec.Integration.Replicas = &replicas
+	recorder.UpdateKameletBinding(expected, nil)
+
+	output, err := runBindingScaleCmd(mockClient, newBindingResumeCommand, "resume", "k1-to-channel", "-n", "current")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "kamelet binding \"k1-to-channel\" resumed\n")
+
+	recorder.Validate()
+}
+
+func TestBindingResumeUnsetReplicas(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	existing := createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current"))
+	zero := int32(0)
+	existing.Annotations = map[string]string{pausedReplicasAnnotation: ""}
+	existing.Spec.Integration = &camelv1.IntegrationSpec{Replicas: &zero}
+	recorder.GetKameletBinding(existing.DeepCopy(), nil)
+
+	expected := existing.DeepCopy()
+	expected.Annotations = map[string]string{}
+	expected.Spec.Integration = nil
+	recorder.UpdateKameletBinding(expected, nil)
+
+	_, err := runBindingScaleCmd(mockClient, newBindingResumeCommand, "resume", "k1-to-channel", "-n", "current")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+}
+
+func TestBindingResumeErrorCaseNotPaused(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.GetKameletBinding(createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current")), nil)
+
+	_, err := runBindingScaleCmd(mockClient, newBindingResumeCommand, "resume", "k1-to-channel", "-n", "current")
+	assert.Error(t, err, "kamelet binding \"k1-to-channel\" is not paused")
+
+	recorder.Validate()
+}
+
+func runBindingScaleCmd(c *client.MockClient, newCommand func(p *KameletPluginParams) *cobra.Command, options ...string) (string, error) {
+	p := KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewKameletClient: func() (camelkv1alpha1.CamelV1alpha1Interface, error) {
+			return c, nil
+		},
+	}
+
+	cmd, _, output := commands.CreateSourcesTestKnCommand(newCommand(&p), p.KnParams)
+
+	cmd.SetArgs(options)
+	err := cmd.Execute()
+
+	return output.String(), err
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"knative.dev/client-pkg/pkg/commands"
+	knerrors "knative.dev/client-pkg/pkg/errors"
+)
+
+var bindingScaleExample = `
+  # Run the Kamelet binding with 3 pods
+  kn source kamelet binding scale NAME --replicas 3`
+
+// newBindingScaleCommand implements 'kn-source-kamelet binding scale' command
+func newBindingScaleCommand(p *KameletPluginParams) *cobra.Command {
+	var replicas int32
+
+	cmd := &cobra.Command{
+		Use:     "scale NAME --replicas N",
+		Short:   "Set the number of pods running a Kamelet binding.",
+		Example: bindingScaleExample,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) != 1 {
+				return errors.New("'kn-source-kamelet binding scale' requires the binding name as argument")
+			}
+			name := args[0]
+
+			if !cmd.Flags().Changed("replicas") {
+				return errors.New("'kn-source-kamelet binding scale' requires the number of replicas with --replicas")
+			}
+			if replicas < 0 {
+				return fmt.Errorf("invalid number of replicas %d - please use a number greater or equal to 0", replicas)
+			}
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := p.NewKameletClient()
+			if err != nil {
+				return err
+			}
+
+			return scaleBinding(client, p.Context, name, namespace, replicas, cmd.OutOrStdout())
+		},
+	}
+	flags := cmd.Flags()
+	commands.AddNamespaceFlags(flags, false)
+	flags.Int32Var(&replicas, "replicas", 0, "Number of integration pods, scaling a paused binding resumes it.")
+	return cmd
+}
+
+// scaleBinding sets the replicas of the binding. A paused binding is resumed with the given replicas.
+func scaleBinding(client camelkv1alpha1.CamelV1alpha1Interface, ctx context.Context, name string, namespace string, replicas int32, cmdOut io.Writer) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		binding, err := client.KameletBindings(namespace).Get(ctx, name, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		delete(binding.Annotations, pausedReplicasAnnotation)
+		count := replicas
+		setBindingReplicas(binding, &count)
+
+		_, err = client.KameletBindings(namespace).Update(ctx, binding, v1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return knerrors.GetError(err)
+	}
+
+	_, _ = fmt.Fprintf(cmdOut, "kamelet binding %q scaled to %d replicas\n", name, replicas)
+	return nil
+}
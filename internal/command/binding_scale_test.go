@@ -0,0 +1,87 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"testing"
+
+	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBindingScale(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	existing := createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current"))
+	recorder.GetKameletBinding(existing.DeepCopy(), nil)
+
+	replicas := int32(3)
+	expected := existing.DeepCopy()
+	expected.Spec.Integration = &camelv1.IntegrationSpec{Replicas: &replicas}
+	recorder.UpdateKameletBinding(expected, nil)
+
+	output, err := runBindingScaleCmd(mockClient, newBindingScaleCommand, "scale", "k1-to-channel", "--replicas", "3", "-n", "current")
+	assert.NilError(t, err)
+	assert.Equal(t, output, "kamelet binding \"k1-to-channel\" scaled to 3 replicas\n")
+
+	recorder.Validate()
+}
+
+func TestBindingScalePaused(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	existing := createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current"))
+	zero := int32(0)
+	existing.Annotations = map[string]string{pausedReplicasAnnotation: "2"}
+	existing.Spec.Integration = &camelv1.IntegrationSpec{Replicas: &zero}
+	recorder.GetKameletBinding(existing.DeepCopy(), nil)
+
+	replicas := int32(1)
+	expected := existing.DeepCopy()
+	expected.Annotations = map[string]string{}
+	expected.Spec.Integration.Replicas = &replicas
+	recorder.UpdateKameletBinding(expected, nil)
+
+	_, err := runBindingScaleCmd(mockClient, newBindingScaleCommand, "scale", "k1-to-channel", "--replicas", "1", "-n", "current")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+}
+
+func TestBindingScaleErrorCaseMissingReplicas(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	_, err := runBindingScaleCmd(mockClient, newBindingScaleCommand, "scale", "k1-to-channel")
+	assert.Error(t, err, "'kn-source-kamelet binding scale' requires the number of replicas with --replicas")
+
+	recorder.Validate()
+}
+
+func TestBindingScaleErrorCaseInvalidReplicas(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	_, err := runBindingScaleCmd(mockClient, newBindingScaleCommand, "scale", "k1-to-channel", "--replicas", "-1")
+	assert.Error(t, err, "invalid number of replicas -1 - please use a number greater or equal to 0")
+
+	recorder.Validate()
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	knerrors "knative.dev/client-pkg/pkg/errors"
+)
+
+// Steps are stored in spec.steps of KameletBindings and Pipes. The vendored v1alpha1 types lack that field, so
+// while the commands work on a binding its steps sit in this annotation. The dynamic clients and printers move them
+// into spec.steps, the annotation never reaches the cluster or the printed manifests.
+const (
+	stepsAnnotation    = "kamelet.knative.dev/steps"
+	stepPropertyPrefix = "step"
+)
+
+// StepFlags holding the action Kamelets processing the events between source and sink of a binding
+type StepFlags struct {
+	// Kamelets of the steps in the order of processing
+	Kamelets []string
+	// Properties of the steps in the form of "step<index>.<key>=<value>"
+	Properties []string
+}
+
+// AddFlags adds the --step and --step-property flags to the given flag set
+func (f *StepFlags) AddFlags(flags *pflag.FlagSet) {
+	flags.StringArrayVar(&f.Kamelets, "step", nil, "Action Kamelet processing the events between source and sink, repeat to chain multiple steps.")
+	flags.StringArrayVar(&f.Properties, "step-property", nil, `Property of a step in the form of "step<index>.<key>=<value>", steps are counted from 1 in the order of --step.`)
+}
+
+// resolve creates the step endpoints. Each step must be an action Kamelet and its properties are validated against
+// the Kamelet definition.
+func (f *StepFlags) resolve(ctx context.Context, client camelkv1alpha1.CamelV1alpha1Interface, namespace string) ([]v1alpha1.Endpoint, error) {
+	if f == nil {
+		return nil, nil
+	}
+	properties, err := parseStepProperties(f.Properties, len(f.Kamelets))
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make([]v1alpha1.Endpoint, 0, len(f.Kamelets))
+	for i, name := range f.Kamelets {
+		kamelet, err := client.Kamelets(namespace).Get(ctx, name, v1.GetOptions{})
+		if err != nil {
+			return nil, knerrors.GetError(err)
+		}
+		if !isActionType(kamelet) {
+			return nil, fmt.Errorf("kamelet %s is not an action - only action Kamelets can be used with --step", name)
+		}
+
+		props, err := asEndpointProperties(properties[i])
+		if err != nil {
+			return nil, err
+		}
+		step := v1alpha1.Endpoint{
+			Properties: &props,
+			Ref: &corev1.ObjectReference{
+				Kind:       v1alpha1.KameletKind,
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Name:       kamelet.Name,
+				Namespace:  kamelet.Namespace,
+			},
+		}
+		if err := coerceEndpointProperties(kamelet, step.Properties); err != nil {
+			return nil, err
+		}
+		if err := verifyProperties(kamelet, step); err != nil {
+			return nil, fmt.Errorf("step%d: %w", i+1, err)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// parseStepProperties returns the properties of each of the given number of steps
+func parseStepProperties(properties []string, count int) ([]map[string]string, error) {
+	result := make([]map[string]string, count)
+	for i := range result {
+		result[i] = make(map[string]string)
+	}
+	for _, prop := range properties {
+		key, value, err := parseProperty(prop)
+		if err != nil {
+			return nil, err
+		}
+		step, name, found := strings.Cut(key, ".")
+		index, err := strconv.Atoi(strings.TrimPrefix(step, stepPropertyPrefix))
+		if !found || name == "" || !strings.HasPrefix(step, stepPropertyPrefix) || err != nil {
+			return nil, fmt.Errorf(`step property %q does not follow format "step<index>.<key>=<value>"`, prop)
+		}
+		if index < 1 || index > count {
+			return nil, fmt.Errorf("step property %q refers to step %d, but %d step(s) are given with --step", prop, index, count)
+		}
+		result[index-1][name] = value
+	}
+	return result, nil
+}
+
+// bindingSteps returns the steps carried in the annotation of the given binding
+func bindingSteps(binding *v1alpha1.KameletBinding) ([]v1alpha1.Endpoint, error) {
+	data, ok := binding.Annotations[stepsAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var steps []v1alpha1.Endpoint
+	if err := json.Unmarshal([]byte(data), &steps); err != nil {
+		return nil, fmt.Errorf("invalid steps of kamelet binding %q: %w", binding.Name, err)
+	}
+	return steps, nil
+}
+
+// setBindingSteps stores the given steps in the annotation of the binding
+func setBindingSteps(binding *v1alpha1.KameletBinding, steps []v1alpha1.Endpoint) error {
+	if len(steps) == 0 {
+		delete(binding.Annotations, stepsAnnotation)
+		return nil
+	}
+	data, err := json.Marshal(steps)
+	if err != nil {
+		return err
+	}
+	if binding.Annotations == nil {
+		binding.Annotations = make(map[string]string)
+	}
+	binding.Annotations[stepsAnnotation] = string(data)
+	return nil
+}
+
+// stepsToSpec moves the steps carried in the annotation of a binding into the spec of the Pipe content
+func stepsToSpec(content map[string]interface{}) {
+	annotations, _, _ := unstructured.NestedStringMap(content, "metadata", "annotations")
+	data, ok := annotations[stepsAnnotation]
+	if !ok {
+		return
+	}
+	var steps []interface{}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(data)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&steps); err != nil {
+		return
+	}
+
+	delete(annotations, stepsAnnotation)
+	if len(annotations) == 0 {
+		unstructured.RemoveNestedField(content, "metadata", "annotations")
+	} else {
+		_ = unstructured.SetNestedStringMap(content, annotations, "metadata", "annotations")
+	}
+	_ = unstructured.SetNestedSlice(content, steps, "spec", "steps")
+}
+
+// stepsToAnnotation moves the steps of the Pipe content into the annotation of the binding
+func stepsToAnnotation(content map[string]interface{}) {
+	steps, found, err := unstructured.NestedSlice(content, "spec", "steps")
+	if !found || err != nil {
+		return
+	}
+	unstructured.RemoveNestedField(content, "spec", "steps")
+	if len(steps) == 0 {
+		return
+	}
+	data, err := json.Marshal(steps)
+	if err != nil {
+		return
+	}
+	_ = unstructured.SetNestedField(content, string(data), "metadata", "annotations", stepsAnnotation)
+}
@@ -0,0 +1,224 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"knative.dev/client-pkg/pkg/commands"
+	"knative.dev/client-pkg/pkg/util"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBindingCreateSteps(t *testing.T) {
+	dynamicClient := newFakeStepsDynamicClient(t)
+
+	output, err := runBindingCreateStepsCmd(dynamicClient, "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo",
+		"--step", "a1", "--step-property", "step1.a1_prop=bar", "--step", "a2", "--step-property", "step2.a2_prop=baz", "--step-property", "step2.a2_optional=true")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kamelet binding \"k1-to-channel\" created"))
+
+	pipe, err := dynamicClient.Resource(pipesV1Resource).Namespace("current").Get(context.TODO(), "k1-to-channel", v1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(pipe.GetAnnotations()), 0)
+	steps, _, err := unstructured.NestedSlice(pipe.Object, "spec", "steps")
+	assert.NilError(t, err)
+	assert.Equal(t, len(steps), 2)
+	for i, expected := range []struct {
+		name     string
+		property string
+		value    interface{}
+	}{
+		{name: "a1", property: "a1_prop", value: "bar"},
+		{name: "a2", property: "a2_optional", value: true},
+	} {
+		step := steps[i].(map[string]interface{})
+		name, _, _ := unstructured.NestedString(step, "ref", "name")
+		assert.Equal(t, name, expected.name)
+		apiVersion, _, _ := unstructured.NestedString(step, "ref", "apiVersion")
+		assert.Equal(t, apiVersion, "camel.apache.org/v1")
+		value, _, _ := unstructured.NestedFieldNoCopy(step, "properties", expected.property)
+		assert.Equal(t, value, expected.value)
+	}
+
+	// the steps survive the round trip through the v1alpha1 binding
+	binding, err := newCamelV1Client(dynamicClient).KameletBindings("current").Get(context.TODO(), "k1-to-channel", v1.GetOptions{})
+	assert.NilError(t, err)
+	carried, err := bindingSteps(binding)
+	assert.NilError(t, err)
+	assert.Equal(t, len(carried), 2)
+	assert.Equal(t, carried[0].Ref.APIVersion, v1alpha1.SchemeGroupVersion.String())
+	assert.Equal(t, carried[1].Ref.Name, "a2")
+
+	p := camelV1PluginParams(dynamicClient)
+	describeCmd, _, describeOutput := commands.CreateSourcesTestKnCommand(newBindingDescribeCommand(p), p.KnParams)
+	describeCmd.SetArgs([]string{"describe", "k1-to-channel", "-n", "current"})
+	assert.NilError(t, describeCmd.Execute())
+	assert.Assert(t, util.ContainsAll(describeOutput.String(), "Step 1", "a1", "a1_prop", "bar", "Step 2", "a2", "a2_prop", "baz"))
+}
+
+func TestBindingCreateStepsDryRun(t *testing.T) {
+	output, err := runBindingCreateStepsCmd(newFakeStepsDynamicClient(t), "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo",
+		"--step", "a1", "--step-property", "step1.a1_prop=bar", "--dry-run=client")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kind: Pipe", "steps:", "name: a1", "a1_prop: bar"))
+	assert.Assert(t, util.ContainsNone(output, stepsAnnotation))
+}
+
+func TestBindingCreateStepsErrorCases(t *testing.T) {
+	dynamicClient := newFakeStepsDynamicClient(t)
+	for _, tc := range []struct {
+		args     []string
+		expected string
+	}{
+		{
+			args:     []string{"--step", "k1", "--step-property", "step1.k1_prop=bar"},
+			expected: "kamelet k1 is not an action - only action Kamelets can be used with --step",
+		},
+		{
+			args:     []string{"--step", "a1"},
+			expected: "step1: binding is missing required property \"a1_prop\" for Kamelet \"a1\"",
+		},
+		{
+			args:     []string{"--step", "a1", "--step-property", "a1_prop=bar"},
+			expected: "step property \"a1_prop=bar\" does not follow format \"step<index>.<key>=<value>\"",
+		},
+		{
+			args:     []string{"--step", "a1", "--step-property", "step2.a1_prop=bar"},
+			expected: "step property \"step2.a1_prop=bar\" refers to step 2, but 1 step(s) are given with --step",
+		},
+		{
+			args:     []string{"--step-property", "step1.a1_prop=bar"},
+			expected: "step property \"step1.a1_prop=bar\" refers to step 1, but 0 step(s) are given with --step",
+		},
+		{
+			args:     []string{"--step", "unknown"},
+			expected: "kamelets.camel.apache.org \"unknown\" not found",
+		},
+	} {
+		args := append([]string{"k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo"}, tc.args...)
+		_, err := runBindingCreateStepsCmd(dynamicClient, args...)
+		assert.ErrorContains(t, err, tc.expected)
+	}
+
+	pipes, err := dynamicClient.Resource(pipesV1Resource).Namespace("current").List(context.TODO(), v1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(pipes.Items), 0)
+}
+
+func TestBindingCreateStepsKameletBindings(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKameletInNamespace("k1", "current"), nil)
+	recorder.Get(createActionKameletInNamespace("a1", "current"), nil)
+
+	dynamicClient := newFakeCamelV1DynamicClient()
+	p := &KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewKameletClient: func() (camelkv1alpha1.CamelV1alpha1Interface, error) {
+			return newCamelV1alpha1Client(mockClient, dynamicClient), nil
+		},
+	}
+	command, _, output := commands.CreateSourcesTestKnCommand(newBindingCreateCommand(p), p.KnParams)
+	command.SetArgs([]string{"create", "k1-to-channel", "-n", "current", "--no-wait", "--kamelet", "k1", "--channel", "test",
+		"--property", "k1_prop=foo", "--step", "a1", "--step-property", "step1.a1_prop=bar"})
+	assert.NilError(t, command.Execute())
+	assert.Assert(t, util.ContainsAll(output.String(), "kamelet binding \"k1-to-channel\" created"))
+
+	binding, err := dynamicClient.Resource(kameletBindingsV1alpha1Resource).Namespace("current").Get(context.TODO(), "k1-to-channel", v1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, binding.GetKind(), v1alpha1.KameletBindingKind)
+	assert.Equal(t, len(binding.GetAnnotations()), 0)
+	steps, _, err := unstructured.NestedSlice(binding.Object, "spec", "steps")
+	assert.NilError(t, err)
+	assert.Equal(t, len(steps), 1)
+	step := steps[0].(map[string]interface{})
+	name, _, _ := unstructured.NestedString(step, "ref", "name")
+	assert.Equal(t, name, "a1")
+	apiVersion, _, _ := unstructured.NestedString(step, "ref", "apiVersion")
+	assert.Equal(t, apiVersion, v1alpha1.SchemeGroupVersion.String())
+	value, _, _ := unstructured.NestedString(step, "properties", "a1_prop")
+	assert.Equal(t, value, "bar")
+
+	// updates keep the steps unknown to the typed client
+	bindings := newCamelV1alpha1Client(mockClient, dynamicClient).KameletBindings("current")
+	read, err := bindings.Get(context.TODO(), "k1-to-channel", v1.GetOptions{})
+	assert.NilError(t, err)
+	_, err = bindings.Update(context.TODO(), read, v1.UpdateOptions{})
+	assert.NilError(t, err)
+	binding, err = dynamicClient.Resource(kameletBindingsV1alpha1Resource).Namespace("current").Get(context.TODO(), "k1-to-channel", v1.GetOptions{})
+	assert.NilError(t, err)
+	steps, _, _ = unstructured.NestedSlice(binding.Object, "spec", "steps")
+	assert.Equal(t, len(steps), 1)
+
+	recorder.Validate()
+}
+
+func TestBindingCreateStepsKameletBindingsDryRun(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKameletInNamespace("k1", "current"), nil)
+	recorder.Get(createActionKameletInNamespace("a1", "current"), nil)
+
+	output, err := runBindingCreateCmdWithOutput(mockClient, "k1-to-channel", "-n", "current", "--kamelet", "k1", "--channel", "test",
+		"--property", "k1_prop=foo", "--step", "a1", "--step-property", "step1.a1_prop=bar", "--dry-run=client")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kind: KameletBinding", "steps:", "name: a1", "a1_prop: bar"))
+	assert.Assert(t, util.ContainsNone(output, stepsAnnotation))
+
+	recorder.Validate()
+}
+
+// newFakeStepsDynamicClient serves the source Kamelet k1 and the action Kamelets a1 and a2 as camel.apache.org/v1 Kamelets
+func newFakeStepsDynamicClient(t *testing.T) *dynamicfake.FakeDynamicClient {
+	var objects []runtime.Object
+	for _, kamelet := range []*v1alpha1.Kamelet{
+		createKameletInNamespace("k1", "current"),
+		createActionKameletInNamespace("a1", "current"),
+		createActionKameletInNamespace("a2", "current"),
+	} {
+		obj, err := toCamelV1(kamelet, v1alpha1.KameletKind)
+		assert.NilError(t, err)
+		objects = append(objects, obj)
+	}
+	return newFakeCamelV1DynamicClient(objects...)
+}
+
+func runBindingCreateStepsCmd(dynamicClient *dynamicfake.FakeDynamicClient, options ...string) (string, error) {
+	p := camelV1PluginParams(dynamicClient)
+
+	command, _, output := commands.CreateSourcesTestKnCommand(newBindingCreateCommand(p), p.KnParams)
+
+	args := []string{"create", "-n", "current", "--no-wait"}
+	args = append(args, options...)
+	command.SetArgs(args)
+	err := command.Execute()
+
+	return output.String(), err
+}
@@ -0,0 +1,279 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"knative.dev/client-pkg/pkg/commands"
+	knerrors "knative.dev/client-pkg/pkg/errors"
+)
+
+var bindingUpdateExample = `
+  # Update the sink of a Kamelet binding.
+  kn source kamelet binding update NAME --broker=<name>
+
+  # Add or change a source property and remove another one
+  kn source kamelet binding update NAME --property=<key>=<value> --property=<key>-`
+
+// newBindingUpdateCommand implements 'kn-source-kamelet binding update' command
+func newBindingUpdateCommand(p *KameletPluginParams) *cobra.Command {
+	var properties []string
+	var sink string
+	var broker string
+	var channel string
+	var service string
+	var cloudEventsOverride []string
+	var cloudEventsSpecVersion string
+	var cloudEventsType string
+	var verifySink bool
+	var waitFlags WaitFlags
+	var integrationFlags IntegrationFlags
+
+	cmd := &cobra.Command{
+		Use:     "update NAME",
+		Short:   "Update Kamelet binding source properties and sink.",
+		Example: bindingUpdateExample,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) != 1 {
+				return errors.New("'kn-source-kamelet binding update' requires the binding name as argument")
+			}
+			name := args[0]
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+
+			client, err := p.NewKameletClient()
+			if err != nil {
+				return err
+			}
+
+			properties, err := readPropertyValueFiles(properties)
+			if err != nil {
+				return err
+			}
+
+			resolver, err := p.newSinkResolver(verifySink)
+			if err != nil {
+				return err
+			}
+
+			options := UpdateBindingOptions{
+				Name:                   name,
+				SourceProperties:       properties,
+				Sink:                   sink,
+				CloudEventsOverride:    cloudEventsOverride,
+				CloudEventsSpecVersion: cloudEventsSpecVersion,
+				CloudEventsType:        cloudEventsType,
+				Broker:                 broker,
+				Channel:                channel,
+				Service:                service,
+				Wait:                   waitFlags.Wait,
+				WaitTimeout:            waitFlags.TimeoutInSeconds,
+				Integration:            &integrationFlags,
+				CmdOut:                 cmd.OutOrStdout(),
+			}
+
+			err = updateBinding(client, resolver, p.Context, namespace, options)
+			if err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	commands.AddNamespaceFlags(flags, false)
+
+	flags.StringVarP(&sink, "sink", "s", "", "Sink expression to define the binding sink. Use an http or https URI to send events to an endpoint address.")
+	flags.StringVar(&broker, "broker", "", "Uses a broker as binding sink.")
+	flags.StringVar(&channel, "channel", "", "Uses a channel as binding sink.")
+	flags.StringVar(&service, "service", "", "Uses a Knative service as binding sink.")
+	flags.StringArrayVar(&properties, "property", nil, `Add or update a Kamelet property in the form of "<key>=<value>" or "<key>=@<file>", remove a property with "<key>-"`)
+	flags.StringVar(&cloudEventsSpecVersion, "ce-spec", "", "Customize cloud events spec version provided to the binding sink.")
+	flags.StringVar(&cloudEventsType, "ce-type", "", "Customize cloud events type provided to the binding sink.")
+	flags.StringArrayVar(&cloudEventsOverride, "ce-override", nil, `Customize cloud events property in the form of "<key>=<value>", remove an override with "<key>-"`)
+	flags.BoolVar(&verifySink, "verify-sink", false, "Verify that the sink exists and is addressable before applying the binding.")
+	integrationFlags.AddFlags(flags, true)
+	waitFlags.AddFlags(flags)
+	return cmd
+}
+
+func updateBinding(client camelkv1alpha1.CamelV1alpha1Interface, resolver *sinkResolver, ctx context.Context, namespace string, options UpdateBindingOptions) error {
+	var updated *v1alpha1.KameletBinding
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		binding, err := client.KameletBindings(namespace).Get(ctx, options.Name, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if err := updateBindingKamelet(client, ctx, binding, options); err != nil {
+			return err
+		}
+
+		if err := updateBindingSink(ctx, resolver, binding, options); err != nil {
+			return err
+		}
+
+		binding.Spec.Integration, err = options.Integration.apply(binding.Spec.Integration)
+		if err != nil {
+			return err
+		}
+		if options.Integration != nil && options.Integration.replicasSpecified() {
+			// explicitly set replicas end a pause
+			delete(binding.Annotations, pausedReplicasAnnotation)
+		}
+
+		updated, err = client.KameletBindings(namespace).Update(ctx, binding, v1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return knerrors.GetError(err)
+	}
+
+	_, _ = fmt.Fprintf(options.CmdOut, "kamelet binding %q updated\n", options.Name)
+
+	if options.Wait {
+		return waitForBindingReady(client, ctx, namespace, options.Name, updated.ResourceVersion, options.WaitTimeout, options.CmdOut)
+	}
+
+	return nil
+}
+
+// updateBindingKamelet merges the property changes into the Kamelet endpoint of the binding and
+// verifies the result against the referenced Kamelet. The Kamelet endpoint is the binding source
+// unless the binding uses a Kamelet sink.
+func updateBindingKamelet(client camelkv1alpha1.CamelV1alpha1Interface, ctx context.Context, binding *v1alpha1.KameletBinding, options UpdateBindingOptions) error {
+	endpoint := &binding.Spec.Source
+	if !isKameletEndpoint(*endpoint) {
+		endpoint = &binding.Spec.Sink
+	}
+	if !isKameletEndpoint(*endpoint) {
+		return fmt.Errorf("kamelet binding %q does not reference a Kamelet", binding.Name)
+	}
+
+	kameletNamespace := endpoint.Ref.Namespace
+	if kameletNamespace == "" {
+		kameletNamespace = binding.Namespace
+	}
+	kamelet, err := client.Kamelets(kameletNamespace).Get(ctx, endpoint.Ref.Name, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	props, err := mergeProperties(endpoint.Properties, options.SourceProperties, "")
+	if err != nil {
+		return err
+	}
+	endpoint.Properties = &props
+
+	if err := coerceEndpointProperties(kamelet, endpoint.Properties); err != nil {
+		return err
+	}
+
+	return verifyProperties(kamelet, *endpoint)
+}
+
+// updateBindingSink applies a changed sink reference and the cloud events settings to the binding sink. The sink
+// of a binding with a Kamelet sink can not be changed.
+func updateBindingSink(ctx context.Context, resolver *sinkResolver, binding *v1alpha1.KameletBinding, options UpdateBindingOptions) error {
+	sink := sinkExpressionFor(options.Sink, options.Broker, options.Channel, options.Service)
+	if isKameletEndpoint(binding.Spec.Sink) {
+		if sink != "" {
+			return fmt.Errorf("kamelet %s is the sink of kamelet binding %q - --sink, --broker, --channel and --service can not be used", binding.Spec.Sink.Ref.Name, binding.Name)
+		}
+		if options.CloudEventsSpecVersion != "" || options.CloudEventsType != "" || len(options.CloudEventsOverride) > 0 {
+			return fmt.Errorf("cloud events settings are not supported for Kamelet sink %s", binding.Spec.Sink.Ref.Name)
+		}
+		return nil
+	}
+
+	if sink != "" {
+		sinkEndpoint, err := resolver.resolve(ctx, sink, binding.Namespace)
+		if err != nil {
+			return err
+		}
+		binding.Spec.Sink.Ref = sinkEndpoint.Ref
+		binding.Spec.Sink.URI = sinkEndpoint.URI
+	}
+
+	var settings []string
+	if options.CloudEventsSpecVersion != "" {
+		settings = append(settings, cloudEventsSpecVersionProperty+"="+options.CloudEventsSpecVersion)
+	}
+	if options.CloudEventsType != "" {
+		settings = append(settings, cloudEventsTypeProperty+"="+options.CloudEventsType)
+	}
+
+	sinkProps, err := mergeProperties(binding.Spec.Sink.Properties, settings, "")
+	if err != nil {
+		return err
+	}
+	sinkProps, err = mergeProperties(&sinkProps, options.CloudEventsOverride, cloudEventsOverridePrefix)
+	if err != nil {
+		return err
+	}
+	binding.Spec.Sink.Properties = &sinkProps
+
+	return nil
+}
+
+// mergeProperties applies the given changes to the endpoint properties. Changes in the form of "<key>=<value>"
+// add or update a property and changes in the form of "<key>-" remove the property. Each key gets the given
+// prefix. Existing property values keep their JSON type.
+func mergeProperties(existing *v1alpha1.EndpointProperties, changes []string, prefix string) (v1alpha1.EndpointProperties, error) {
+	props, err := decodeEndpointProperties(existing)
+	if err != nil {
+		return v1alpha1.EndpointProperties{}, err
+	}
+
+	for _, change := range changes {
+		if !strings.Contains(change, "=") && strings.HasSuffix(change, "-") {
+			delete(props, prefix+strings.TrimSuffix(change, "-"))
+			continue
+		}
+		key, value, err := parseProperty(change)
+		if err != nil {
+			return v1alpha1.EndpointProperties{}, err
+		}
+		props[prefix+key] = value
+	}
+
+	if len(props) == 0 {
+		return v1alpha1.EndpointProperties{}, nil
+	}
+	data, err := json.Marshal(props)
+	if err != nil {
+		return v1alpha1.EndpointProperties{}, err
+	}
+	return v1alpha1.EndpointProperties{
+		RawMessage: camelv1.RawMessage(data),
+	}, nil
+}
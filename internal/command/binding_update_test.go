@@ -0,0 +1,400 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/watch"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	"knative.dev/client-pkg/pkg/commands"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBindingUpdateSetup(t *testing.T) {
+	p := KameletPluginParams{
+		Context: context.TODO(),
+	}
+
+	command := newBindingUpdateCommand(&p)
+	assert.Equal(t, command.Use, "update NAME")
+	assert.Equal(t, command.Short, "Update Kamelet binding source properties and sink.")
+}
+
+func TestBindingUpdateErrorCaseMissingArgument(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	err := runBindingUpdateCmd(mockClient)
+	assert.Error(t, err, "'kn-source-kamelet binding update' requires the binding name as argument")
+	recorder.Validate()
+}
+
+func TestBindingUpdateErrorCaseNotFound(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.GetKameletBinding(&v1alpha1.KameletBinding{}, errors.New("not found"))
+
+	err := runBindingUpdateCmd(mockClient, "k1-to-channel", "--property", "k1_prop=bar")
+	assert.Error(t, err, "not found")
+	recorder.Validate()
+}
+
+func TestBindingUpdateErrorCaseUnknownProperty(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	recorder.GetKameletBinding(createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace)), nil)
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+
+	err := runBindingUpdateCmd(mockClient, "k1-to-channel", "--property", "foo=unknown")
+	assert.Error(t, err, "binding uses unknown property \"foo\" for Kamelet \"k1\"")
+	recorder.Validate()
+}
+
+func TestBindingUpdateErrorCaseRemoveRequiredProperty(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	recorder.GetKameletBinding(createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace)), nil)
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+
+	err := runBindingUpdateCmd(mockClient, "k1-to-channel", "--property", "k1_prop-")
+	assert.Error(t, err, "binding is missing required property \"k1_prop\" for Kamelet \"k1\"")
+	recorder.Validate()
+}
+
+func TestBindingUpdateProperties(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	existing := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	existing.Labels = map[string]string{"app": "test"}
+	existing.Annotations = map[string]string{"note": "keep"}
+	existing.Spec.Integration = &camelv1.IntegrationSpec{Profile: camelv1.TraitProfileKnative}
+	existing.Spec.Source.Properties.RawMessage = []byte("{\"k1_optional\":true,\"k1_prop\":\"foo\"}")
+	recorder.GetKameletBinding(existing.DeepCopy(), nil)
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+
+	expected := existing.DeepCopy()
+	expected.Spec.Source.Properties.RawMessage = []byte("{\"k1_prop\":\"bar\"}")
+	recorder.UpdateKameletBinding(expected, nil)
+
+	err := runBindingUpdateCmd(mockClient, "k1-to-channel", "--property", "k1_prop=bar", "--property", "k1_optional-", "--no-wait")
+	assert.NilError(t, err)
+	recorder.Validate()
+}
+
+func TestBindingUpdatePropertiesKeepLargeNumbers(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	existing := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	existing.Spec.Source.Properties.RawMessage = []byte("{\"k1_id\":9007199254740993,\"k1_prop\":\"foo\"}")
+	recorder.GetKameletBinding(existing.DeepCopy(), nil)
+	kamelet := createKameletInNamespace("k1", namespace)
+	kamelet.Spec.Definition.Properties["k1_id"] = v1alpha1.JSONSchemaProps{Type: "integer"}
+	recorder.Get(kamelet, nil)
+
+	expected := existing.DeepCopy()
+	expected.Spec.Source.Properties.RawMessage = []byte("{\"k1_id\":9007199254740993,\"k1_prop\":\"bar\"}")
+	recorder.UpdateKameletBinding(expected, nil)
+
+	err := runBindingUpdateCmd(mockClient, "k1-to-channel", "--property", "k1_prop=bar", "--no-wait")
+	assert.NilError(t, err)
+	recorder.Validate()
+}
+
+func TestBindingUpdateIntegrationSettings(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	existing := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	existing.Spec.Integration = &camelv1.IntegrationSpec{
+		Profile:      camelv1.TraitProfileKnative,
+		Dependencies: []string{"mvn:org.example:library:1.0"},
+		Traits: map[string]camelv1.TraitSpec{
+			"prometheus": {Configuration: camelv1.TraitConfiguration{RawMessage: []byte(`{"enabled":true,"port":9779}`)}},
+			"logging":    {Configuration: camelv1.TraitConfiguration{RawMessage: []byte(`{"level":"INFO"}`)}},
+		},
+	}
+	recorder.GetKameletBinding(existing.DeepCopy(), nil)
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+
+	replicas := int32(0)
+	expected := existing.DeepCopy()
+	expected.Spec.Integration.Replicas = &replicas
+	expected.Spec.Integration.Dependencies = []string{"mvn:org.example:library:1.0", "mvn:org.example:other:2.0"}
+	expected.Spec.Integration.Traits = map[string]camelv1.TraitSpec{
+		"prometheus": {Configuration: camelv1.TraitConfiguration{RawMessage: []byte(`{"enabled":true}`)}},
+		"logging":    {Configuration: camelv1.TraitConfiguration{RawMessage: []byte(`{"level":"DEBUG"}`)}},
+	}
+	recorder.UpdateKameletBinding(expected, nil)
+
+	err := runBindingUpdateCmd(mockClient, "k1-to-channel", "--trait", "prometheus.port-", "--trait", "logging.level=DEBUG",
+		"--dependency", "mvn:org.example:library:1.0", "--dependency", "mvn:org.example:other:2.0", "--replicas", "0", "--no-wait")
+	assert.NilError(t, err)
+	recorder.Validate()
+}
+
+func TestBindingUpdateReplicasResumesPausedBinding(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	zero := int32(0)
+	existing := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	existing.Annotations = map[string]string{pausedReplicasAnnotation: ""}
+	existing.Spec.Integration = &camelv1.IntegrationSpec{Replicas: &zero}
+	recorder.GetKameletBinding(existing.DeepCopy(), nil)
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+
+	replicas := int32(2)
+	expected := existing.DeepCopy()
+	expected.Annotations = map[string]string{}
+	expected.Spec.Integration.Replicas = &replicas
+	recorder.UpdateKameletBinding(expected, nil)
+
+	err := runBindingUpdateCmd(mockClient, "k1-to-channel", "--replicas", "2", "--no-wait")
+	assert.NilError(t, err)
+	recorder.Validate()
+}
+
+func TestBindingUpdateErrorCaseInvalidTrait(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	recorder.GetKameletBinding(createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace)), nil)
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+
+	err := runBindingUpdateCmd(mockClient, "k1-to-channel", "--trait", "prometheus=true")
+	assert.Error(t, err, "invalid trait \"prometheus=true\" - please use the form \"<trait>.<key>=<value>\"")
+	recorder.Validate()
+}
+
+func TestBindingUpdateSinkAndCloudEventsSettings(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	existing := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	existing.Spec.Sink.Properties.RawMessage = []byte("{\"ce.override.source\":\"custom\",\"ce.override.subject\":\"custom\",\"cloudEventsType\":\"custom-type\"}")
+	recorder.GetKameletBinding(existing.DeepCopy(), nil)
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+
+	expected := existing.DeepCopy()
+	expected.Spec.Sink.Ref = &corev1.ObjectReference{
+		Kind:       "Broker",
+		APIVersion: eventingv1.SchemeGroupVersion.String(),
+		Namespace:  namespace,
+		Name:       "default",
+	}
+	expected.Spec.Sink.Properties.RawMessage = []byte("{\"ce.override.subject\":\"changed\",\"cloudEventsSpecVersion\":\"1.0\",\"cloudEventsType\":\"custom-type\"}")
+	recorder.UpdateKameletBinding(expected, nil)
+
+	err := runBindingUpdateCmd(mockClient, "k1-to-channel", "--broker", "default", "--ce-spec", "1.0", "--ce-override", "subject=changed", "--ce-override", "source-", "--no-wait")
+	assert.NilError(t, err)
+	recorder.Validate()
+}
+
+func TestBindingUpdateSinkToURI(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	existing := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	recorder.GetKameletBinding(existing.DeepCopy(), nil)
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+
+	uri := "https://example.com/events"
+	expected := existing.DeepCopy()
+	expected.Spec.Sink.Ref = nil
+	expected.Spec.Sink.URI = &uri
+	recorder.UpdateKameletBinding(expected, nil)
+
+	err := runBindingUpdateCmd(mockClient, "k1-to-channel", "--sink", uri, "--no-wait")
+	assert.NilError(t, err)
+	recorder.Validate()
+}
+
+func TestBindingUpdateSinkKameletProperties(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	existing := createKameletBindingInNamespace("channel-to-log", "log-sink", namespace, nil)
+	existing.Spec.Sink = existing.Spec.Source
+	existing.Spec.Source = v1alpha1.Endpoint{
+		Properties: &v1alpha1.EndpointProperties{},
+		Ref:        channelRef(namespace),
+	}
+	recorder.GetKameletBinding(existing.DeepCopy(), nil)
+	recorder.Get(createSinkKameletInNamespace("log-sink", namespace), nil)
+
+	expected := existing.DeepCopy()
+	expected.Spec.Sink.Properties.RawMessage = []byte("{\"log-sink_optional\":true,\"log-sink_prop\":\"foo\"}")
+	recorder.UpdateKameletBinding(expected, nil)
+
+	err := runBindingUpdateCmd(mockClient, "channel-to-log", "--property", "log-sink_optional=true", "--no-wait")
+	assert.NilError(t, err)
+	recorder.Validate()
+}
+
+func TestBindingUpdateErrorCaseSinkKamelet(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	existing := createKameletBindingInNamespace("channel-to-log", "log-sink", namespace, nil)
+	existing.Spec.Sink = existing.Spec.Source
+	existing.Spec.Source = v1alpha1.Endpoint{
+		Properties: &v1alpha1.EndpointProperties{},
+		Ref:        channelRef(namespace),
+	}
+	recorder.GetKameletBinding(existing.DeepCopy(), nil)
+	recorder.Get(createSinkKameletInNamespace("log-sink", namespace), nil)
+	recorder.GetKameletBinding(existing.DeepCopy(), nil)
+	recorder.Get(createSinkKameletInNamespace("log-sink", namespace), nil)
+
+	err := runBindingUpdateCmd(mockClient, "channel-to-log", "--broker", "default", "--no-wait")
+	assert.Error(t, err, "kamelet log-sink is the sink of kamelet binding \"channel-to-log\" - --sink, --broker, --channel and --service can not be used")
+
+	err = runBindingUpdateCmd(mockClient, "channel-to-log", "--ce-type", "org.example.order", "--no-wait")
+	assert.Error(t, err, "cloud events settings are not supported for Kamelet sink log-sink")
+	recorder.Validate()
+}
+
+func TestBindingUpdateRetryOnConflict(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	existing := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	expected := existing.DeepCopy()
+	expected.Spec.Source.Properties.RawMessage = []byte("{\"k1_prop\":\"bar\"}")
+
+	recorder.GetKameletBinding(existing.DeepCopy(), nil)
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+	recorder.UpdateKameletBinding(expected, k8serrors.NewConflict(v1alpha1.Resource("kameletbindings"), "k1-to-channel", errors.New("modified")))
+
+	recorder.GetKameletBinding(existing.DeepCopy(), nil)
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+	recorder.UpdateKameletBinding(expected, nil)
+
+	err := runBindingUpdateCmd(mockClient, "k1-to-channel", "--property", "k1_prop=bar", "--no-wait")
+	assert.NilError(t, err)
+	recorder.Validate()
+}
+
+func TestBindingUpdateWaitForReady(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	existing := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	recorder.GetKameletBinding(existing.DeepCopy(), nil)
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+
+	expected := existing.DeepCopy()
+	expected.Spec.Source.Properties.RawMessage = []byte("{\"k1_prop\":\"bar\"}")
+	recorder.UpdateKameletBinding(expected, nil)
+
+	ready := expected.DeepCopy()
+	ready.Status = statusReady()
+	watcher := watch.NewFakeWithChanSize(1, false)
+	watcher.Modify(ready)
+	recorder.WatchKameletBinding(watcher, nil)
+
+	err := runBindingUpdateCmd(mockClient, "k1-to-channel", "--property", "k1_prop=bar", "--wait")
+	assert.NilError(t, err)
+	recorder.Validate()
+}
+
+func TestBindingUpdateWaitIgnoresPreviousStatus(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	existing := createKameletBindingInNamespace("k1-to-channel", "k1", namespace, channelRef(namespace))
+	existing.ResourceVersion = "1"
+	existing.Status = statusReady()
+	recorder.GetKameletBinding(existing.DeepCopy(), nil)
+	recorder.Get(createKameletInNamespace("k1", namespace), nil)
+
+	expected := existing.DeepCopy()
+	expected.Spec.Source.Properties.RawMessage = []byte("{\"k1_prop\":\"bar\"}")
+	recorder.UpdateKameletBinding(expected, nil)
+
+	// the binding as written still carries the Ready status of the previous version
+	failed := expected.DeepCopy()
+	failed.ResourceVersion = "2"
+	failed.Status = v1alpha1.KameletBindingStatus{Phase: v1alpha1.KameletBindingPhaseError}
+	watcher := watch.NewFakeWithChanSize(2, false)
+	watcher.Add(expected.DeepCopy())
+	watcher.Modify(failed)
+	recorder.WatchKameletBinding(watcher, nil)
+
+	err := runBindingUpdateCmd(mockClient, "k1-to-channel", "--property", "k1_prop=bar", "--wait")
+	assert.ErrorContains(t, err, "kamelet binding \"k1-to-channel\" failed")
+	recorder.Validate()
+}
+
+func channelRef(namespace string) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		Kind:       "Channel",
+		APIVersion: messagingv1.SchemeGroupVersion.String(),
+		Namespace:  namespace,
+		Name:       "test",
+	}
+}
+
+func runBindingUpdateCmd(c *client.MockClient, options ...string) error {
+	p := KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewKameletClient: func() (camelkv1alpha1.CamelV1alpha1Interface, error) {
+			return c, nil
+		},
+	}
+
+	command, _, _ := commands.CreateSourcesTestKnCommand(newBindingUpdateCommand(&p), p.KnParams)
+
+	args := []string{"update"}
+	args = append(args, options...)
+	command.SetArgs(args)
+	err := command.Execute()
+
+	return err
+}
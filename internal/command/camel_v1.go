@@ -0,0 +1,469 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// camelAPIVersionV1alpha1 serves Kamelets and KameletBindings with camel.apache.org/v1alpha1
+	camelAPIVersionV1alpha1 = "v1alpha1"
+	// camelAPIVersionV1 serves Kamelets and Pipes with camel.apache.org/v1
+	camelAPIVersionV1 = "v1"
+)
+
+const (
+	pipeKind     = "Pipe"
+	pipeListKind = "PipeList"
+)
+
+var (
+	camelV1GroupVersion = schema.GroupVersion{Group: v1alpha1.SchemeGroupVersion.Group, Version: camelAPIVersionV1}
+	kameletsV1Resource  = camelV1GroupVersion.WithResource("kamelets")
+	pipesV1Resource     = camelV1GroupVersion.WithResource("pipes")
+)
+
+// camelAPIVersion returns the Camel K API version to use. The given version wins, without version the cluster
+// is asked whether it serves Pipes and falls back to KameletBindings otherwise.
+func camelAPIVersion(version string, client discovery.ServerResourcesInterface) (string, error) {
+	switch version {
+	case camelAPIVersionV1alpha1, camelAPIVersionV1:
+		return version, nil
+	case "":
+	default:
+		return "", fmt.Errorf("unsupported Camel K API version %q - please use one of %s, %s", version, camelAPIVersionV1alpha1, camelAPIVersionV1)
+	}
+
+	resources, err := client.ServerResourcesForGroupVersion(camelV1GroupVersion.String())
+	if k8serrors.IsNotFound(err) {
+		return camelAPIVersionV1alpha1, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == pipesV1Resource.Resource {
+			return camelAPIVersionV1, nil
+		}
+	}
+	return camelAPIVersionV1alpha1, nil
+}
+
+// camelV1Client serves Kamelets and KameletBindings from the camel.apache.org/v1 API, where bindings are Pipes.
+// Resources are converted from and to their v1alpha1 counterparts, so commands work the same with both APIs.
+type camelV1Client struct {
+	client dynamic.Interface
+}
+
+func newCamelV1Client(client dynamic.Interface) camelkv1alpha1.CamelV1alpha1Interface {
+	return &camelV1Client{client: client}
+}
+
+// RESTClient is not available, all access goes through the dynamic client
+func (c *camelV1Client) RESTClient() rest.Interface {
+	return nil
+}
+
+func (c *camelV1Client) Kamelets(namespace string) camelkv1alpha1.KameletInterface {
+	return &camelV1Kamelets{client: c.client.Resource(kameletsV1Resource).Namespace(namespace)}
+}
+
+func (c *camelV1Client) KameletBindings(namespace string) camelkv1alpha1.KameletBindingInterface {
+	return &dynamicBindings{client: c.client.Resource(pipesV1Resource).Namespace(namespace), kind: pipeKind}
+}
+
+// camelV1Kamelets implements the v1alpha1 Kamelet interface on top of camel.apache.org/v1 Kamelets
+type camelV1Kamelets struct {
+	client dynamic.ResourceInterface
+}
+
+func (k *camelV1Kamelets) Create(ctx context.Context, kamelet *v1alpha1.Kamelet, opts v1.CreateOptions) (*v1alpha1.Kamelet, error) {
+	obj, err := toCamelV1(kamelet, v1alpha1.KameletKind)
+	if err != nil {
+		return nil, err
+	}
+	return kameletFromCamelV1(k.client.Create(ctx, obj, opts))
+}
+
+func (k *camelV1Kamelets) Update(ctx context.Context, kamelet *v1alpha1.Kamelet, opts v1.UpdateOptions) (*v1alpha1.Kamelet, error) {
+	obj, err := toCamelV1(kamelet, v1alpha1.KameletKind)
+	if err != nil {
+		return nil, err
+	}
+	return kameletFromCamelV1(k.client.Update(ctx, obj, opts))
+}
+
+func (k *camelV1Kamelets) UpdateStatus(ctx context.Context, kamelet *v1alpha1.Kamelet, opts v1.UpdateOptions) (*v1alpha1.Kamelet, error) {
+	obj, err := toCamelV1(kamelet, v1alpha1.KameletKind)
+	if err != nil {
+		return nil, err
+	}
+	return kameletFromCamelV1(k.client.UpdateStatus(ctx, obj, opts))
+}
+
+func (k *camelV1Kamelets) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return k.client.Delete(ctx, name, opts)
+}
+
+func (k *camelV1Kamelets) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	return k.client.DeleteCollection(ctx, opts, listOpts)
+}
+
+func (k *camelV1Kamelets) Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.Kamelet, error) {
+	return kameletFromCamelV1(k.client.Get(ctx, name, opts))
+}
+
+func (k *camelV1Kamelets) List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.KameletList, error) {
+	list, err := k.client.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	result := &v1alpha1.KameletList{}
+	if err := fromCamelV1(list, result, "KameletList"); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (k *camelV1Kamelets) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	watcher, err := k.client.Watch(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return watch.Filter(watcher, func(event watch.Event) (watch.Event, bool) {
+		if obj, ok := event.Object.(*unstructured.Unstructured); ok {
+			kamelet, err := kameletFromCamelV1(obj, nil)
+			if err != nil {
+				return event, false
+			}
+			event.Object = kamelet
+		}
+		return event, true
+	}), nil
+}
+
+func (k *camelV1Kamelets) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (*v1alpha1.Kamelet, error) {
+	return kameletFromCamelV1(k.client.Patch(ctx, name, pt, data, opts, subresources...))
+}
+
+// dynamicBindings implements the v1alpha1 KameletBinding interface on top of the dynamic client. The bindings are
+// stored as resources of the given kind, either camel.apache.org/v1 Pipes or v1alpha1 KameletBindings, including
+// the steps unknown to the vendored v1alpha1 API.
+type dynamicBindings struct {
+	client dynamic.ResourceInterface
+	kind   string
+}
+
+// toObject converts the binding into the resource stored by the client
+func (p *dynamicBindings) toObject(binding *v1alpha1.KameletBinding) (*unstructured.Unstructured, error) {
+	if p.kind == pipeKind {
+		return toCamelV1(binding, pipeKind)
+	}
+	return toCamelV1alpha1Binding(binding)
+}
+
+func (p *dynamicBindings) Create(ctx context.Context, binding *v1alpha1.KameletBinding, opts v1.CreateOptions) (*v1alpha1.KameletBinding, error) {
+	obj, err := p.toObject(binding)
+	if err != nil {
+		return nil, err
+	}
+	return bindingFromCamelV1(p.client.Create(ctx, obj, opts))
+}
+
+func (p *dynamicBindings) Update(ctx context.Context, binding *v1alpha1.KameletBinding, opts v1.UpdateOptions) (*v1alpha1.KameletBinding, error) {
+	obj, err := p.toObject(binding)
+	if err != nil {
+		return nil, err
+	}
+	return bindingFromCamelV1(p.client.Update(ctx, obj, opts))
+}
+
+func (p *dynamicBindings) UpdateStatus(ctx context.Context, binding *v1alpha1.KameletBinding, opts v1.UpdateOptions) (*v1alpha1.KameletBinding, error) {
+	obj, err := p.toObject(binding)
+	if err != nil {
+		return nil, err
+	}
+	return bindingFromCamelV1(p.client.UpdateStatus(ctx, obj, opts))
+}
+
+func (p *dynamicBindings) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return p.client.Delete(ctx, name, opts)
+}
+
+func (p *dynamicBindings) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	return p.client.DeleteCollection(ctx, opts, listOpts)
+}
+
+func (p *dynamicBindings) Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.KameletBinding, error) {
+	return bindingFromCamelV1(p.client.Get(ctx, name, opts))
+}
+
+func (p *dynamicBindings) List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.KameletBindingList, error) {
+	list, err := p.client.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	result := &v1alpha1.KameletBindingList{}
+	if err := fromCamelV1(list, result, "KameletBindingList"); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (p *dynamicBindings) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	watcher, err := p.client.Watch(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return watch.Filter(watcher, func(event watch.Event) (watch.Event, bool) {
+		if obj, ok := event.Object.(*unstructured.Unstructured); ok {
+			binding, err := bindingFromCamelV1(obj, nil)
+			if err != nil {
+				return event, false
+			}
+			event.Object = binding
+		}
+		return event, true
+	}), nil
+}
+
+func (p *dynamicBindings) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (*v1alpha1.KameletBinding, error) {
+	return bindingFromCamelV1(p.client.Patch(ctx, name, pt, data, opts, subresources...))
+}
+
+func kameletFromCamelV1(obj *unstructured.Unstructured, err error) (*v1alpha1.Kamelet, error) {
+	if err != nil {
+		return nil, err
+	}
+	kamelet := &v1alpha1.Kamelet{}
+	if err := fromCamelV1(obj, kamelet, v1alpha1.KameletKind); err != nil {
+		return nil, err
+	}
+	return kamelet, nil
+}
+
+func bindingFromCamelV1(obj *unstructured.Unstructured, err error) (*v1alpha1.KameletBinding, error) {
+	if err != nil {
+		return nil, err
+	}
+	binding := &v1alpha1.KameletBinding{}
+	if err := fromCamelV1(obj, binding, v1alpha1.KameletBindingKind); err != nil {
+		return nil, err
+	}
+	return binding, nil
+}
+
+// toCamelV1 converts the given v1alpha1 resource into the camel.apache.org/v1 resource of the given kind
+func toCamelV1(obj interface{}, kind string) (*unstructured.Unstructured, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	content := make(map[string]interface{})
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, err
+	}
+
+	if items, ok := content["items"].([]interface{}); ok {
+		itemKind := strings.TrimSuffix(kind, "List")
+		for _, item := range items {
+			if m, ok := item.(map[string]interface{}); ok {
+				setCamelV1Kind(m, itemKind)
+			}
+		}
+	}
+	setCamelV1Kind(content, kind)
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+func setCamelV1Kind(content map[string]interface{}, kind string) {
+	content["apiVersion"] = camelV1GroupVersion.String()
+	content["kind"] = kind
+	if kind == pipeKind {
+		stepsToSpec(content)
+		errorHandlerToSpec(content)
+	}
+	convertKameletRefs(content, camelV1GroupVersion.String())
+	convertTraits(content, flattenTraitConfiguration)
+}
+
+// toCamelV1Object converts Kamelets, bindings and their lists into the camel.apache.org/v1 resources, so they can be
+// printed and applied to clusters serving Pipes. Other objects are returned as they are.
+func toCamelV1Object(obj runtime.Object) (runtime.Object, error) {
+	switch obj.(type) {
+	case *v1alpha1.Kamelet:
+		return toCamelV1(obj, v1alpha1.KameletKind)
+	case *v1alpha1.KameletList:
+		return toCamelV1List(obj, "KameletList")
+	case *v1alpha1.KameletBinding:
+		return toCamelV1(obj, pipeKind)
+	case *v1alpha1.KameletBindingList:
+		return toCamelV1List(obj, pipeListKind)
+	}
+	return obj, nil
+}
+
+// toCamelV1List converts the given list into an unstructured list of camel.apache.org/v1 resources
+func toCamelV1List(obj runtime.Object, kind string) (*unstructured.UnstructuredList, error) {
+	list, err := toCamelV1(obj, kind)
+	if err != nil {
+		return nil, err
+	}
+	return list.ToList()
+}
+
+// printableObject returns the given object as camel.apache.org/v1 resource when the client serves Pipes
+func printableObject(client camelkv1alpha1.CamelV1alpha1Interface, obj runtime.Object) (runtime.Object, error) {
+	if !servesPipes(client) {
+		return toCamelV1alpha1Object(obj)
+	}
+	return toCamelV1Object(obj)
+}
+
+// pipesInUse returns true when the bindings are served as Pipes. Without cluster access the API version given with
+// --api-version decides.
+func (params *KameletPluginParams) pipesInUse(client camelkv1alpha1.CamelV1alpha1Interface, offline bool) bool {
+	if offline {
+		return params.APIVersion == camelAPIVersionV1
+	}
+	return servesPipes(client)
+}
+
+// servesPipes returns true when the client serves the bindings as camel.apache.org/v1 Pipes
+func servesPipes(client camelkv1alpha1.CamelV1alpha1Interface) bool {
+	switch c := client.(type) {
+	case *camelV1Client:
+		return true
+	case *localKameletClient:
+		return c.CamelV1alpha1Interface != nil && servesPipes(c.CamelV1alpha1Interface)
+	}
+	return false
+}
+
+// fromCamelV1 converts the given camel.apache.org/v1 resource or list into the v1alpha1 resource of the given kind
+func fromCamelV1(obj runtime.Unstructured, into interface{}, kind string) error {
+	content := obj.UnstructuredContent()
+	if items, ok := content["items"].([]interface{}); ok {
+		itemKind := strings.TrimSuffix(kind, "List")
+		for _, item := range items {
+			if m, ok := item.(map[string]interface{}); ok {
+				setCamelV1alpha1Kind(m, itemKind)
+			}
+		}
+	}
+	setCamelV1alpha1Kind(content, kind)
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, into)
+}
+
+func setCamelV1alpha1Kind(content map[string]interface{}, kind string) {
+	content["apiVersion"] = v1alpha1.SchemeGroupVersion.String()
+	content["kind"] = kind
+	convertKameletRefs(content, v1alpha1.SchemeGroupVersion.String())
+	convertTraits(content, nestTraitConfiguration)
+	if kind == v1alpha1.KameletBindingKind {
+		stepsToAnnotation(content)
+		errorHandlerToAnnotation(content)
+	}
+}
+
+// convertKameletRefs rewrites the API version of all Kamelet references in the endpoints of a binding or Pipe
+func convertKameletRefs(content map[string]interface{}, apiVersion string) {
+	spec, ok := content["spec"].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	endpoints := []interface{}{spec["source"], spec["sink"]}
+	if steps, ok := spec["steps"].([]interface{}); ok {
+		endpoints = append(endpoints, steps...)
+	}
+	if handler, ok := spec["errorHandler"].(map[string]interface{}); ok {
+		if sink, ok := handler[errorHandlerTypeSink].(map[string]interface{}); ok {
+			endpoints = append(endpoints, sink["endpoint"])
+		}
+	}
+	for _, endpoint := range endpoints {
+		e, ok := endpoint.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ref, ok := e["ref"].(map[string]interface{})
+		if !ok || ref["kind"] != v1alpha1.KameletKind {
+			continue
+		}
+		if gv, err := schema.ParseGroupVersion(fmt.Sprint(ref["apiVersion"])); err == nil && gv.Group == camelV1GroupVersion.Group {
+			ref["apiVersion"] = apiVersion
+		}
+	}
+}
+
+// convertTraits applies the given conversion to each trait of the integration spec of a binding or Pipe
+func convertTraits(content map[string]interface{}, convert func(trait map[string]interface{}) map[string]interface{}) {
+	traits, found, err := unstructured.NestedMap(content, "spec", "integration", "traits")
+	if !found || err != nil {
+		return
+	}
+	for name, trait := range traits {
+		if t, ok := trait.(map[string]interface{}); ok {
+			traits[name] = convert(t)
+		}
+	}
+	_ = unstructured.SetNestedMap(content, traits, "spec", "integration", "traits")
+}
+
+// flattenTraitConfiguration turns the v1alpha1 trait configuration into the typed trait fields of camel.apache.org/v1
+func flattenTraitConfiguration(trait map[string]interface{}) map[string]interface{} {
+	configuration, ok := trait["configuration"].(map[string]interface{})
+	if !ok {
+		return trait
+	}
+	result := make(map[string]interface{}, len(configuration))
+	for key, value := range configuration {
+		result[key] = value
+	}
+	return result
+}
+
+// nestTraitConfiguration turns the typed trait fields of camel.apache.org/v1 into the v1alpha1 trait configuration
+func nestTraitConfiguration(trait map[string]interface{}) map[string]interface{} {
+	if _, ok := trait["configuration"]; ok {
+		return trait
+	}
+	return map[string]interface{}{"configuration": trait}
+}
@@ -0,0 +1,269 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	"github.com/spf13/cobra"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"knative.dev/client-pkg/pkg/commands"
+	"knative.dev/client-pkg/pkg/util"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCamelAPIVersion(t *testing.T) {
+	version, err := camelAPIVersion("", &fakeServerResources{resources: []v1.APIResource{{Name: "kamelets"}, {Name: "pipes"}}})
+	assert.NilError(t, err)
+	assert.Equal(t, version, camelAPIVersionV1)
+
+	version, err = camelAPIVersion("", &fakeServerResources{resources: []v1.APIResource{{Name: "integrations"}}})
+	assert.NilError(t, err)
+	assert.Equal(t, version, camelAPIVersionV1alpha1)
+
+	version, err = camelAPIVersion("", &fakeServerResources{err: k8serrors.NewNotFound(schema.GroupResource{}, "camel.apache.org/v1")})
+	assert.NilError(t, err)
+	assert.Equal(t, version, camelAPIVersionV1alpha1)
+
+	version, err = camelAPIVersion("v1alpha1", &fakeServerResources{resources: []v1.APIResource{{Name: "pipes"}}})
+	assert.NilError(t, err)
+	assert.Equal(t, version, camelAPIVersionV1alpha1)
+
+	_, err = camelAPIVersion("v2", &fakeServerResources{})
+	assert.Error(t, err, "unsupported Camel K API version \"v2\" - please use one of v1alpha1, v1")
+}
+
+func TestCamelV1ClientCreatePipe(t *testing.T) {
+	dynamicClient := newFakeCamelV1DynamicClient()
+	client := newCamelV1Client(dynamicClient)
+
+	binding := createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current"))
+	created, err := client.KameletBindings("current").Create(context.TODO(), binding, v1.CreateOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, created.Kind, v1alpha1.KameletBindingKind)
+	assert.Equal(t, created.Spec.Source.Ref.APIVersion, v1alpha1.SchemeGroupVersion.String())
+
+	pipe, err := dynamicClient.Resource(pipesV1Resource).Namespace("current").Get(context.TODO(), "k1-to-channel", v1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, pipe.GetAPIVersion(), "camel.apache.org/v1")
+	assert.Equal(t, pipe.GetKind(), pipeKind)
+	apiVersion, _, _ := unstructured.NestedString(pipe.Object, "spec", "source", "ref", "apiVersion")
+	assert.Equal(t, apiVersion, "camel.apache.org/v1")
+	apiVersion, _, _ = unstructured.NestedString(pipe.Object, "spec", "sink", "ref", "apiVersion")
+	assert.Equal(t, apiVersion, "messaging.knative.dev/v1")
+	prop, _, _ := unstructured.NestedString(pipe.Object, "spec", "source", "properties", "k1_prop")
+	assert.Equal(t, prop, "foo")
+}
+
+func TestCamelV1ClientGetKamelet(t *testing.T) {
+	kamelet, err := toCamelV1(createKameletInNamespace("k1", "current"), v1alpha1.KameletKind)
+	assert.NilError(t, err)
+	client := newCamelV1Client(newFakeCamelV1DynamicClient(kamelet))
+
+	result, err := client.Kamelets("current").Get(context.TODO(), "k1", v1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, result.APIVersion, v1alpha1.SchemeGroupVersion.String())
+	assert.Equal(t, result.Spec.Definition.Title, "Kamelet k1")
+
+	list, err := client.Kamelets("current").List(context.TODO(), v1.ListOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(list.Items), 1)
+	assert.Equal(t, list.Items[0].Kind, v1alpha1.KameletKind)
+
+	_, err = client.Kamelets("current").Get(context.TODO(), "k2", v1.GetOptions{})
+	assert.Assert(t, k8serrors.IsNotFound(err))
+}
+
+func TestCamelV1ClientListPipes(t *testing.T) {
+	binding := createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current"))
+	binding.Status = statusReady()
+	pipe, err := toCamelV1(binding, pipeKind)
+	assert.NilError(t, err)
+
+	p := KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewKameletClient: func() (camelkv1alpha1.CamelV1alpha1Interface, error) {
+			return newCamelV1Client(newFakeCamelV1DynamicClient(pipe)), nil
+		},
+		NewIntegrationClient: func() (IntegrationClient, error) {
+			return &fakeIntegrationClient{}, nil
+		},
+	}
+	listCmd, _, output := commands.CreateSourcesTestKnCommand(newBindingListCommand(&p), p.KnParams)
+	listCmd.SetArgs([]string{"list", "-n", "current"})
+	assert.NilError(t, listCmd.Execute())
+	assert.Assert(t, util.ContainsAll(output.String(), "k1-to-channel", "k1", "Ready", "True"))
+}
+
+func TestCamelV1PrintPipes(t *testing.T) {
+	binding := createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current"))
+	binding.Spec.Integration = &camelv1.IntegrationSpec{
+		Traits: map[string]camelv1.TraitSpec{
+			"logging": {Configuration: camelv1.TraitConfiguration{RawMessage: []byte(`{"level":"DEBUG"}`)}},
+		},
+	}
+	pipe, err := toCamelV1(binding, pipeKind)
+	assert.NilError(t, err)
+	kamelet, err := toCamelV1(createKameletInNamespace("k1", "current"), v1alpha1.KameletKind)
+	assert.NilError(t, err)
+	p := camelV1PluginParams(newFakeCamelV1DynamicClient(pipe, kamelet))
+
+	for _, cmd := range []struct {
+		command *cobra.Command
+		args    []string
+	}{
+		{command: newBindingListCommand(p), args: []string{"list", "-n", "current", "-o", "yaml"}},
+		{command: newBindingDescribeCommand(p), args: []string{"describe", "k1-to-channel", "-n", "current", "-o", "yaml"}},
+		{command: newBindingExportCommand(p), args: []string{"export", "k1-to-channel", "-n", "current"}},
+	} {
+		command, _, output := commands.CreateSourcesTestKnCommand(cmd.command, p.KnParams)
+		command.SetArgs(cmd.args)
+		assert.NilError(t, command.Execute())
+		assert.Assert(t, util.ContainsAll(output.String(), "apiVersion: camel.apache.org/v1", "kind: Pipe", "name: k1-to-channel", "level: DEBUG"))
+		assert.Assert(t, util.ContainsNone(output.String(), "v1alpha1", "KameletBinding", "configuration:"))
+	}
+
+	command, _, output := commands.CreateSourcesTestKnCommand(newBindingCreateCommand(p), p.KnParams)
+	command.SetArgs([]string{"create", "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo", "-n", "current", "--dry-run=client"})
+	assert.NilError(t, command.Execute())
+	assert.Assert(t, util.ContainsAll(output.String(), "apiVersion: camel.apache.org/v1", "kind: Pipe", "name: k1-to-channel", "k1_prop: foo"))
+	assert.Assert(t, util.ContainsNone(output.String(), "v1alpha1", "KameletBinding"))
+
+	command, _, output = commands.CreateSourcesTestKnCommand(NewDescribeCommand(p), p.KnParams)
+	command.SetArgs([]string{"describe", "k1", "-n", "current", "-o", "yaml"})
+	assert.NilError(t, command.Execute())
+	assert.Assert(t, util.ContainsAll(output.String(), "apiVersion: camel.apache.org/v1", "kind: Kamelet", "name: k1"))
+	assert.Assert(t, util.ContainsNone(output.String(), "apiVersion: camel.apache.org/v1alpha1"))
+}
+
+func TestCamelV1PipeErrorHandler(t *testing.T) {
+	kamelet, err := toCamelV1(createKameletInNamespace("k1", "current"), v1alpha1.KameletKind)
+	assert.NilError(t, err)
+	dynamicClient := newFakeCamelV1DynamicClient(kamelet)
+	p := camelV1PluginParams(dynamicClient)
+
+	command, _, _ := commands.CreateSourcesTestKnCommand(newBindingCreateCommand(p), p.KnParams)
+	command.SetArgs([]string{"create", "k1-to-channel", "--kamelet", "k1", "--channel", "test", "--property", "k1_prop=foo", "-n", "current", "--no-wait",
+		"--error-sink", "channel:errors", "--error-max-redeliveries", "3", "--error-redelivery-delay", "2s"})
+	assert.NilError(t, command.Execute())
+
+	pipe, err := dynamicClient.Resource(pipesV1Resource).Namespace("current").Get(context.TODO(), "k1-to-channel", v1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, len(pipe.GetAnnotations()), 0)
+	_, found, _ := unstructured.NestedFieldNoCopy(pipe.Object, "spec", "integration")
+	assert.Assert(t, !found)
+	name, _, _ := unstructured.NestedString(pipe.Object, "spec", "errorHandler", "sink", "endpoint", "ref", "name")
+	assert.Equal(t, name, "errors")
+	kind, _, _ := unstructured.NestedString(pipe.Object, "spec", "errorHandler", "sink", "endpoint", "ref", "kind")
+	assert.Equal(t, kind, "Channel")
+	redeliveries, _, _ := unstructured.NestedFieldNoCopy(pipe.Object, "spec", "errorHandler", "sink", "parameters", "maximumRedeliveries")
+	assert.Equal(t, fmt.Sprint(redeliveries), "3")
+	delay, _, _ := unstructured.NestedFieldNoCopy(pipe.Object, "spec", "errorHandler", "sink", "parameters", "redeliveryDelay")
+	assert.Equal(t, fmt.Sprint(delay), "2000")
+
+	command, _, output := commands.CreateSourcesTestKnCommand(newBindingDescribeCommand(p), p.KnParams)
+	command.SetArgs([]string{"describe", "k1-to-channel", "-n", "current"})
+	assert.NilError(t, command.Execute())
+	assert.Assert(t, util.ContainsAll(output.String(), "Error Handler:", "Type:", "sink", "Kind:", "Channel", "Name:", "errors",
+		"Max Redeliveries:", "3", "Redelivery Delay:", "2s"))
+	assert.Assert(t, util.ContainsNone(output.String(), "Integration:"))
+
+	command, _, output = commands.CreateSourcesTestKnCommand(newBindingExportCommand(p), p.KnParams)
+	command.SetArgs([]string{"export", "k1-to-channel", "-n", "current"})
+	assert.NilError(t, command.Execute())
+	assert.Assert(t, util.ContainsAll(output.String(), "errorHandler:", "maximumRedeliveries: 3", "redeliveryDelay: 2000", "name: errors"))
+	assert.Assert(t, util.ContainsNone(output.String(), errorHandlerAnnotation))
+
+	command, _, output = commands.CreateSourcesTestKnCommand(newBindingExportCommand(p), p.KnParams)
+	command.SetArgs([]string{"export", "k1-to-channel", "-n", "current", "--format", "command"})
+	assert.NilError(t, command.Execute())
+	assert.Assert(t, util.ContainsAll(output.String(), "--error-sink channel:errors --error-max-redeliveries 3 --error-redelivery-delay 2s"))
+}
+
+func TestCamelV1PipeLogErrorHandler(t *testing.T) {
+	binding := createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current"))
+	pipe, err := toCamelV1(binding, pipeKind)
+	assert.NilError(t, err)
+	assert.NilError(t, unstructured.SetNestedField(pipe.Object, map[string]interface{}{"log": map[string]interface{}{}}, "spec", "errorHandler"))
+	p := camelV1PluginParams(newFakeCamelV1DynamicClient(pipe))
+
+	result, err := newCamelV1Client(newFakeCamelV1DynamicClient(pipe)).KameletBindings("current").Get(context.TODO(), "k1-to-channel", v1.GetOptions{})
+	assert.NilError(t, err)
+	handler := errorHandlerOf(result)
+	assert.Assert(t, handler != nil)
+	assert.Equal(t, handler.Type, errorHandlerTypeLog)
+
+	command, _, output := commands.CreateSourcesTestKnCommand(newBindingExportCommand(p), p.KnParams)
+	command.SetArgs([]string{"export", "k1-to-channel", "-n", "current", "--format", "command"})
+	assert.NilError(t, command.Execute())
+	assert.Assert(t, util.ContainsAll(output.String(), "--error-sink log"))
+
+	assert.NilError(t, unstructured.SetNestedField(pipe.Object, map[string]interface{}{"none": map[string]interface{}{}}, "spec", "errorHandler"))
+	p = camelV1PluginParams(newFakeCamelV1DynamicClient(pipe))
+	command, _, _ = commands.CreateSourcesTestKnCommand(newBindingExportCommand(p), p.KnParams)
+	command.SetArgs([]string{"export", "k1-to-channel", "-n", "current", "--format", "command"})
+	assert.ErrorContains(t, command.Execute(), "error handler of type \"none\" can not be expressed as command option")
+}
+
+// camelV1PluginParams returns the plugin params of a cluster serving camel.apache.org/v1 Pipes
+func camelV1PluginParams(dynamicClient *dynamicfake.FakeDynamicClient) *KameletPluginParams {
+	return &KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewKameletClient: func() (camelkv1alpha1.CamelV1alpha1Interface, error) {
+			return newCamelV1Client(dynamicClient), nil
+		},
+		NewIntegrationClient: func() (IntegrationClient, error) {
+			return &fakeIntegrationClient{}, nil
+		},
+	}
+}
+
+func newFakeCamelV1DynamicClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		kameletsV1Resource:              "KameletList",
+		pipesV1Resource:                 pipeListKind,
+		kameletBindingsV1alpha1Resource: "KameletBindingList",
+	}, objects...)
+}
+
+// fakeServerResources serves the resources of the camel.apache.org/v1 API
+type fakeServerResources struct {
+	discovery.ServerResourcesInterface
+	resources []v1.APIResource
+	err       error
+}
+
+func (f *fakeServerResources) ServerResourcesForGroupVersion(groupVersion string) (*v1.APIResourceList, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &v1.APIResourceList{GroupVersion: groupVersion, APIResources: f.resources}, nil
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"encoding/json"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+)
+
+var (
+	kameletBindingsV1alpha1Resource = v1alpha1.SchemeGroupVersion.WithResource("kameletbindings")
+)
+
+// camelV1alpha1Client serves Kamelets with the typed camel.apache.org/v1alpha1 client. KameletBindings are served
+// through the dynamic client, so their steps are read and written although the typed client does not know them.
+type camelV1alpha1Client struct {
+	camelkv1alpha1.CamelV1alpha1Interface
+	client dynamic.Interface
+}
+
+func newCamelV1alpha1Client(typed camelkv1alpha1.CamelV1alpha1Interface, client dynamic.Interface) camelkv1alpha1.CamelV1alpha1Interface {
+	return &camelV1alpha1Client{CamelV1alpha1Interface: typed, client: client}
+}
+
+func (c *camelV1alpha1Client) KameletBindings(namespace string) camelkv1alpha1.KameletBindingInterface {
+	return &dynamicBindings{client: c.client.Resource(kameletBindingsV1alpha1Resource).Namespace(namespace), kind: v1alpha1.KameletBindingKind}
+}
+
+// toCamelV1alpha1Binding converts the binding into the unstructured KameletBinding with its steps in the spec
+func toCamelV1alpha1Binding(binding *v1alpha1.KameletBinding) (*unstructured.Unstructured, error) {
+	content, err := toCamelV1alpha1Content(binding)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
+
+func toCamelV1alpha1Content(obj interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	content := make(map[string]interface{})
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, err
+	}
+
+	if items, ok := content["items"].([]interface{}); ok {
+		for _, item := range items {
+			if m, ok := item.(map[string]interface{}); ok {
+				m["apiVersion"] = v1alpha1.SchemeGroupVersion.String()
+				m["kind"] = v1alpha1.KameletBindingKind
+				stepsToSpec(m)
+				errorHandlerToSpec(m)
+			}
+		}
+		return content, nil
+	}
+	content["apiVersion"] = v1alpha1.SchemeGroupVersion.String()
+	content["kind"] = v1alpha1.KameletBindingKind
+	stepsToSpec(content)
+	errorHandlerToSpec(content)
+	return content, nil
+}
+
+// toCamelV1alpha1Object converts bindings and binding lists having steps or a native error handler into unstructured
+// KameletBindings, so both are printed in the spec. Other objects are returned as they are.
+func toCamelV1alpha1Object(obj runtime.Object) (runtime.Object, error) {
+	switch o := obj.(type) {
+	case *v1alpha1.KameletBinding:
+		if hasSpecAnnotation(o) {
+			return toCamelV1alpha1Binding(o)
+		}
+	case *v1alpha1.KameletBindingList:
+		for i := range o.Items {
+			if hasSpecAnnotation(&o.Items[i]) {
+				content, err := toCamelV1alpha1Content(o)
+				if err != nil {
+					return nil, err
+				}
+				content["kind"] = "KameletBindingList"
+				content["apiVersion"] = v1alpha1.SchemeGroupVersion.String()
+				return (&unstructured.Unstructured{Object: content}).ToList()
+			}
+		}
+	}
+	return obj, nil
+}
+
+// hasSpecAnnotation returns true when the binding carries steps or an error handler that belong into its spec
+func hasSpecAnnotation(binding *v1alpha1.KameletBinding) bool {
+	_, steps := binding.Annotations[stepsAnnotation]
+	_, errorHandler := binding.Annotations[errorHandlerAnnotation]
+	return steps || errorHandler
+}
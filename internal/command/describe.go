@@ -40,11 +40,19 @@ var describeExample = `
   kn source kamelet describe NAME
 
   # Describe given Kamelets in YAML output format
-  kn source kamelet describe NAME -o yaml`
+  kn source kamelet describe NAME -o yaml
+
+  # Describe a Kamelet from a local file without cluster access
+  kn source kamelet describe NAME --kamelet-file=NAME.kamelet.yaml
+
+  # Describe given Kamelet listing the bindings of all namespaces using it
+  kn source kamelet describe NAME --bindings-all-namespaces`
 
 // NewDescribeCommand implements 'kn-source-kamelet describe' command
 func NewDescribeCommand(p *KameletPluginParams) *cobra.Command {
 	printFlags := genericclioptions.NewPrintFlags("")
+	var kameletFileFlags KameletFileFlags
+	var bindingsAllNamespaces bool
 
 	cmd := &cobra.Command{
 		Use:     "describe NAME",
@@ -61,7 +69,7 @@ func NewDescribeCommand(p *KameletPluginParams) *cobra.Command {
 				return err
 			}
 
-			client, err := p.NewKameletClient()
+			client, err := kameletFileFlags.client(p.NewKameletClient, kameletFileFlags.specified())
 			if err != nil {
 				return err
 			}
@@ -74,8 +82,8 @@ func NewDescribeCommand(p *KameletPluginParams) *cobra.Command {
 
 			out := cmd.OutOrStdout()
 
-			if !isEventSourceType(kamelet) {
-				return fmt.Errorf("kamelet %s is not an event source", name)
+			if !isEventSourceType(kamelet) && !isEventSinkType(kamelet) && !isActionType(kamelet) {
+				return fmt.Errorf("kamelet %s is not a source, sink or action", name)
 			}
 
 			if printFlags.OutputFlagSpecified() {
@@ -87,7 +95,11 @@ func NewDescribeCommand(p *KameletPluginParams) *cobra.Command {
 				if err != nil {
 					return err
 				}
-				return printer.PrintObj(kamelet, out)
+				obj, err := printableObject(client, kamelet)
+				if err != nil {
+					return err
+				}
+				return printer.PrintObj(obj, out)
 			}
 
 			dw := printers.NewPrefixWriter(out)
@@ -99,6 +111,17 @@ func NewDescribeCommand(p *KameletPluginParams) *cobra.Command {
 
 			writeKamelet(dw, kamelet, printDetails)
 			dw.WriteLine()
+
+			// bindings are only known to the cluster, the Kamelet is described even when they can not be listed
+			if !kameletFileFlags.specified() {
+				bindings, err := listKameletBindings(client, p.Context, kamelet, bindingsAllNamespaces)
+				if err != nil {
+					dw.WriteAttribute("Bindings", fmt.Sprintf("<unknown> (%v)", knerrors.GetError(err)))
+				} else {
+					writeKameletBindings(dw, bindings, bindingsAllNamespaces)
+				}
+				dw.WriteLine()
+			}
 			if err := dw.Flush(); err != nil {
 				return err
 			}
@@ -115,6 +138,8 @@ func NewDescribeCommand(p *KameletPluginParams) *cobra.Command {
 	flags := cmd.Flags()
 	commands.AddNamespaceFlags(flags, false)
 	flags.BoolP("verbose", "v", false, "More output.")
+	flags.BoolVar(&bindingsAllNamespaces, "bindings-all-namespaces", false, "List the Kamelet bindings using the Kamelet across all namespaces.")
+	kameletFileFlags.AddFlags(flags)
 	printFlags.AddFlags(cmd)
 	cmd.Flag("output").Usage = fmt.Sprintf("Output format. One of: %s.", strings.Join(append(printFlags.AllowedFormats(), "url"), "|"))
 	return cmd
@@ -134,6 +159,38 @@ func writeKamelet(dw printers.PrefixWriter, kamelet *v1alpha1.Kamelet, printDeta
 
 	dw.WriteLine()
 	writeKameletProperties(dw, kamelet)
+
+	if len(kamelet.Spec.Types) > 0 {
+		dw.WriteLine()
+		writeEventTypes(dw, kamelet.Spec.Types)
+	}
+}
+
+// writeKameletBindings prints the name and phase of the bindings using the Kamelet
+func writeKameletBindings(dw printers.PrefixWriter, bindings []v1alpha1.KameletBinding, allNamespaces bool) {
+	if len(bindings) == 0 {
+		dw.WriteAttribute("Bindings", "<none>")
+		return
+	}
+
+	section := dw.WriteAttribute("Bindings", "")
+	names := make([]string, 0, len(bindings))
+	maxLen := len("Name")
+	for _, binding := range bindings {
+		name := binding.Name
+		if allNamespaces {
+			name = binding.Namespace + "/" + binding.Name
+		}
+		names = append(names, name)
+		if len(name) > maxLen {
+			maxLen = len(name)
+		}
+	}
+	format := "%-" + strconv.Itoa(maxLen) + "s %s\n"
+	section.Writef(format, "Name", "Phase")
+	for i := range bindings {
+		section.Writef(format, names[i], bindingPhaseValue(&bindings[i]))
+	}
 }
 
 func writeKameletProperties(dw printers.PrefixWriter, kamelet *v1alpha1.Kamelet) {
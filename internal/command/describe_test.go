@@ -22,7 +22,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
 	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"knative.dev/client-pkg/pkg/commands"
 	"knative.dev/client-pkg/pkg/util"
 	"knative.dev/kn-plugin-source-kamelet/internal/client"
@@ -66,13 +68,11 @@ func TestDescribeErrorCaseNoEventSource(t *testing.T) {
 	recorder := mockClient.Recorder()
 
 	kamelet := createKamelet("k1")
-	kamelet.Labels = map[string]string{
-		KameletTypeLabel: "sink",
-	}
+	kamelet.Labels = map[string]string{}
 	recorder.Get(kamelet, nil)
 
 	_, err := runDescribeCmd(mockClient, "k1")
-	assert.Error(t, err, "kamelet k1 is not an event source")
+	assert.Error(t, err, "kamelet k1 is not a source, sink or action")
 	recorder.Validate()
 }
 
@@ -83,6 +83,8 @@ func TestDescribeOutput(t *testing.T) {
 	kamelet := createKamelet("k1")
 	recorder.Get(kamelet, nil)
 
+	recorder.ListBindings(&v1alpha1.KameletBindingList{}, nil)
+
 	output, err := runDescribeCmd(mockClient, "k1")
 	assert.NilError(t, err)
 
@@ -103,9 +105,130 @@ func TestDescribeOutput(t *testing.T) {
 	assert.Check(t, util.ContainsAll(outputLines[12], "k1_optional", " ", "boolean", "The k1 optional property"))
 	assert.Check(t, util.ContainsAll(outputLines[13], "k1_prop", "✓", "string", "The k1 required property"))
 
-	assert.Check(t, util.ContainsAll(outputLines[15], "Conditions:"))
-	assert.Check(t, util.ContainsAll(outputLines[16], "OK", "TYPE", "AGE", "REASON"))
-	assert.Check(t, util.ContainsAll(outputLines[17], "++", "Ready", "", ""))
+	assert.Check(t, util.ContainsAll(outputLines[15], "Bindings:", "<none>"))
+
+	assert.Check(t, util.ContainsAll(outputLines[17], "Conditions:"))
+	assert.Check(t, util.ContainsAll(outputLines[18], "OK", "TYPE", "AGE", "REASON"))
+	assert.Check(t, util.ContainsAll(outputLines[19], "++", "Ready", "", ""))
+
+	recorder.Validate()
+}
+
+func TestDescribeEventTypes(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	kamelet.Spec.Types = map[v1alpha1.EventSlot]v1alpha1.EventTypeSpec{
+		v1alpha1.EventSlotOut: {
+			MediaType: "application/json",
+			Schema: &v1alpha1.JSONSchemaProps{
+				Type:     "object",
+				Required: []string{"id"},
+				Properties: map[string]v1alpha1.JSONSchemaProps{
+					"id":   {Type: "string", Description: "Event id"},
+					"tags": {Type: "array", Items: &v1alpha1.JSONSchemaProps{Type: "string"}},
+					"payload": {Type: "object", Properties: map[string]v1alpha1.JSONSchemaProps{
+						"created": {Type: "string", Format: "date-time"},
+					}},
+				},
+			},
+		},
+		v1alpha1.EventSlotError: {MediaType: "text/plain"},
+	}
+	recorder.Get(kamelet, nil)
+
+	recorder.ListBindings(&v1alpha1.KameletBindingList{}, nil)
+
+	output, err := runDescribeCmd(mockClient, "k1")
+	assert.NilError(t, err)
+
+	assert.Check(t, util.ContainsAll(output, "Types:", "out:", "Media Type:", "application/json", "Schema:", "(object)",
+		"error:", "text/plain"))
+	assert.Check(t, util.ContainsAll(output, "id (string, required) - Event id\n", "payload (object)\n",
+		"  created (string, format date-time)\n", "tags (array of string)\n"))
+	assert.Assert(t, strings.Index(output, "out:") < strings.Index(output, "error:"))
+
+	recorder.Validate()
+}
+
+func TestDescribeBindings(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	recorder.Get(kamelet, nil)
+
+	running := createKameletBindingInNamespace("k1-to-channel", "k1", "default", channelRef("default"))
+	running.Status.Phase = v1alpha1.KameletBindingPhaseReady
+	paused := createKameletBindingInNamespace("k1-to-broker", "k1", "default", brokerRef("default"))
+	paused.Annotations = map[string]string{pausedReplicasAnnotation: ""}
+	other := createKameletBindingInNamespace("k2-to-channel", "k2", "default", channelRef("default"))
+	recorder.ListBindings(&v1alpha1.KameletBindingList{Items: []v1alpha1.KameletBinding{*running, *paused, *other}}, nil)
+
+	output, err := runDescribeCmd(mockClient, "k1")
+	assert.NilError(t, err)
+
+	assert.Check(t, util.ContainsAll(output, "Bindings:", "Name", "Phase", "k1-to-broker  Paused", "k1-to-channel Ready"))
+	assert.Assert(t, strings.Index(output, "k1-to-broker") < strings.Index(output, "k1-to-channel"))
+	assert.Assert(t, !strings.Contains(output, "k2-to-channel"))
+
+	recorder.Validate()
+}
+
+func TestDescribeBindingsNotListed(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKamelet("k1"), nil)
+	recorder.ListBindings(nil, k8serrors.NewForbidden(v1alpha1.Resource("kameletbindings"), "", errors.New("no permission")))
+
+	output, err := runDescribeCmd(mockClient, "k1")
+	assert.NilError(t, err)
+	assert.Check(t, util.ContainsAll(output, "Name:", "k1", "Properties:", "Bindings:", "<unknown>", "no permission"))
+	assert.Check(t, util.ContainsAll(output, "Conditions:"))
+
+	recorder.Validate()
+}
+
+func TestDescribeBindingsAllNamespaces(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createKamelet("k1")
+	recorder.Get(kamelet, nil)
+
+	local := createKameletBindingInNamespace("k1-to-channel", "k1", "default", channelRef("default"))
+	remote := createKameletBindingInNamespace("k1-to-channel", "k1", "other", channelRef("other"))
+	remote.Spec.Source.Ref.Namespace = "default"
+	foreign := createKameletBindingInNamespace("k1-to-broker", "k1", "other", brokerRef("other"))
+	recorder.ListBindings(&v1alpha1.KameletBindingList{Items: []v1alpha1.KameletBinding{*remote, *local, *foreign}}, nil)
+
+	output, err := runDescribeCmd(mockClient, "k1", "--bindings-all-namespaces")
+	assert.NilError(t, err)
+
+	assert.Check(t, util.ContainsAll(output, "default/k1-to-channel", "other/k1-to-channel"))
+	assert.Assert(t, strings.Index(output, "default/k1-to-channel") < strings.Index(output, "other/k1-to-channel"))
+	assert.Assert(t, !strings.Contains(output, "k1-to-broker"))
+
+	recorder.Validate()
+}
+
+func TestDescribeSinkKamelet(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createSinkKameletInNamespace("log-sink", "default")
+	recorder.Get(kamelet, nil)
+
+	recorder.ListBindings(&v1alpha1.KameletBindingList{}, nil)
+
+	output, err := runDescribeCmd(mockClient, "log-sink")
+	assert.NilError(t, err)
+
+	assert.Check(t, util.ContainsAll(output, "Labels:", "camel.apache.org/kamelet.type=sink"))
+	assert.Check(t, util.ContainsAll(output, "Description:", "Kamelet log-sink - Sample Kamelet sink"))
+	assert.Check(t, util.ContainsAll(output, "log-sink_prop", "✓", "string"))
 
 	recorder.Validate()
 }
@@ -117,6 +240,8 @@ func TestDescribeVerboseOutput(t *testing.T) {
 	kamelet := createKamelet("k1")
 	recorder.Get(kamelet, nil)
 
+	recorder.ListBindings(&v1alpha1.KameletBindingList{}, nil)
+
 	output, err := runDescribeCmd(mockClient, "k1", "--verbose")
 	assert.NilError(t, err)
 
@@ -138,9 +263,11 @@ func TestDescribeVerboseOutput(t *testing.T) {
 	assert.Check(t, util.ContainsAll(outputLines[13], "k1_optional", " ", "boolean", "The k1 optional property"))
 	assert.Check(t, util.ContainsAll(outputLines[14], "k1_prop", "✓", "string", "The k1 required property"))
 
-	assert.Check(t, util.ContainsAll(outputLines[16], "Conditions:"))
-	assert.Check(t, util.ContainsAll(outputLines[17], "OK", "TYPE", "AGE", "REASON"))
-	assert.Check(t, util.ContainsAll(outputLines[18], "++", "Ready", "", ""))
+	assert.Check(t, util.ContainsAll(outputLines[16], "Bindings:", "<none>"))
+
+	assert.Check(t, util.ContainsAll(outputLines[18], "Conditions:"))
+	assert.Check(t, util.ContainsAll(outputLines[19], "OK", "TYPE", "AGE", "REASON"))
+	assert.Check(t, util.ContainsAll(outputLines[20], "++", "Ready", "", ""))
 
 	recorder.Validate()
 }
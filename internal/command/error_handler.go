@@ -0,0 +1,502 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	knerrors "knative.dev/client-pkg/pkg/errors"
+)
+
+// KameletBindings of the vendored v1alpha1 API have no error handler field. For them the error handler is a bean
+// registered via application properties of the integration and referenced by the error-handler trait, the same way
+// Camel K sets it up for bindings. Pipes declare the handler natively in spec.errorHandler.
+const (
+	errorSinkLog = "log"
+
+	errorHandlerTrait      = "error-handler"
+	errorHandlerRef        = "defaultErrorHandler"
+	errorHandlerProperty   = "camel.beans." + errorHandlerRef
+	logErrorHandlerClass   = "#class:org.apache.camel.builder.DefaultErrorHandlerBuilder"
+	sinkErrorHandlerClass  = "#class:org.apache.camel.builder.DeadLetterChannelBuilder"
+	deadLetterURIKey       = "deadLetterUri"
+	maximumRedeliveriesKey = "maximumRedeliveries"
+	redeliveryDelayKey     = "redeliveryDelay"
+	httpDependency         = "camel:http"
+)
+
+const (
+	// errorHandlerAnnotation carries the spec.errorHandler of a Pipe while a command works on the v1alpha1 binding
+	errorHandlerAnnotation = "kamelet.knative.dev/error-handler"
+
+	errorHandlerTypeLog  = "log"
+	errorHandlerTypeSink = "sink"
+)
+
+// knativeSinkTraitKeys are the knative trait settings declaring the Knative resources an integration sends to
+var knativeSinkTraitKeys = map[string]string{
+	"channel":  "channelSinks",
+	"endpoint": "endpointSinks",
+	"event":    "eventSinks",
+}
+
+// ErrorHandlerFlags holding the error handler settings of a binding
+type ErrorHandlerFlags struct {
+	// Sink expression receiving failed exchanges, "log" to log them
+	Sink string
+	// MaxRedeliveries before an exchange is handed to the error sink
+	MaxRedeliveries int
+	// RedeliveryDelay between redeliveries
+	RedeliveryDelay time.Duration
+
+	flags *pflag.FlagSet
+}
+
+// AddFlags adds the --error-sink, --error-max-redeliveries and --error-redelivery-delay flags to the given flag set
+func (f *ErrorHandlerFlags) AddFlags(flags *pflag.FlagSet) {
+	f.flags = flags
+	flags.StringVar(&f.Sink, "error-sink", "", `Sink expression receiving the events that failed to be delivered, e.g. channel:<name>, or "log" to log them.`)
+	flags.IntVar(&f.MaxRedeliveries, "error-max-redeliveries", 0, "Number of redelivery attempts before an event is handed to the error sink.")
+	flags.DurationVar(&f.RedeliveryDelay, "error-redelivery-delay", 0, "Delay between redelivery attempts, e.g. 500ms or 2s.")
+}
+
+// bindingErrorHandler is the error handler of a binding
+type bindingErrorHandler struct {
+	// Type of the error handler, log or sink for the handlers the flags configure
+	Type string
+	// Sink receiving the failed exchanges of a sink error handler
+	Sink            *v1alpha1.Endpoint
+	MaxRedeliveries string
+	RedeliveryDelay string
+}
+
+// apply configures the error handler on the given binding, in spec.errorHandler of Pipes and on the integration of
+// KameletBindings. The binding is left unchanged without error sink.
+func (f *ErrorHandlerFlags) apply(ctx context.Context, resolver *sinkResolver, binding *v1alpha1.KameletBinding, pipes bool) error {
+	handler, err := f.resolve(ctx, resolver, binding.Namespace)
+	if err != nil || handler == nil {
+		return err
+	}
+	if pipes {
+		return setBindingErrorHandler(binding, handler)
+	}
+	if binding.Spec.Integration == nil {
+		binding.Spec.Integration = &camelv1.IntegrationSpec{}
+	}
+	return handler.configure(binding.Spec.Integration, binding.Namespace)
+}
+
+// resolve returns the error handler given with the flags, nil when no error sink is given
+func (f *ErrorHandlerFlags) resolve(ctx context.Context, resolver *sinkResolver, namespace string) (*bindingErrorHandler, error) {
+	if f == nil {
+		return nil, nil
+	}
+	maxRedeliveriesSet := f.flags != nil && f.flags.Changed("error-max-redeliveries")
+	redeliveryDelaySet := f.flags != nil && f.flags.Changed("error-redelivery-delay")
+	if f.Sink == "" {
+		if maxRedeliveriesSet || redeliveryDelaySet {
+			return nil, errors.New("--error-max-redeliveries and --error-redelivery-delay require --error-sink")
+		}
+		return nil, nil
+	}
+	if f.MaxRedeliveries < 0 {
+		return nil, fmt.Errorf("invalid number of redeliveries %d - please use a number greater or equal to 0", f.MaxRedeliveries)
+	}
+	if f.RedeliveryDelay < 0 {
+		return nil, fmt.Errorf("invalid redelivery delay %s - please use a positive duration", f.RedeliveryDelay)
+	}
+
+	handler := &bindingErrorHandler{Type: errorHandlerTypeLog}
+	if maxRedeliveriesSet {
+		handler.MaxRedeliveries = strconv.Itoa(f.MaxRedeliveries)
+	}
+	if redeliveryDelaySet {
+		handler.RedeliveryDelay = strconv.FormatInt(f.RedeliveryDelay.Milliseconds(), 10)
+	}
+	if f.Sink != errorSinkLog {
+		endpoint, err := resolver.resolve(ctx, f.Sink, namespace)
+		if err != nil {
+			return nil, knerrors.GetError(err)
+		}
+		handler.Type = errorHandlerTypeSink
+		handler.Sink = &endpoint
+	}
+	return handler, nil
+}
+
+// configure registers the error handler bean on the integration spec and references it by the error-handler trait.
+// Knative sinks get declared in the knative trait, URI sinks add the HTTP dependency.
+func (h *bindingErrorHandler) configure(spec *camelv1.IntegrationSpec, namespace string) error {
+	uri := ""
+	if h.Sink != nil {
+		if h.Sink.URI != nil {
+			uri = *h.Sink.URI
+			if !contains(spec.Dependencies, httpDependency) {
+				spec.Dependencies = append(spec.Dependencies, httpDependency)
+			}
+		} else {
+			if h.Sink.Ref.Namespace != namespace {
+				return fmt.Errorf("error sink %s %q must be in the namespace %q of the binding", h.Sink.Ref.Kind, h.Sink.Ref.Name, namespace)
+			}
+			uri = knativeURI(h.Sink.Ref)
+			if err := addKnativeSink(spec, uri); err != nil {
+				return err
+			}
+		}
+	}
+
+	properties := []string{errorHandlerProperty + "=" + logErrorHandlerClass}
+	if uri != "" {
+		properties = []string{
+			errorHandlerProperty + "=" + sinkErrorHandlerClass,
+			errorHandlerProperty + "." + deadLetterURIKey + "=" + uri,
+		}
+	}
+	if h.MaxRedeliveries != "" {
+		properties = append(properties, errorHandlerProperty+"."+maximumRedeliveriesKey+"="+h.MaxRedeliveries)
+	}
+	if h.RedeliveryDelay != "" {
+		properties = append(properties, errorHandlerProperty+"."+redeliveryDelayKey+"="+h.RedeliveryDelay)
+	}
+	for _, property := range properties {
+		spec.Configuration = append(spec.Configuration, camelv1.ConfigurationSpec{Type: "property", Value: property})
+	}
+	return setTraitValue(spec, errorHandlerTrait, "ref", errorHandlerRef)
+}
+
+// pipeErrorHandler is the spec.errorHandler of a Pipe, keyed by the error handler type
+type pipeErrorHandler map[string]pipeErrorHandlerSettings
+
+// pipeErrorHandlerSettings are the sink endpoint and the error handler parameters like maximumRedeliveries
+type pipeErrorHandlerSettings struct {
+	Endpoint   *v1alpha1.Endpoint     `json:"endpoint,omitempty"`
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// setBindingErrorHandler stores the given error handler in the annotation of the binding, the way Pipes declare it
+// in spec.errorHandler
+func setBindingErrorHandler(binding *v1alpha1.KameletBinding, handler *bindingErrorHandler) error {
+	parameters := make(map[string]interface{})
+	if handler.MaxRedeliveries != "" {
+		parameters[maximumRedeliveriesKey] = json.Number(handler.MaxRedeliveries)
+	}
+	if handler.RedeliveryDelay != "" {
+		parameters[redeliveryDelayKey] = json.Number(handler.RedeliveryDelay)
+	}
+	data, err := json.Marshal(pipeErrorHandler{
+		handler.Type: {Endpoint: handler.Sink, Parameters: parameters},
+	})
+	if err != nil {
+		return err
+	}
+	if binding.Annotations == nil {
+		binding.Annotations = make(map[string]string)
+	}
+	binding.Annotations[errorHandlerAnnotation] = string(data)
+	return nil
+}
+
+// errorHandlerOf returns the error handler of the binding, nil if there is none. The error handler of Pipes is
+// read from their spec.errorHandler, the one of KameletBindings from the integration.
+func errorHandlerOf(binding *v1alpha1.KameletBinding) *bindingErrorHandler {
+	if data, ok := binding.Annotations[errorHandlerAnnotation]; ok {
+		return pipeErrorHandlerOf(data)
+	}
+	if binding.Spec.Integration == nil {
+		return nil
+	}
+
+	var handler *bindingErrorHandler
+	for _, c := range binding.Spec.Integration.Configuration {
+		key, value, ok := strings.Cut(c.Value, "=")
+		if c.Type != "property" || !ok || (key != errorHandlerProperty && !strings.HasPrefix(key, errorHandlerProperty+".")) {
+			continue
+		}
+		if handler == nil {
+			handler = &bindingErrorHandler{Type: errorHandlerTypeLog}
+		}
+		switch strings.TrimPrefix(key, errorHandlerProperty+".") {
+		case deadLetterURIKey:
+			handler.Type = errorHandlerTypeSink
+			if ref, ok := knativeRef(value); ok {
+				handler.Sink = &v1alpha1.Endpoint{Ref: ref}
+			} else {
+				uri := value
+				handler.Sink = &v1alpha1.Endpoint{URI: &uri}
+			}
+		case maximumRedeliveriesKey:
+			handler.MaxRedeliveries = value
+		case redeliveryDelayKey:
+			handler.RedeliveryDelay = value
+		}
+	}
+	return handler
+}
+
+// pipeErrorHandlerOf returns the error handler of the given spec.errorHandler of a Pipe, nil if it can not be read
+func pipeErrorHandlerOf(data string) *bindingErrorHandler {
+	spec := pipeErrorHandler{}
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&spec); err != nil || len(spec) != 1 {
+		return nil
+	}
+	for handlerType, content := range spec {
+		handler := &bindingErrorHandler{Type: handlerType, Sink: content.Endpoint}
+		if value, ok := content.Parameters[maximumRedeliveriesKey]; ok {
+			handler.MaxRedeliveries = propertyArgValue(value)
+		}
+		if value, ok := content.Parameters[redeliveryDelayKey]; ok {
+			handler.RedeliveryDelay = propertyArgValue(value)
+		}
+		return handler
+	}
+	return nil
+}
+
+// errorHandlerCommandArgs returns the --error-sink, --error-max-redeliveries and --error-redelivery-delay options
+// for the error handler of a binding in the given namespace
+func errorHandlerCommandArgs(handler *bindingErrorHandler, namespace string) ([]string, error) {
+	var sink string
+	switch {
+	case handler.Type == errorHandlerTypeLog:
+		sink = errorSinkLog
+	case handler.Type != errorHandlerTypeSink || handler.Sink == nil:
+		return nil, fmt.Errorf("error handler of type %q can not be expressed as command option", handler.Type)
+	case handler.Sink.Properties != nil && len(handler.Sink.Properties.RawMessage) > 0 && string(handler.Sink.Properties.RawMessage) != "{}":
+		return nil, errors.New("error sink with properties can not be expressed as command option")
+	case handler.Sink.URI != nil && !isURISink(*handler.Sink.URI):
+		return nil, fmt.Errorf("error sink %q can not be expressed as command option", *handler.Sink.URI)
+	default:
+		expression, err := endpointExpression(*handler.Sink, namespace)
+		if err != nil {
+			return nil, err
+		}
+		sink = expression
+	}
+
+	args := []string{"--error-sink", sink}
+	if handler.MaxRedeliveries != "" {
+		args = append(args, "--error-max-redeliveries", handler.MaxRedeliveries)
+	}
+	if handler.RedeliveryDelay != "" {
+		delay, err := strconv.ParseInt(handler.RedeliveryDelay, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redelivery delay %q of the error handler", handler.RedeliveryDelay)
+		}
+		args = append(args, "--error-redelivery-delay", (time.Duration(delay) * time.Millisecond).String())
+	}
+	return args, nil
+}
+
+// removeErrorHandler removes the error handler settings from the given integration spec
+func removeErrorHandler(spec *camelv1.IntegrationSpec) error {
+	var uri string
+	configuration := spec.Configuration[:0]
+	for _, c := range spec.Configuration {
+		key, value, _ := strings.Cut(c.Value, "=")
+		if c.Type == "property" && (key == errorHandlerProperty || strings.HasPrefix(key, errorHandlerProperty+".")) {
+			if key == errorHandlerProperty+"."+deadLetterURIKey {
+				uri = value
+			}
+			continue
+		}
+		configuration = append(configuration, c)
+	}
+	spec.Configuration = configuration
+	if len(spec.Configuration) == 0 {
+		spec.Configuration = nil
+	}
+	delete(spec.Traits, errorHandlerTrait)
+
+	if uri == "" {
+		return nil
+	}
+	if _, ok := knativeRef(uri); !ok {
+		dependencies := spec.Dependencies[:0]
+		for _, dependency := range spec.Dependencies {
+			if dependency != httpDependency {
+				dependencies = append(dependencies, dependency)
+			}
+		}
+		spec.Dependencies = dependencies
+		if len(spec.Dependencies) == 0 {
+			spec.Dependencies = nil
+		}
+		return nil
+	}
+
+	key := knativeSinkTraitKey(uri)
+	knative, err := traitConfiguration("knative", spec.Traits["knative"])
+	if err != nil {
+		return err
+	}
+	sinks, _ := knative[key].([]interface{})
+	var remaining []interface{}
+	for _, sink := range sinks {
+		if sink != uri {
+			remaining = append(remaining, sink)
+		}
+	}
+	if len(remaining) > 0 {
+		return setTraitValue(spec, "knative", key, remaining)
+	}
+	delete(knative, key)
+	if len(knative) == 0 {
+		delete(spec.Traits, "knative")
+		return nil
+	}
+	data, err := json.Marshal(knative)
+	if err != nil {
+		return err
+	}
+	spec.Traits["knative"] = camelv1.TraitSpec{Configuration: camelv1.TraitConfiguration{RawMessage: data}}
+	return nil
+}
+
+// errorHandlerToSpec moves the error handler carried in the annotation of a binding into spec.errorHandler
+func errorHandlerToSpec(content map[string]interface{}) {
+	annotations, _, _ := unstructured.NestedStringMap(content, "metadata", "annotations")
+	data, ok := annotations[errorHandlerAnnotation]
+	if !ok {
+		return
+	}
+	var handler map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&handler); err != nil {
+		return
+	}
+
+	delete(annotations, errorHandlerAnnotation)
+	if len(annotations) == 0 {
+		unstructured.RemoveNestedField(content, "metadata", "annotations")
+	} else {
+		_ = unstructured.SetNestedStringMap(content, annotations, "metadata", "annotations")
+	}
+	_ = unstructured.SetNestedField(content, handler, "spec", "errorHandler")
+}
+
+// errorHandlerToAnnotation moves spec.errorHandler into the annotation of the binding
+func errorHandlerToAnnotation(content map[string]interface{}) {
+	handler, found, err := unstructured.NestedFieldNoCopy(content, "spec", "errorHandler")
+	if !found || err != nil {
+		return
+	}
+	unstructured.RemoveNestedField(content, "spec", "errorHandler")
+	data, err := json.Marshal(handler)
+	if err != nil {
+		return
+	}
+	_ = unstructured.SetNestedField(content, string(data), "metadata", "annotations", errorHandlerAnnotation)
+}
+
+// knativeURI returns the Camel Knative endpoint URI of the given reference
+func knativeURI(ref *corev1.ObjectReference) string {
+	query := url.Values{}
+	query.Set("apiVersion", ref.APIVersion)
+	query.Set("kind", ref.Kind)
+
+	switch {
+	case ref.Kind == "Broker":
+		query.Set("name", ref.Name)
+		return "knative:event?" + query.Encode()
+	case strings.HasPrefix(ref.APIVersion, "messaging.knative.dev/"):
+		return "knative:channel/" + ref.Name + "?" + query.Encode()
+	default:
+		return "knative:endpoint/" + ref.Name + "?" + query.Encode()
+	}
+}
+
+// knativeRef returns the reference of the given Camel Knative endpoint URI
+func knativeRef(uri string) (*corev1.ObjectReference, bool) {
+	if !strings.HasPrefix(uri, "knative:") {
+		return nil, false
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, false
+	}
+	path, query := u.Opaque, u.Query()
+	ref := &corev1.ObjectReference{
+		APIVersion: query.Get("apiVersion"),
+		Kind:       query.Get("kind"),
+		Name:       query.Get("name"),
+	}
+	if _, name, ok := strings.Cut(path, "/"); ok && name != "" {
+		ref.Name = name
+	}
+	if ref.APIVersion == "" || ref.Kind == "" || ref.Name == "" {
+		return nil, false
+	}
+	return ref, true
+}
+
+// knativeSinkTraitKey returns the knative trait setting for the given Camel Knative endpoint URI
+func knativeSinkTraitKey(uri string) string {
+	resource := strings.TrimPrefix(uri, "knative:")
+	resource = strings.FieldsFunc(resource, func(r rune) bool { return r == '/' || r == '?' })[0]
+	return knativeSinkTraitKeys[resource]
+}
+
+// addKnativeSink declares the Knative endpoint URI as sink of the integration in the knative trait
+func addKnativeSink(spec *camelv1.IntegrationSpec, uri string) error {
+	key := knativeSinkTraitKey(uri)
+	configuration, err := traitConfiguration("knative", spec.Traits["knative"])
+	if err != nil {
+		return err
+	}
+	sinks, _ := configuration[key].([]interface{})
+	for _, sink := range sinks {
+		if sink == uri {
+			return nil
+		}
+	}
+	return setTraitValue(spec, "knative", key, append(sinks, uri))
+}
+
+// setTraitValue sets a single key of the trait configuration in the spec
+func setTraitValue(spec *camelv1.IntegrationSpec, name string, key string, value interface{}) error {
+	configuration, err := traitConfiguration(name, spec.Traits[name])
+	if err != nil {
+		return err
+	}
+	configuration[key] = value
+	data, err := json.Marshal(configuration)
+	if err != nil {
+		return err
+	}
+	if spec.Traits == nil {
+		spec.Traits = make(map[string]camelv1.TraitSpec)
+	}
+	spec.Traits[name] = camelv1.TraitSpec{
+		Configuration: camelv1.TraitConfiguration{RawMessage: data},
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clienteventingv1beta1 "knative.dev/client-pkg/pkg/eventing/v1beta1"
+	"knative.dev/eventing/pkg/apis/eventing"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+const (
+	// bindingLabel marks the resources created along with a binding, its value is the binding name
+	bindingLabel = "kamelet.knative.dev/binding"
+	// eventTypeAnnotation names the Knative EventType registered for the events of a binding
+	eventTypeAnnotation = "kamelet.knative.dev/event-type"
+)
+
+// bindingEventType creates the Knative EventType describing the events the binding sends to its broker sink. The
+// type is the cloud events type of the binding sink, the source is the overridden cloud events source or the Kamelet
+// name and the schema is the schema of the out slot declared on the binding or the Kamelet.
+func bindingEventType(kamelet *v1alpha1.Kamelet, binding *v1alpha1.KameletBinding) (*eventingv1beta1.EventType, error) {
+	if !isEventSourceType(kamelet) {
+		return nil, fmt.Errorf("kamelet %s is not an event source - --register-event-type is only supported for source Kamelets", kamelet.Name)
+	}
+	broker := binding.Spec.Sink.Ref
+	if !isBrokerRef(broker) {
+		return nil, errors.New("--register-event-type requires a broker as binding sink")
+	}
+
+	props, err := binding.Spec.Sink.Properties.GetPropertyMap()
+	if err != nil {
+		return nil, err
+	}
+	ceType := props[cloudEventsTypeProperty]
+	if ceType == "" {
+		return nil, errors.New("--register-event-type requires the cloud events type of the binding given with --ce-type")
+	}
+	ceSource := props[cloudEventsOverridePrefix+"source"]
+	if ceSource == "" {
+		ceSource = kamelet.Name
+	}
+	source, err := apis.ParseURL(ceSource)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cloud events source %q: %v", ceSource, err)
+	}
+
+	eventType := &eventingv1beta1.EventType{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      binding.Name,
+			Namespace: binding.Namespace,
+			Labels: map[string]string{
+				bindingLabel: binding.Name,
+			},
+		},
+		Spec: eventingv1beta1.EventTypeSpec{
+			Type:   ceType,
+			Source: source,
+			Broker: broker.Name,
+			Reference: &duckv1.KReference{
+				APIVersion: broker.APIVersion,
+				Kind:       broker.Kind,
+				Name:       broker.Name,
+				Namespace:  broker.Namespace,
+			},
+			Description: fmt.Sprintf("Events of Kamelet binding %s", binding.Name),
+		},
+	}
+
+	// a schema declared on the binding has been checked against the Kamelet schema and is more specific
+	schema := kamelet.Spec.Types[v1alpha1.EventSlotOut].Schema
+	if declared := binding.Spec.Source.Types[v1alpha1.EventSlotOut].Schema; declared != nil {
+		schema = declared
+	}
+	if schema != nil {
+		data, err := json.Marshal(schema)
+		if err != nil {
+			return nil, err
+		}
+		eventType.Spec.SchemaData = string(data)
+	}
+
+	return eventType, nil
+}
+
+// checkBindingEventType makes sure an existing EventType of the same name belongs to the binding of the given one
+func checkBindingEventType(client clienteventingv1beta1.KnEventingV1Beta1Client, ctx context.Context, eventType *eventingv1beta1.EventType) error {
+	existing, err := client.GetEventtype(ctx, eventType.Name)
+	switch {
+	case k8serrors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return err
+	case existing.Labels[bindingLabel] != eventType.Labels[bindingLabel]:
+		return fmt.Errorf("event type %q already exists and does not belong to kamelet binding %q", eventType.Name, eventType.Labels[bindingLabel])
+	}
+	return nil
+}
+
+// createBindingEventType creates the EventType, an EventType of the same name registered before for the binding is
+// replaced
+func createBindingEventType(client clienteventingv1beta1.KnEventingV1Beta1Client, ctx context.Context, eventType *eventingv1beta1.EventType, cmdOut io.Writer) error {
+	existing, err := client.GetEventtype(ctx, eventType.Name)
+	switch {
+	case err == nil:
+		if existing.Labels[bindingLabel] != eventType.Labels[bindingLabel] {
+			return fmt.Errorf("event type %q already exists and does not belong to kamelet binding %q", eventType.Name, eventType.Labels[bindingLabel])
+		}
+		if err := client.DeleteEventtype(ctx, eventType.Name); err != nil {
+			return err
+		}
+	case !k8serrors.IsNotFound(err):
+		return err
+	}
+
+	if err := client.CreateEventtype(ctx, eventType); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(cmdOut, "event type %q registered\n", eventType.Name)
+	return nil
+}
+
+// deleteBindingEventType deletes the EventType registered for the given binding, an EventType that is already gone is
+// ignored
+func deleteBindingEventType(client clienteventingv1beta1.KnEventingV1Beta1Client, ctx context.Context, binding *v1alpha1.KameletBinding, cmdOut io.Writer) error {
+	name := binding.Annotations[eventTypeAnnotation]
+	if name == "" {
+		return nil
+	}
+
+	err := client.DeleteEventtype(ctx, name)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	if err == nil {
+		_, _ = fmt.Fprintf(cmdOut, "event type %q deleted\n", name)
+	}
+	return nil
+}
+
+// isBrokerRef returns true when the given reference points to a Knative broker
+func isBrokerRef(ref *corev1.ObjectReference) bool {
+	return ref != nil && ref.Kind == "Broker" && strings.HasPrefix(ref.APIVersion, eventing.GroupName+"/")
+}
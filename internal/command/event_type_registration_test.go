@@ -0,0 +1,146 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBindingEventTypeSourceDefaultsToKamelet(t *testing.T) {
+	kamelet := createKameletInNamespace("k1", "current")
+	binding := createKameletBindingInNamespace("k1-to-broker", "k1", "current", brokerRef("current"))
+	binding.Spec.Sink.Properties.RawMessage = []byte(`{"cloudEventsType":"org.example.order"}`)
+
+	eventType, err := bindingEventType(kamelet, binding)
+	assert.NilError(t, err)
+	assert.Equal(t, eventType.Name, "k1-to-broker")
+	assert.Equal(t, eventType.Namespace, "current")
+	assert.Equal(t, eventType.Spec.Source.String(), "k1")
+	assert.Equal(t, eventType.Spec.Broker, "default")
+	assert.Equal(t, eventType.Spec.SchemaData, "")
+}
+
+func TestBindingEventTypePrefersBindingSchema(t *testing.T) {
+	kamelet := createKameletInNamespace("k1", "current")
+	kamelet.Spec.Types = map[v1alpha1.EventSlot]v1alpha1.EventTypeSpec{
+		v1alpha1.EventSlotOut: {Schema: &v1alpha1.JSONSchemaProps{Type: "object"}},
+	}
+	binding := createKameletBindingInNamespace("k1-to-broker", "k1", "current", brokerRef("current"))
+	binding.Spec.Source.Types = map[v1alpha1.EventSlot]v1alpha1.EventTypeSpec{
+		v1alpha1.EventSlotOut: {Schema: eventSchema("string")},
+	}
+	binding.Spec.Sink.Properties.RawMessage = []byte(`{"cloudEventsType":"org.example.order"}`)
+
+	eventType, err := bindingEventType(kamelet, binding)
+	assert.NilError(t, err)
+	assert.Equal(t, eventType.Spec.SchemaData, `{"type":"object","required":["id"],"properties":{"id":{"description":"Event id","type":"string"}}}`)
+}
+
+func TestCreateBindingEventTypeReplacesExisting(t *testing.T) {
+	existing := &eventingv1beta1.EventType{
+		ObjectMeta: v1.ObjectMeta{Name: "k1-to-broker", Labels: map[string]string{bindingLabel: "k1-to-broker"}},
+		Spec:       eventingv1beta1.EventTypeSpec{Type: "org.example.old"},
+	}
+	client := newFakeEventTypeClient(existing)
+
+	eventType := existing.DeepCopy()
+	eventType.Spec.Type = "org.example.order"
+	err := createBindingEventType(client, context.TODO(), eventType, &bytes.Buffer{})
+	assert.NilError(t, err)
+	assert.Equal(t, client.eventTypes["k1-to-broker"].Spec.Type, "org.example.order")
+}
+
+func TestCreateBindingEventTypeErrorCaseForeignEventType(t *testing.T) {
+	client := newFakeEventTypeClient(&eventingv1beta1.EventType{
+		ObjectMeta: v1.ObjectMeta{Name: "k1-to-broker"},
+	})
+
+	eventType := &eventingv1beta1.EventType{
+		ObjectMeta: v1.ObjectMeta{Name: "k1-to-broker", Labels: map[string]string{bindingLabel: "k1-to-broker"}},
+	}
+	err := createBindingEventType(client, context.TODO(), eventType, &bytes.Buffer{})
+	assert.Error(t, err, "event type \"k1-to-broker\" already exists and does not belong to kamelet binding \"k1-to-broker\"")
+}
+
+func brokerRef(namespace string) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		Kind:       "Broker",
+		APIVersion: eventingv1.SchemeGroupVersion.String(),
+		Namespace:  namespace,
+		Name:       "default",
+	}
+}
+
+// fakeEventTypeClient keeps the event types in memory
+type fakeEventTypeClient struct {
+	eventTypes map[string]*eventingv1beta1.EventType
+}
+
+func newFakeEventTypeClient(eventTypes ...*eventingv1beta1.EventType) *fakeEventTypeClient {
+	client := &fakeEventTypeClient{eventTypes: make(map[string]*eventingv1beta1.EventType)}
+	for _, eventType := range eventTypes {
+		client.eventTypes[eventType.Name] = eventType
+	}
+	return client
+}
+
+func (c *fakeEventTypeClient) Namespace() string {
+	return "current"
+}
+
+func (c *fakeEventTypeClient) ListEventtypes(ctx context.Context) (*eventingv1beta1.EventTypeList, error) {
+	list := &eventingv1beta1.EventTypeList{}
+	for _, eventType := range c.eventTypes {
+		list.Items = append(list.Items, *eventType)
+	}
+	return list, nil
+}
+
+func (c *fakeEventTypeClient) GetEventtype(ctx context.Context, name string) (*eventingv1beta1.EventType, error) {
+	eventType, ok := c.eventTypes[name]
+	if !ok {
+		return nil, k8serrors.NewNotFound(eventingv1beta1.Resource("eventtypes"), name)
+	}
+	return eventType, nil
+}
+
+func (c *fakeEventTypeClient) CreateEventtype(ctx context.Context, eventType *eventingv1beta1.EventType) error {
+	if _, ok := c.eventTypes[eventType.Name]; ok {
+		return k8serrors.NewAlreadyExists(eventingv1beta1.Resource("eventtypes"), eventType.Name)
+	}
+	c.eventTypes[eventType.Name] = eventType
+	return nil
+}
+
+func (c *fakeEventTypeClient) DeleteEventtype(ctx context.Context, name string) error {
+	if _, ok := c.eventTypes[name]; !ok {
+		return k8serrors.NewNotFound(eventingv1beta1.Resource("eventtypes"), name)
+	}
+	delete(c.eventTypes, name)
+	return nil
+}
@@ -0,0 +1,208 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	"github.com/spf13/pflag"
+	"knative.dev/client-pkg/pkg/printers"
+	"sigs.k8s.io/yaml"
+)
+
+// eventSlots are the event slots of a Kamelet in the order they are printed
+var eventSlots = []v1alpha1.EventSlot{v1alpha1.EventSlotIn, v1alpha1.EventSlotOut, v1alpha1.EventSlotError}
+
+// EventTypeFlags holding the event types declared on the Kamelet endpoint of a binding
+type EventTypeFlags struct {
+	// OutMediaType of the events produced by a source Kamelet
+	OutMediaType string
+	// OutSchema file holding the JSON schema of the events produced by a source Kamelet
+	OutSchema string
+	// InMediaType of the events consumed by a sink Kamelet
+	InMediaType string
+}
+
+// AddFlags adds the --out-media-type, --out-schema and --in-media-type flags to the given flag set
+func (f *EventTypeFlags) AddFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&f.OutMediaType, "out-media-type", "", "Media type of the events produced by the source Kamelet, e.g. application/json.")
+	flags.StringVar(&f.OutSchema, "out-schema", "", "JSON or YAML file holding the JSON schema of the events produced by the source Kamelet.")
+	flags.StringVar(&f.InMediaType, "in-media-type", "", "Media type of the events consumed by the sink Kamelet, e.g. application/json.")
+}
+
+// endpointTypes returns the event types of the Kamelet endpoint checked against the types declared by the Kamelet,
+// nil when no event type is given
+func (f *EventTypeFlags) endpointTypes(kamelet *v1alpha1.Kamelet) (map[v1alpha1.EventSlot]v1alpha1.EventTypeSpec, error) {
+	if f == nil || (f.OutMediaType == "" && f.OutSchema == "" && f.InMediaType == "") {
+		return nil, nil
+	}
+
+	types := make(map[v1alpha1.EventSlot]v1alpha1.EventTypeSpec)
+	if f.OutMediaType != "" || f.OutSchema != "" {
+		if !isEventSourceType(kamelet) {
+			return nil, fmt.Errorf("kamelet %s is not an event source - --out-media-type and --out-schema are only supported for source Kamelets", kamelet.Name)
+		}
+		out := v1alpha1.EventTypeSpec{MediaType: f.OutMediaType}
+		if f.OutSchema != "" {
+			schema, err := readSchemaFile(f.OutSchema)
+			if err != nil {
+				return nil, err
+			}
+			out.Schema = schema
+		}
+		types[v1alpha1.EventSlotOut] = out
+	}
+	if f.InMediaType != "" {
+		if !isEventSinkType(kamelet) {
+			return nil, fmt.Errorf("kamelet %s is not an event sink - --in-media-type is only supported for sink Kamelets", kamelet.Name)
+		}
+		types[v1alpha1.EventSlotIn] = v1alpha1.EventTypeSpec{MediaType: f.InMediaType}
+	}
+
+	for slot, eventType := range types {
+		if err := checkEventType(kamelet, slot, eventType); err != nil {
+			return nil, err
+		}
+	}
+	return types, nil
+}
+
+// readSchemaFile reads a JSON schema from the given JSON or YAML file. Keywords the Kamelet schema does not know, such
+// as the draft-07 $id, examples and const, are ignored.
+func readSchemaFile(file string) (*v1alpha1.JSONSchemaProps, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read schema file %q: %v", file, err)
+	}
+	schema := &v1alpha1.JSONSchemaProps{}
+	if err := yaml.Unmarshal(data, schema); err != nil {
+		return nil, fmt.Errorf("invalid schema file %q: %v", file, err)
+	}
+	return schema, nil
+}
+
+// checkEventType makes sure the event type matches the event type the Kamelet declares for the slot. Kamelets that
+// do not declare the slot accept any event type.
+func checkEventType(kamelet *v1alpha1.Kamelet, slot v1alpha1.EventSlot, eventType v1alpha1.EventTypeSpec) error {
+	declared, ok := kamelet.Spec.Types[slot]
+	if !ok {
+		return nil
+	}
+	if declared.MediaType != "" && eventType.MediaType != "" && !strings.EqualFold(declared.MediaType, eventType.MediaType) {
+		return fmt.Errorf("media type %q does not match media type %q of the %s slot of Kamelet %s",
+			eventType.MediaType, declared.MediaType, slot, kamelet.Name)
+	}
+	if declared.Schema != nil && eventType.Schema != nil {
+		if problems := schemaMismatches("", *declared.Schema, *eventType.Schema); len(problems) > 0 {
+			return fmt.Errorf("schema does not match the schema of the %s slot of Kamelet %s: %s", slot, kamelet.Name, strings.Join(problems, ", "))
+		}
+	}
+	return nil
+}
+
+// schemaMismatches returns the properties of the given schema whose type differs from the declared schema as well as
+// required properties of the declared schema that are missing
+func schemaMismatches(path string, declared v1alpha1.JSONSchemaProps, schema v1alpha1.JSONSchemaProps) []string {
+	name := path
+	if name == "" {
+		name = "<root>"
+	}
+	if declared.Type != "" && schema.Type != "" && declared.Type != schema.Type {
+		return []string{fmt.Sprintf("%s is of type %s instead of %s", name, schema.Type, declared.Type)}
+	}
+
+	var problems []string
+	for _, required := range declared.Required {
+		if _, ok := schema.Properties[required]; !ok && len(schema.Properties) > 0 {
+			problems = append(problems, fmt.Sprintf("missing required property %s", joinSchemaPath(path, required)))
+		}
+	}
+	for _, key := range sortedKeys(schema.Properties) {
+		if declaredProperty, ok := declared.Properties[key]; ok {
+			problems = append(problems, schemaMismatches(joinSchemaPath(path, key), declaredProperty, schema.Properties[key])...)
+		}
+	}
+	return problems
+}
+
+func joinSchemaPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// writeEventTypes prints the media type and the schema tree of each event slot
+func writeEventTypes(dw printers.PrefixWriter, types map[v1alpha1.EventSlot]v1alpha1.EventTypeSpec) {
+	if len(types) == 0 {
+		return
+	}
+
+	section := dw.WriteAttribute("Types", "")
+	for _, slot := range eventSlots {
+		eventType, ok := types[slot]
+		if !ok {
+			continue
+		}
+		slotSection := section.WriteAttribute(string(slot), "")
+		if eventType.MediaType != "" {
+			slotSection.WriteAttribute("Media Type", eventType.MediaType)
+		}
+		if eventType.Schema != nil {
+			schemaSection := slotSection.WriteAttribute("Schema", schemaTypeDescription(*eventType.Schema, false))
+			writeSchemaTree(schemaSection, *eventType.Schema, "")
+		}
+	}
+}
+
+// writeSchemaTree prints the properties of the schema indented by their nesting level
+func writeSchemaTree(dw printers.PrefixWriter, schema v1alpha1.JSONSchemaProps, indent string) {
+	if schema.Type == "array" && schema.Items != nil {
+		schema = *schema.Items
+	}
+
+	for _, name := range sortedKeys(schema.Properties) {
+		property := schema.Properties[name]
+		line := indent + name + " " + schemaTypeDescription(property, contains(schema.Required, name))
+		if property.Description != "" {
+			line += " - " + property.Description
+		}
+		dw.Writef("%s\n", line)
+		writeSchemaTree(dw, property, indent+"  ")
+	}
+}
+
+// schemaTypeDescription returns the type of the schema, e.g. "array of string", marked as required if requested
+func schemaTypeDescription(schema v1alpha1.JSONSchemaProps, required bool) string {
+	description := schema.Type
+	if description == "" {
+		description = "any"
+	}
+	if schema.Type == "array" && schema.Items != nil && schema.Items.Type != "" {
+		description += " of " + schema.Items.Type
+	}
+	if schema.Format != "" {
+		description += ", format " + schema.Format
+	}
+	if required {
+		description += ", required"
+	}
+	return "(" + description + ")"
+}
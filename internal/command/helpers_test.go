@@ -78,6 +78,20 @@ func createKameletInNamespace(kameletName string, namespace string) *camelkv1alp
 	}
 }
 
+func createSinkKameletInNamespace(kameletName string, namespace string) *camelkv1alpha1.Kamelet {
+	kamelet := createKameletInNamespace(kameletName, namespace)
+	kamelet.Labels[KameletTypeLabel] = "sink"
+	kamelet.Spec.Definition.Description = "Sample Kamelet sink"
+	return kamelet
+}
+
+func createActionKameletInNamespace(kameletName string, namespace string) *camelkv1alpha1.Kamelet {
+	kamelet := createKameletInNamespace(kameletName, namespace)
+	kamelet.Labels[KameletTypeLabel] = "action"
+	kamelet.Spec.Definition.Description = "Sample Kamelet action"
+	return kamelet
+}
+
 func createKameletBinding(bindingName string, kameletName string, sink *corev1.ObjectReference) *camelkv1alpha1.KameletBinding {
 	return createKameletBindingInNamespace(bindingName, kameletName, "default", sink)
 }
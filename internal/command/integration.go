@@ -0,0 +1,277 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	camelk "github.com/apache/camel-k/pkg/client/camel/clientset/versioned"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// knownTraits are the Camel K traits that can be configured with --trait
+var knownTraits = []string{
+	"3scale", "affinity", "builder", "camel", "container", "cron", "dependencies", "deployer", "deployment",
+	"environment", "error-handler", "gc", "health", "ingress", "istio", "jolokia", "jvm", "kamelets", "keda",
+	"knative", "knative-service", "logging", "master", "mount", "openapi", "owner", "pdb", "platform", "pod",
+	"prometheus", "pull-secret", "quarkus", "registry", "route", "service", "service-binding", "toleration", "tracing",
+}
+
+// IntegrationClient looks up the Camel K Integrations running the bindings, their pods and logs
+type IntegrationClient interface {
+	GetIntegration(ctx context.Context, namespace string, name string) (*camelv1.Integration, error)
+	ListIntegrations(ctx context.Context, namespace string) ([]camelv1.Integration, error)
+	ListPods(ctx context.Context, namespace string, selector string) ([]corev1.Pod, error)
+	StreamLogs(ctx context.Context, namespace string, pod string, options *corev1.PodLogOptions) (io.ReadCloser, error)
+}
+
+// IntegrationFlags holding the Camel K integration settings of a binding
+type IntegrationFlags struct {
+	// Traits in the form of "<trait>.<key>=<value>"
+	Traits []string
+	// Profile of the integration, one of Kubernetes, Knative or OpenShift
+	Profile string
+	// Dependencies added to the integration, e.g. "mvn:org.example:library:1.0"
+	Dependencies []string
+	// ServiceAccount running the integration
+	ServiceAccount string
+	// Replicas of the integration, only applied when the flag is given
+	Replicas int32
+
+	flags *pflag.FlagSet
+}
+
+// AddFlags adds the --trait, --profile, --dependency, --service-account and --replicas flags to the given flag set.
+// With removable set traits can be removed in the form of "<trait>.<key>-".
+func (f *IntegrationFlags) AddFlags(flags *pflag.FlagSet, removable bool) {
+	f.flags = flags
+	traitUsage := `Configure a Camel K trait of the integration in the form of "<trait>.<key>=<value>", e.g. prometheus.enabled=true`
+	if removable {
+		traitUsage += `, remove a trait setting with "<trait>.<key>-"`
+	}
+	flags.StringArrayVar(&f.Traits, "trait", nil, traitUsage)
+	flags.StringVar(&f.Profile, "profile", "", "Camel K trait profile of the integration, one of Kubernetes, Knative or OpenShift.")
+	flags.StringArrayVar(&f.Dependencies, "dependency", nil, `Add a dependency to the integration, e.g. "mvn:org.example:library:1.0".`)
+	flags.StringVar(&f.ServiceAccount, "service-account", "", "Service account running the integration.")
+	flags.Int32Var(&f.Replicas, "replicas", 0, "Number of integration pods.")
+}
+
+// specified returns true when any integration setting is given
+func (f *IntegrationFlags) specified() bool {
+	return len(f.Traits) > 0 || f.Profile != "" || len(f.Dependencies) > 0 || f.ServiceAccount != "" || f.replicasSpecified()
+}
+
+func (f *IntegrationFlags) replicasSpecified() bool {
+	return f.flags != nil && f.flags.Changed("replicas")
+}
+
+// apply merges the integration settings into the given integration spec. The spec is created when settings are
+// given and returned unchanged otherwise.
+func (f *IntegrationFlags) apply(spec *camelv1.IntegrationSpec) (*camelv1.IntegrationSpec, error) {
+	if f == nil || !f.specified() {
+		return spec, nil
+	}
+	if spec == nil {
+		spec = &camelv1.IntegrationSpec{}
+	}
+
+	if err := mergeTraits(spec, f.Traits); err != nil {
+		return nil, err
+	}
+
+	if f.Profile != "" {
+		profile, err := traitProfile(f.Profile)
+		if err != nil {
+			return nil, err
+		}
+		spec.Profile = profile
+	}
+
+	for _, dependency := range f.Dependencies {
+		if !contains(spec.Dependencies, dependency) {
+			spec.Dependencies = append(spec.Dependencies, dependency)
+		}
+	}
+
+	if f.ServiceAccount != "" {
+		spec.ServiceAccountName = f.ServiceAccount
+	}
+
+	if f.replicasSpecified() {
+		if f.Replicas < 0 {
+			return nil, fmt.Errorf("invalid number of replicas %d - please use a number greater or equal to 0", f.Replicas)
+		}
+		replicas := f.Replicas
+		spec.Replicas = &replicas
+	}
+
+	return spec, nil
+}
+
+// mergeTraits applies the trait settings to the trait configuration of the spec. Repeating a key collects the
+// values in an array and a setting in the form of "<trait>.<key>-" removes the key.
+func mergeTraits(spec *camelv1.IntegrationSpec, settings []string) error {
+	configurations := make(map[string]map[string]interface{})
+	for name, trait := range spec.Traits {
+		configuration, err := traitConfiguration(name, trait)
+		if err != nil {
+			return err
+		}
+		configurations[name] = configuration
+	}
+
+	given := make(map[string]bool)
+	for _, setting := range settings {
+		remove := !strings.Contains(setting, "=") && strings.HasSuffix(setting, "-")
+		key, value := strings.TrimSuffix(setting, "-"), ""
+		if !remove {
+			var err error
+			key, value, err = parseProperty(setting)
+			if err != nil {
+				return fmt.Errorf(`invalid trait %q - please use the form "<trait>.<key>=<value>"`, setting)
+			}
+		}
+
+		name, traitKey, ok := strings.Cut(key, ".")
+		if !ok || name == "" || traitKey == "" {
+			return fmt.Errorf(`invalid trait %q - please use the form "<trait>.<key>=<value>"`, setting)
+		}
+		if !contains(knownTraits, name) {
+			return fmt.Errorf("unknown trait %q - please use one of %s", name, strings.Join(knownTraits, ", "))
+		}
+
+		configuration, ok := configurations[name]
+		if !ok {
+			configuration = make(map[string]interface{})
+			configurations[name] = configuration
+		}
+
+		if remove {
+			delete(configuration, traitKey)
+			continue
+		}
+
+		traitValue := parseTraitValue(value)
+		if existing, ok := configuration[traitKey]; ok && given[key] {
+			if values, ok := existing.([]interface{}); ok {
+				configuration[traitKey] = append(values, traitValue)
+			} else {
+				configuration[traitKey] = []interface{}{existing, traitValue}
+			}
+		} else {
+			configuration[traitKey] = traitValue
+		}
+		given[key] = true
+	}
+
+	spec.Traits = nil
+	for name, configuration := range configurations {
+		if len(configuration) == 0 {
+			continue
+		}
+		data, err := json.Marshal(configuration)
+		if err != nil {
+			return err
+		}
+		if spec.Traits == nil {
+			spec.Traits = make(map[string]camelv1.TraitSpec)
+		}
+		spec.Traits[name] = camelv1.TraitSpec{
+			Configuration: camelv1.TraitConfiguration{RawMessage: data},
+		}
+	}
+	return nil
+}
+
+// traitConfiguration decodes the configuration of the given trait keeping numbers as they are
+func traitConfiguration(name string, trait camelv1.TraitSpec) (map[string]interface{}, error) {
+	configuration := make(map[string]interface{})
+	if len(trait.Configuration.RawMessage) == 0 {
+		return configuration, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(trait.Configuration.RawMessage))
+	decoder.UseNumber()
+	if err := decoder.Decode(&configuration); err != nil {
+		return nil, fmt.Errorf("invalid configuration of trait %q: %v", name, err)
+	}
+	return configuration, nil
+}
+
+// parseTraitValue returns booleans, numbers and JSON arrays or objects with their JSON type, anything else as string
+func parseTraitValue(value string) interface{} {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.UseNumber()
+	var parsed interface{}
+	if err := decoder.Decode(&parsed); err != nil || decoder.More() || parsed == nil {
+		return value
+	}
+	if _, ok := parsed.(string); ok {
+		return value
+	}
+	return parsed
+}
+
+// traitProfile returns the trait profile matching the given name ignoring case
+func traitProfile(name string) (camelv1.TraitProfile, error) {
+	var profiles []string
+	for _, profile := range camelv1.AllTraitProfiles {
+		if strings.EqualFold(string(profile), name) {
+			return profile, nil
+		}
+		profiles = append(profiles, string(profile))
+	}
+	return "", fmt.Errorf("unsupported trait profile %q - please use one of %s", name, strings.Join(profiles, ", "))
+}
+
+// kubeIntegrationClient implements the IntegrationClient with the Camel K and Kubernetes clients
+type kubeIntegrationClient struct {
+	camel camelk.Interface
+	kube  kubernetes.Interface
+}
+
+func (c *kubeIntegrationClient) GetIntegration(ctx context.Context, namespace string, name string) (*camelv1.Integration, error) {
+	return c.camel.CamelV1().Integrations(namespace).Get(ctx, name, v1.GetOptions{})
+}
+
+func (c *kubeIntegrationClient) ListIntegrations(ctx context.Context, namespace string) ([]camelv1.Integration, error) {
+	integrations, err := c.camel.CamelV1().Integrations(namespace).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return integrations.Items, nil
+}
+
+func (c *kubeIntegrationClient) ListPods(ctx context.Context, namespace string, selector string) ([]corev1.Pod, error) {
+	pods, err := c.kube.CoreV1().Pods(namespace).List(ctx, v1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+func (c *kubeIntegrationClient) StreamLogs(ctx context.Context, namespace string, pod string, options *corev1.PodLogOptions) (io.ReadCloser, error) {
+	return c.kube.CoreV1().Pods(namespace).GetLogs(pod, options).Stream(ctx)
+}
@@ -0,0 +1,214 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	"github.com/spf13/pflag"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/yaml"
+)
+
+var yamlDocumentSeparator = regexp.MustCompile(`(?m)^---\s*$`)
+
+// KameletFileFlags holding the settings for loading Kamelets from local files instead of the cluster
+type KameletFileFlags struct {
+	// Files holding Kamelet definitions in YAML or JSON format
+	Files []string
+	// CatalogDir is a directory holding Kamelet definitions in YAML or JSON format
+	CatalogDir string
+}
+
+// AddFlags adds the --kamelet-file and --catalog-dir flags to the given flag set
+func (f *KameletFileFlags) AddFlags(flags *pflag.FlagSet) {
+	flags.StringArrayVar(&f.Files, "kamelet-file", nil, "Load the Kamelet definition from the given YAML file instead of the cluster.")
+	flags.StringVar(&f.CatalogDir, "catalog-dir", "", "Load the Kamelet definitions from the YAML files in the given directory instead of the cluster.")
+}
+
+// specified returns true when Kamelets should be loaded from local files
+func (f *KameletFileFlags) specified() bool {
+	return len(f.Files) > 0 || f.CatalogDir != ""
+}
+
+// client returns a client that looks up Kamelets in the local files before asking the cluster. When offline is
+// set no cluster client is created, so only the Kamelets can be read and no bindings can be accessed.
+func (f *KameletFileFlags) client(newKameletClient func() (camelkv1alpha1.CamelV1alpha1Interface, error), offline bool) (camelkv1alpha1.CamelV1alpha1Interface, error) {
+	if !f.specified() {
+		return newKameletClient()
+	}
+
+	kamelets, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+
+	local := &localKameletClient{kamelets: kamelets}
+	if !offline {
+		client, err := newKameletClient()
+		if err != nil {
+			return nil, err
+		}
+		local.CamelV1alpha1Interface = client
+	}
+	return local, nil
+}
+
+// load reads the Kamelets of all given files and the catalog directory
+func (f *KameletFileFlags) load() ([]v1alpha1.Kamelet, error) {
+	var kamelets []v1alpha1.Kamelet
+	for _, file := range f.Files {
+		fileKamelets, err := readKameletFile(file, true)
+		if err != nil {
+			return nil, err
+		}
+		kamelets = append(kamelets, fileKamelets...)
+	}
+
+	if f.CatalogDir != "" {
+		entries, err := os.ReadDir(f.CatalogDir)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read Kamelet catalog %s: %v", f.CatalogDir, err)
+		}
+		for _, entry := range entries {
+			ext := strings.ToLower(filepath.Ext(entry.Name()))
+			if entry.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+				continue
+			}
+			fileKamelets, err := readKameletFile(filepath.Join(f.CatalogDir, entry.Name()), false)
+			if err != nil {
+				return nil, err
+			}
+			kamelets = append(kamelets, fileKamelets...)
+		}
+	}
+	return kamelets, nil
+}
+
+// readKameletFile reads all Kamelets of the given file. Documents of another kind are skipped unless strict is set.
+func readKameletFile(file string, strict bool) ([]v1alpha1.Kamelet, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read Kamelet file %s: %v", file, err)
+	}
+
+	var kamelets []v1alpha1.Kamelet
+	for _, doc := range yamlDocumentSeparator.Split(string(data), -1) {
+		if len(bytes.TrimSpace([]byte(doc))) == 0 {
+			continue
+		}
+		var kamelet v1alpha1.Kamelet
+		if err := yaml.Unmarshal([]byte(doc), &kamelet); err != nil {
+			return nil, fmt.Errorf("unable to read Kamelet file %s: %v", file, err)
+		}
+		if kamelet.Kind != v1alpha1.KameletKind {
+			if strict {
+				return nil, fmt.Errorf("file %s does not hold a Kamelet but %q", file, kamelet.Kind)
+			}
+			continue
+		}
+		kamelets = append(kamelets, kamelet)
+	}
+
+	if strict && len(kamelets) == 0 {
+		return nil, fmt.Errorf("file %s does not hold a Kamelet", file)
+	}
+	return kamelets, nil
+}
+
+// localKameletClient looks up Kamelets in a list of local Kamelets first and falls back to the cluster client
+type localKameletClient struct {
+	camelkv1alpha1.CamelV1alpha1Interface
+	kamelets []v1alpha1.Kamelet
+}
+
+func (c *localKameletClient) Kamelets(namespace string) camelkv1alpha1.KameletInterface {
+	kamelets := &localKamelets{
+		namespace: namespace,
+		kamelets:  c.kamelets,
+	}
+	if c.CamelV1alpha1Interface != nil {
+		kamelets.KameletInterface = c.CamelV1alpha1Interface.Kamelets(namespace)
+	}
+	return kamelets
+}
+
+type localKamelets struct {
+	camelkv1alpha1.KameletInterface
+	namespace string
+	kamelets  []v1alpha1.Kamelet
+}
+
+func (k *localKamelets) Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.Kamelet, error) {
+	for i := range k.kamelets {
+		if k.kamelets[i].Name == name {
+			return k.localCopy(&k.kamelets[i]), nil
+		}
+	}
+	if k.KameletInterface == nil {
+		return nil, k8serrors.NewNotFound(v1alpha1.Resource("kamelets"), name)
+	}
+	return k.KameletInterface.Get(ctx, name, opts)
+}
+
+func (k *localKamelets) List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.KameletList, error) {
+	selector, err := labels.Parse(opts.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &v1alpha1.KameletList{}
+	names := make(map[string]bool)
+	for i := range k.kamelets {
+		if selector.Matches(labels.Set(k.kamelets[i].Labels)) {
+			list.Items = append(list.Items, *k.localCopy(&k.kamelets[i]))
+			names[k.kamelets[i].Name] = true
+		}
+	}
+
+	if k.KameletInterface != nil {
+		clusterList, err := k.KameletInterface.List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, kamelet := range clusterList.Items {
+			if !names[kamelet.Name] {
+				list.Items = append(list.Items, kamelet)
+			}
+		}
+	}
+	return list, nil
+}
+
+// localCopy returns a copy of the local Kamelet that lives in the namespace of the client unless it sets one itself
+func (k *localKamelets) localCopy(kamelet *v1alpha1.Kamelet) *v1alpha1.Kamelet {
+	kamelet = kamelet.DeepCopy()
+	if kamelet.Namespace == "" {
+		kamelet.Namespace = k.namespace
+	}
+	return kamelet
+}
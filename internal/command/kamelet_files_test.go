@@ -0,0 +1,157 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	"github.com/spf13/cobra"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/client-pkg/pkg/commands"
+	"knative.dev/client-pkg/pkg/util"
+
+	"gotest.tools/v3/assert"
+)
+
+const localKamelet = `apiVersion: camel.apache.org/v1alpha1
+kind: Kamelet
+metadata:
+  name: timer-source
+  labels:
+    camel.apache.org/kamelet.type: source
+spec:
+  definition:
+    title: Timer Source
+    description: Produces periodic events
+    required:
+    - message
+    properties:
+      message:
+        type: string
+        description: The message to generate
+      period:
+        type: integer
+        description: The interval between two events
+`
+
+const localSinkKamelet = `apiVersion: camel.apache.org/v1alpha1
+kind: Kamelet
+metadata:
+  name: log-sink
+  labels:
+    camel.apache.org/kamelet.type: sink
+spec:
+  definition:
+    title: Log Sink
+`
+
+func TestReadKameletFileMultipleDocuments(t *testing.T) {
+	file := writeTestFile(t, "kamelets.yaml", localKamelet+"---\n"+localSinkKamelet)
+
+	kamelets, err := readKameletFile(file, true)
+	assert.NilError(t, err)
+	assert.Equal(t, len(kamelets), 2)
+	assert.Equal(t, kamelets[0].Name, "timer-source")
+	assert.Equal(t, kamelets[1].Name, "log-sink")
+}
+
+func TestReadKameletFileErrorCaseNoKamelet(t *testing.T) {
+	file := writeTestFile(t, "config.yaml", "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: foo\n")
+
+	_, err := readKameletFile(file, true)
+	assert.ErrorContains(t, err, "does not hold a Kamelet but \"ConfigMap\"")
+}
+
+func TestKameletFileFlagsCatalogDir(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "timer-source.kamelet.yaml"), []byte(localKamelet), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "log-sink.kamelet.yaml"), []byte(localSinkKamelet), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte("kind: Kustomization\n"), 0o600))
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Kamelets\n"), 0o600))
+
+	flags := KameletFileFlags{CatalogDir: dir}
+	client, err := flags.client(noClusterAccess, true)
+	assert.NilError(t, err)
+
+	kamelet, err := client.Kamelets("current").Get(context.TODO(), "timer-source", v1.GetOptions{})
+	assert.NilError(t, err)
+	assert.Equal(t, kamelet.Namespace, "current")
+
+	sources, err := client.Kamelets("current").List(context.TODO(), v1.ListOptions{LabelSelector: KameletTypeLabel + "=source"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(sources.Items), 1)
+
+	_, err = client.Kamelets("current").Get(context.TODO(), "unknown", v1.GetOptions{})
+	assert.Assert(t, k8serrors.IsNotFound(err))
+}
+
+func TestBindingCreateOffline(t *testing.T) {
+	file := writeTestFile(t, "timer-source.kamelet.yaml", localKamelet)
+
+	output, err := runOfflineCmd(newBindingCreateCommand, "create", "timer-to-broker", "--kamelet", "timer-source", "--kamelet-file", file,
+		"--broker", "default", "--property", "message=hello", "--property", "period=1000", "--dry-run=client")
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "kind: KameletBinding", "name: timer-to-broker", "message: hello", "period: 1000", "kind: Broker"))
+}
+
+func TestBindOfflineErrorCaseMissingProperty(t *testing.T) {
+	dir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "timer-source.kamelet.yaml"), []byte(localKamelet), 0o600))
+
+	_, err := runOfflineCmd(NewBindCommand, "bind", "timer-source", "--catalog-dir", dir, "--broker", "default", "--dry-run=client")
+	assert.Error(t, err, "binding is missing required property \"message\" for Kamelet \"timer-source\"")
+}
+
+func TestBindOfflineErrorCaseVerifySink(t *testing.T) {
+	file := writeTestFile(t, "timer-source.kamelet.yaml", localKamelet)
+
+	_, err := runOfflineCmd(NewBindCommand, "bind", "timer-source", "--kamelet-file", file, "--broker", "default", "--dry-run=client", "--verify-sink")
+	assert.ErrorContains(t, err, "--verify-sink requires cluster access")
+}
+
+func TestDescribeOffline(t *testing.T) {
+	file := writeTestFile(t, "timer-source.kamelet.yaml", localKamelet)
+
+	output, err := runOfflineCmd(NewDescribeCommand, "describe", "timer-source", "--kamelet-file", file)
+	assert.NilError(t, err)
+	assert.Assert(t, util.ContainsAll(output, "timer-source", "Timer Source", "message", "period"))
+}
+
+func noClusterAccess() (camelkv1alpha1.CamelV1alpha1Interface, error) {
+	return nil, errors.New("no cluster access")
+}
+
+// runOfflineCmd runs the given command with a Kamelet client that fails on creation
+func runOfflineCmd(newCommand func(p *KameletPluginParams) *cobra.Command, args ...string) (string, error) {
+	p := KameletPluginParams{
+		KnParams:         &commands.KnParams{},
+		Context:          context.TODO(),
+		NewKameletClient: noClusterAccess,
+	}
+
+	cmd, _, output := commands.CreateSourcesTestKnCommand(newCommand(&p), p.KnParams)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+
+	return output.String(), err
+}
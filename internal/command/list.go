@@ -36,11 +36,15 @@ var listExample = `
   kn source kamelet list
 
   # List available Kamelets in YAML output format
-  kn source kamelet list -o yaml`
+  kn source kamelet list -o yaml
+
+  # List available sink Kamelets
+  kn source kamelet list --type sink`
 
 // NewListCommand implements 'kn-source-kamelet list' command
 func NewListCommand(p *KameletPluginParams) *cobra.Command {
 	kameletListFlags := flags.NewListPrintFlags(ListHandlers)
+	var kameletType string
 
 	cmd := &cobra.Command{
 		Use:     "list",
@@ -58,8 +62,13 @@ func NewListCommand(p *KameletPluginParams) *cobra.Command {
 				return err
 			}
 
+			labelSelector, err := kameletTypeSelector(kameletType)
+			if err != nil {
+				return err
+			}
+
 			filterCriteria := v1.ListOptions{
-				LabelSelector: fmt.Sprintf("%s=%s", KameletTypeLabel, "source"),
+				LabelSelector: labelSelector,
 			}
 
 			kameletList, err := kameletClient.Kamelets(namespace).List(p.Context, filterCriteria)
@@ -80,7 +89,16 @@ func NewListCommand(p *KameletPluginParams) *cobra.Command {
 				kameletListFlags.EnsureWithNamespace()
 			}
 
-			err = kameletListFlags.Print(kameletList, cmd.OutOrStdout())
+			// Kamelets are printed as camel.apache.org/v1 Kamelets when the cluster serves Pipes
+			var obj runtime.Object = kameletList
+			if kameletListFlags.GenericPrintFlags.OutputFlagSpecified() {
+				obj, err = printableObject(kameletClient, kameletList)
+				if err != nil {
+					return err
+				}
+			}
+
+			err = kameletListFlags.Print(obj, cmd.OutOrStdout())
 			if err != nil {
 				return err
 			}
@@ -88,15 +106,28 @@ func NewListCommand(p *KameletPluginParams) *cobra.Command {
 		},
 	}
 	commands.AddNamespaceFlags(cmd.Flags(), true)
+	cmd.Flags().StringVar(&kameletType, "type", kameletTypeSource, "Type of the Kamelets to list. One of: source|sink|action|all.")
 	kameletListFlags.AddFlags(cmd)
 	return cmd
 }
 
+// kameletTypeSelector returns the label selector for listing Kamelets of the given type
+func kameletTypeSelector(kameletType string) (string, error) {
+	switch kameletType {
+	case kameletTypeSource, kameletTypeSink, kameletTypeAction:
+		return fmt.Sprintf("%s=%s", KameletTypeLabel, kameletType), nil
+	case "all":
+		return fmt.Sprintf("%s in (%s,%s,%s)", KameletTypeLabel, kameletTypeSource, kameletTypeSink, kameletTypeAction), nil
+	}
+	return "", fmt.Errorf("unsupported Kamelet type %q - please use one of source, sink, action, all", kameletType)
+}
+
 // ListHandlers handles printing human readable table for `kn-source-kamelet list` command's output
 func ListHandlers(h hprinters.PrintHandler) {
 	kameletColumnDefinitions := []metav1beta1.TableColumnDefinition{
 		{Name: "Namespace", Type: "string", Description: "Namespace of the Kamelet instance", Priority: 0},
 		{Name: "Name", Type: "string", Description: "Name of the Kamelet instance", Priority: 1},
+		{Name: "Type", Type: "string", Description: "Type of the Kamelet instance", Priority: 1},
 		{Name: "Phase", Type: "string", Description: "Phase of the Kamelet instance", Priority: 1},
 		{Name: "Age", Type: "string", Description: "Age of the Kamelet instance", Priority: 1},
 		{Name: "Conditions", Type: "string", Description: "Ready state conditions", Priority: 1},
@@ -141,6 +172,7 @@ func printKamelet(kamelet *camelkv1alpha1.Kamelet, options hprinters.PrintOption
 
 	row.Cells = append(row.Cells,
 		name,
+		extractKameletType(kamelet),
 		phase,
 		age,
 		conditions,
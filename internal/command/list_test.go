@@ -128,6 +128,43 @@ func TestListAllNamespace(t *testing.T) {
 	recorder.Validate()
 }
 
+func TestListSinkType(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	kamelet := createSinkKameletInNamespace("log-sink", "default")
+	recorder.List(&camelkapis.KameletList{Items: []camelkapis.Kamelet{*kamelet}}, nil)
+
+	output, err := runListCmd(mockClient, "--type", "sink")
+	assert.NilError(t, err)
+
+	outputLines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(outputLines[0], "NAME", "TYPE", "PHASE"))
+	assert.Check(t, util.ContainsAll(outputLines[1], "log-sink", "sink", "Ready"))
+
+	recorder.Validate()
+}
+
+func TestListErrorCaseUnsupportedType(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	_, err := runListCmd(mockClient, "--type", "foo")
+	assert.Error(t, err, "unsupported Kamelet type \"foo\" - please use one of source, sink, action, all")
+
+	recorder.Validate()
+}
+
+func TestKameletTypeSelector(t *testing.T) {
+	selector, err := kameletTypeSelector("action")
+	assert.NilError(t, err)
+	assert.Equal(t, selector, "camel.apache.org/kamelet.type=action")
+
+	selector, err = kameletTypeSelector("all")
+	assert.NilError(t, err)
+	assert.Equal(t, selector, "camel.apache.org/kamelet.type in (source,sink,action)")
+}
+
 func runListCmd(c *client.MockClient, options ...string) (string, error) {
 	p := KameletPluginParams{
 		KnParams: &commands.KnParams{},
@@ -0,0 +1,79 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+const (
+	dryRunNone   = "none"
+	dryRunClient = "client"
+	dryRunServer = "server"
+)
+
+// OutputFlags holding the dry run and output format settings of commands applying bindings
+type OutputFlags struct {
+	// DryRun strategy, one of none, client or server
+	DryRun     string
+	PrintFlags *genericclioptions.PrintFlags
+}
+
+// NewOutputFlags creates output flags using the given operation for the name output format
+func NewOutputFlags(operation string) *OutputFlags {
+	return &OutputFlags{
+		PrintFlags: genericclioptions.NewPrintFlags(operation),
+	}
+}
+
+// AddFlags adds the --dry-run and --output flags to the given command
+func (f *OutputFlags) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.DryRun, "dry-run", dryRunNone,
+		`Must be "none", "client" or "server". With "client" the binding is only printed, with "server" it is submitted without being persisted.`)
+	cmd.Flag("dry-run").NoOptDefVal = dryRunClient
+	f.PrintFlags.AddFlags(cmd)
+	cmd.Flag("output").Usage = fmt.Sprintf("Output format. One of: %s.", strings.Join(f.PrintFlags.AllowedFormats(), "|"))
+}
+
+// dryRunStrategy returns the validated dry run strategy, an empty string means no dry run
+func (f *OutputFlags) dryRunStrategy() (string, error) {
+	switch f.DryRun {
+	case "", dryRunNone:
+		return "", nil
+	case dryRunClient, dryRunServer:
+		return f.DryRun, nil
+	}
+	return "", fmt.Errorf("invalid dry run strategy %q - please use one of none, client, server", f.DryRun)
+}
+
+// printer returns the printer for the requested output format. Without output format a client dry run prints YAML
+// and any other invocation returns no printer, so the default status messages are written.
+func (f *OutputFlags) printer(dryRun string) (printers.ResourcePrinter, error) {
+	if !f.PrintFlags.OutputFlagSpecified() {
+		if dryRun != dryRunClient {
+			return nil, nil
+		}
+		output := "yaml"
+		f.PrintFlags.OutputFormat = &output
+	}
+	return f.PrintFlags.ToPrinter()
+}
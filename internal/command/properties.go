@@ -0,0 +1,254 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/magiconair/properties"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientdynamic "knative.dev/client-pkg/pkg/dynamic"
+	knerrors "knative.dev/client-pkg/pkg/errors"
+)
+
+var (
+	secretResource    = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	configMapResource = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+)
+
+// PropertyFlags holding the different ways of providing Kamelet properties
+type PropertyFlags struct {
+	// Properties in the form of "<key>=<value>" or "<key>=@<file>"
+	Properties []string
+	// Files in YAML, JSON or Java properties format holding properties
+	Files []string
+	// Secrets in the form of "<name>[:<key>]" that are referenced via placeholders
+	FromSecret []string
+	// ConfigMaps in the form of "<name>[:<key>]" that are referenced via placeholders
+	FromConfigMap []string
+}
+
+// AddFlags adds the --property, --property-file, --property-from-secret and --property-from-configmap flags
+// to the given flag set
+func (f *PropertyFlags) AddFlags(flags *pflag.FlagSet) {
+	flags.StringArrayVar(&f.Properties, "property", nil, `Add a Kamelet property in the form of "<key>=<value>", use "<key>=@<file>" to read the value from a file`)
+	flags.StringArrayVar(&f.Files, "property-file", nil, "Add the Kamelet properties from a YAML, JSON or .properties file.")
+	flags.StringArrayVar(&f.FromSecret, "property-from-secret", nil, `Reference the Kamelet property "<key>" or all properties of a Secret in the form of "<name>[:<key>]" without reading the value`)
+	flags.StringArrayVar(&f.FromConfigMap, "property-from-configmap", nil, `Reference the Kamelet property "<key>" or all properties of a ConfigMap in the form of "<name>[:<key>]" without reading the value`)
+}
+
+// resolve returns all properties in the form of "<key>=<value>". Properties from files come first, followed by
+// the Secret and ConfigMap references and the properties given on the command line, so later settings win.
+// Secrets and ConfigMaps without a key are read with the given client to look up the keys.
+func (f *PropertyFlags) resolve(ctx context.Context, newDynamicClient func(namespace string) (clientdynamic.KnDynamicClient, error), namespace string) ([]string, error) {
+	var props []string
+
+	for _, file := range f.Files {
+		fileProps, err := readPropertyFile(file)
+		if err != nil {
+			return nil, err
+		}
+		props = append(props, fileProps...)
+	}
+
+	for _, ref := range f.FromSecret {
+		refProps, err := propertyPlaceholders(ctx, newDynamicClient, namespace, "secret", secretResource, ref)
+		if err != nil {
+			return nil, err
+		}
+		props = append(props, refProps...)
+	}
+
+	for _, ref := range f.FromConfigMap {
+		refProps, err := propertyPlaceholders(ctx, newDynamicClient, namespace, "configmap", configMapResource, ref)
+		if err != nil {
+			return nil, err
+		}
+		props = append(props, refProps...)
+	}
+
+	cmdProps, err := readPropertyValueFiles(f.Properties)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(props, cmdProps...), nil
+}
+
+// readPropertyValueFiles replaces values in the form of "@<file>" with the content of the file. A leading "@@"
+// escapes a value that starts with "@". Entries without a value are kept as they are.
+func readPropertyValueFiles(props []string) ([]string, error) {
+	resolved := make([]string, 0, len(props))
+	for _, prop := range props {
+		parts := strings.SplitN(prop, "=", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[1], "@") {
+			resolved = append(resolved, prop)
+			continue
+		}
+
+		if strings.HasPrefix(parts[1], "@@") {
+			resolved = append(resolved, parts[0]+"="+strings.TrimPrefix(parts[1], "@"))
+			continue
+		}
+
+		data, err := os.ReadFile(strings.TrimPrefix(parts[1], "@"))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read value of property %q: %v", parts[0], err)
+		}
+		resolved = append(resolved, parts[0]+"="+strings.TrimSuffix(string(data), "\n"))
+	}
+	return resolved, nil
+}
+
+// readPropertyFile reads the properties of the given file. Files with the .properties extension are read as
+// Java properties, all other files as YAML or JSON map of scalar values. Values are taken as written, the Kamelet
+// schema decides about their type.
+func readPropertyFile(file string) ([]string, error) {
+	values := make(map[string]string)
+
+	if strings.EqualFold(filepath.Ext(file), ".properties") {
+		loader := properties.Loader{
+			Encoding:         properties.UTF8,
+			DisableExpansion: true,
+		}
+		p, err := loader.LoadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read property file %s: %v", file, err)
+		}
+		values = p.Map()
+	} else {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read property file %s: %v", file, err)
+		}
+		var document yaml.Node
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return nil, fmt.Errorf("unable to read property file %s: %v", file, err)
+		}
+		if len(document.Content) > 0 {
+			content := document.Content[0]
+			if content.Kind != yaml.MappingNode {
+				return nil, fmt.Errorf("unable to read property file %s: properties must be a map", file)
+			}
+			for i := 0; i+1 < len(content.Content); i += 2 {
+				key, value := content.Content[i].Value, resolveAlias(content.Content[i+1])
+				if value.Kind != yaml.ScalarNode {
+					return nil, fmt.Errorf("property %q in file %s must be a scalar value", key, file)
+				}
+				values[key] = value.Value
+			}
+		}
+	}
+
+	props := make([]string, 0, len(values))
+	for _, key := range sortedKeys(values) {
+		props = append(props, key+"="+values[key])
+	}
+	return props, nil
+}
+
+// propertyPlaceholders creates property placeholders in the form of "{{<kind>:<name>/<key>}}" for the given
+// reference in the form of "<name>[:<key>]". Without key the resource is read to create a property for each key.
+func propertyPlaceholders(ctx context.Context, newDynamicClient func(namespace string) (clientdynamic.KnDynamicClient, error), namespace string,
+	kind string, resource schema.GroupVersionResource, ref string) ([]string, error) {
+	name, key, hasKey := strings.Cut(ref, ":")
+	if name == "" || (hasKey && key == "") {
+		return nil, fmt.Errorf(`%s reference %q does not follow format "<name>[:<key>]"`, kind, ref)
+	}
+
+	keys := []string{key}
+	if !hasKey {
+		if newDynamicClient == nil {
+			return nil, fmt.Errorf("unable to read %s %q - no client available", kind, name)
+		}
+		client, err := newDynamicClient(namespace)
+		if err != nil {
+			return nil, err
+		}
+		obj, err := client.RawClient().Resource(resource).Namespace(namespace).Get(ctx, name, v1.GetOptions{})
+		if err != nil {
+			return nil, knerrors.GetError(err)
+		}
+
+		keys = nil
+		for _, field := range []string{"data", "binaryData"} {
+			if data, ok := obj.Object[field].(map[string]interface{}); ok {
+				for k := range data {
+					keys = append(keys, k)
+				}
+			}
+		}
+		if len(keys) == 0 {
+			return nil, fmt.Errorf("%s %q does not hold any keys", kind, name)
+		}
+		sort.Strings(keys)
+	}
+
+	props := make([]string, 0, len(keys))
+	for _, k := range keys {
+		props = append(props, fmt.Sprintf("%s={{%s:%s/%s}}", k, kind, name, k))
+	}
+	return props, nil
+}
+
+// PropertyValues holding Kamelet properties as written in a YAML or JSON document. Scalars keep their text, so large
+// numbers keep all digits, 1.10 keeps its trailing zero and on or yes are no booleans. Lists and maps are kept as JSON.
+type PropertyValues map[string]string
+
+// UnmarshalYAML reads the property values of the given mapping node
+func (v *PropertyValues) UnmarshalYAML(node *yaml.Node) error {
+	node = resolveAlias(node)
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: properties must be a map", node.Line)
+	}
+	values := make(PropertyValues, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, resolveAlias(node.Content[i+1])
+		if value.Kind == yaml.ScalarNode {
+			values[key] = value.Value
+			continue
+		}
+		var decoded interface{}
+		if err := value.Decode(&decoded); err != nil {
+			return err
+		}
+		data, err := json.Marshal(decoded)
+		if err != nil {
+			return fmt.Errorf("property %q: %v", key, err)
+		}
+		values[key] = string(data)
+	}
+	*v = values
+	return nil
+}
+
+// resolveAlias returns the node an alias node refers to
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
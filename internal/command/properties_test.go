@@ -0,0 +1,129 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestReadPropertyFileYAML(t *testing.T) {
+	file := writeTestFile(t, "props.yaml", "k1_prop: foo\nk1_optional: true\ncount: 3\n")
+
+	props, err := readPropertyFile(file)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, props, []string{"count=3", "k1_optional=true", "k1_prop=foo"})
+}
+
+func TestReadPropertyFileKeepsValuesAsWritten(t *testing.T) {
+	file := writeTestFile(t, "props.yaml", "offset: 9007199254740993\nenabled: on\nversion: 1.10\nquoted: \"yes\"\n")
+
+	props, err := readPropertyFile(file)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, props, []string{"enabled=on", "offset=9007199254740993", "quoted=yes", "version=1.10"})
+
+	file = writeTestFile(t, "props.json", `{"offset": 9007199254740993, "version": 1.10}`)
+	props, err = readPropertyFile(file)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, props, []string{"offset=9007199254740993", "version=1.10"})
+}
+
+func TestReadPropertyFileProperties(t *testing.T) {
+	file := writeTestFile(t, "props.properties", "# comment\nk1_prop=foo\nk1_optional = ${not.expanded}\n")
+
+	props, err := readPropertyFile(file)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, props, []string{"k1_optional=${not.expanded}", "k1_prop=foo"})
+}
+
+func TestReadPropertyFileErrorCaseNested(t *testing.T) {
+	file := writeTestFile(t, "props.yaml", "k1_prop:\n  nested: foo\n")
+
+	_, err := readPropertyFile(file)
+	assert.Error(t, err, "property \"k1_prop\" in file "+file+" must be a scalar value")
+}
+
+func TestReadPropertyValueFiles(t *testing.T) {
+	file := writeTestFile(t, "token", "my-token\n")
+
+	props, err := readPropertyValueFiles([]string{"token=@" + file, "handle=@@knative", "k1_prop=foo", "removed-"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, props, []string{"token=my-token", "handle=@knative", "k1_prop=foo", "removed-"})
+
+	_, err = readPropertyValueFiles([]string{"token=@" + filepath.Join(t.TempDir(), "missing")})
+	assert.ErrorContains(t, err, "unable to read value of property \"token\"")
+}
+
+func TestPropertyFlagsResolve(t *testing.T) {
+	secret := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]interface{}{
+				"name":      "credentials",
+				"namespace": "current",
+			},
+			"data": map[string]interface{}{
+				"password": "c2VjcmV0",
+				"username": "dXNlcg==",
+			},
+		},
+	}
+
+	flags := PropertyFlags{
+		Properties:    []string{"k1_prop=bar"},
+		Files:         []string{writeTestFile(t, "props.yaml", "k1_prop: foo\n")},
+		FromSecret:    []string{"credentials"},
+		FromConfigMap: []string{"settings:level"},
+	}
+
+	props, err := flags.resolve(context.TODO(), newFakeDynamicClient(secret), "current")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, props, []string{
+		"k1_prop=foo",
+		"password={{secret:credentials/password}}",
+		"username={{secret:credentials/username}}",
+		"level={{configmap:settings/level}}",
+		"k1_prop=bar",
+	})
+}
+
+func TestPropertyFlagsResolveErrorCases(t *testing.T) {
+	flags := PropertyFlags{
+		FromSecret: []string{"credentials:"},
+	}
+	_, err := flags.resolve(context.TODO(), newFakeDynamicClient(), "current")
+	assert.Error(t, err, "secret reference \"credentials:\" does not follow format \"<name>[:<key>]\"")
+
+	flags = PropertyFlags{
+		FromConfigMap: []string{"missing"},
+	}
+	_, err = flags.resolve(context.TODO(), newFakeDynamicClient(), "current")
+	assert.ErrorContains(t, err, "\"missing\" not found")
+}
+
+func writeTestFile(t *testing.T, name string, content string) string {
+	file := filepath.Join(t.TempDir(), name)
+	assert.NilError(t, os.WriteFile(file, []byte(content), 0o600))
+	return file
+}
@@ -0,0 +1,310 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	camelv1 "github.com/apache/camel-k/pkg/apis/camel/v1"
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+)
+
+var (
+	propertyPlaceholder = regexp.MustCompile(`{{.+}}`)
+	hostnameFormat      = regexp.MustCompile(`^[a-zA-Z0-9]([-a-zA-Z0-9]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([-a-zA-Z0-9]{0,61}[a-zA-Z0-9])?)*$`)
+	uuidFormat          = regexp.MustCompile(`(?i)^[0-9a-f]{8}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{12}$`)
+	// jsonNumber matches the number grammar of RFC 8259, which is stricter than the syntax accepted by strconv
+	jsonNumber  = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+	jsonInteger = regexp.MustCompile(`^-?(0|[1-9][0-9]*)$`)
+)
+
+// coerceEndpointProperties converts string property values to the type declared for the property in the
+// Kamelet definition. Values that can not be converted are kept as they are and get reported by the validation.
+func coerceEndpointProperties(kamelet *v1alpha1.Kamelet, props *v1alpha1.EndpointProperties) error {
+	if props == nil || len(props.RawMessage) == 0 || kamelet.Spec.Definition == nil {
+		return nil
+	}
+
+	values, err := decodeEndpointProperties(props)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range values {
+		if schema, ok := kamelet.Spec.Definition.Properties[key]; ok {
+			values[key] = coercePropertyValue(schema, value)
+		}
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	props.RawMessage = camelv1.RawMessage(data)
+	return nil
+}
+
+func coercePropertyValue(schema v1alpha1.JSONSchemaProps, value interface{}) interface{} {
+	s, ok := value.(string)
+	if !ok || propertyPlaceholder.MatchString(s) {
+		return value
+	}
+
+	switch schema.Type {
+	case "integer":
+		if n := strings.TrimSpace(s); jsonInteger.MatchString(n) {
+			if _, err := strconv.ParseInt(n, 10, 64); err == nil {
+				return json.Number(n)
+			}
+		}
+	case "number":
+		if n := strings.TrimSpace(s); jsonNumber.MatchString(n) {
+			if _, err := strconv.ParseFloat(n, 64); err == nil {
+				return json.Number(n)
+			}
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(strings.TrimSpace(s)); err == nil {
+			return b
+		}
+	case "array":
+		var items []interface{}
+		if strings.HasPrefix(strings.TrimSpace(s), "[") {
+			if err := json.Unmarshal([]byte(s), &items); err != nil {
+				return value
+			}
+		} else {
+			for _, item := range strings.Split(s, ",") {
+				items = append(items, strings.TrimSpace(item))
+			}
+		}
+		if schema.Items != nil {
+			for i := range items {
+				items[i] = coercePropertyValue(*schema.Items, items[i])
+			}
+		}
+		return items
+	}
+	return value
+}
+
+// validateProperty checks the given value against the property schema and returns all violations
+func validateProperty(name string, schema v1alpha1.JSONSchemaProps, value interface{}) []string {
+	if s, ok := value.(string); ok && propertyPlaceholder.MatchString(s) {
+		// the value gets resolved at runtime
+		return nil
+	}
+
+	var violations []string
+	violation := func(format string, args ...interface{}) {
+		violations = append(violations, fmt.Sprintf("property %q ", name)+fmt.Sprintf(format, args...))
+	}
+
+	if schema.Type != "" && !hasSchemaType(schema.Type, value) {
+		violation("must be of type %s but is %s", schema.Type, formatPropertyValue(value))
+		return violations
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		allowed := make([]string, 0, len(schema.Enum))
+		for _, e := range schema.Enum {
+			allowed = append(allowed, string(e.RawMessage))
+		}
+		violation("must be one of %s but is %s", strings.Join(allowed, ", "), formatPropertyValue(value))
+	}
+
+	switch v := value.(type) {
+	case string:
+		length := int64(utf8.RuneCountInString(v))
+		if schema.MinLength != nil && length < *schema.MinLength {
+			violation("must have at least %d characters", *schema.MinLength)
+		}
+		if schema.MaxLength != nil && length > *schema.MaxLength {
+			violation("must have at most %d characters", *schema.MaxLength)
+		}
+		if schema.Pattern != "" {
+			if pattern, err := regexp.Compile(schema.Pattern); err == nil && !pattern.MatchString(v) {
+				violation("must match pattern %q", schema.Pattern)
+			}
+		}
+		if schema.Format != "" && !hasFormat(schema.Format, v) {
+			violation("must be a valid %s", schema.Format)
+		}
+	case json.Number, float64:
+		number, _ := new(big.Float).SetString(fmt.Sprint(v))
+		if schema.Minimum != nil {
+			if minimum, ok := new(big.Float).SetString(schema.Minimum.String()); ok && number != nil {
+				if c := number.Cmp(minimum); c < 0 || (c == 0 && schema.ExclusiveMinimum) {
+					violation("must be greater than %s%s", orEqual(!schema.ExclusiveMinimum), schema.Minimum.String())
+				}
+			}
+		}
+		if schema.Maximum != nil {
+			if maximum, ok := new(big.Float).SetString(schema.Maximum.String()); ok && number != nil {
+				if c := number.Cmp(maximum); c > 0 || (c == 0 && schema.ExclusiveMaximum) {
+					violation("must be less than %s%s", orEqual(!schema.ExclusiveMaximum), schema.Maximum.String())
+				}
+			}
+		}
+	case []interface{}:
+		if schema.MinItems != nil && int64(len(v)) < *schema.MinItems {
+			violation("must have at least %d items", *schema.MinItems)
+		}
+		if schema.MaxItems != nil && int64(len(v)) > *schema.MaxItems {
+			violation("must have at most %d items", *schema.MaxItems)
+		}
+		if schema.Items != nil {
+			for i, item := range v {
+				violations = append(violations, validateProperty(fmt.Sprintf("%s[%d]", name, i), *schema.Items, item)...)
+			}
+		}
+	}
+
+	return violations
+}
+
+func hasSchemaType(schemaType string, value interface{}) bool {
+	switch schemaType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "integer":
+		switch v := value.(type) {
+		case json.Number:
+			_, err := v.Int64()
+			return err == nil
+		case float64:
+			return v == float64(int64(v))
+		}
+		return false
+	case "number":
+		switch value.(type) {
+		case json.Number, float64:
+			return true
+		}
+		return false
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	}
+	return true
+}
+
+func inEnum(enum []*v1alpha1.JSON, value interface{}) bool {
+	for _, e := range enum {
+		if e == nil {
+			continue
+		}
+		var allowed interface{}
+		decoder := json.NewDecoder(bytes.NewReader(e.RawMessage))
+		decoder.UseNumber()
+		if err := decoder.Decode(&allowed); err != nil {
+			continue
+		}
+		if reflect.DeepEqual(allowed, value) || fmt.Sprint(allowed) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasFormat(format string, value string) bool {
+	var err error
+	switch format {
+	case "uri":
+		_, err = url.ParseRequestURI(value)
+	case "email":
+		_, err = mail.ParseAddress(value)
+	case "hostname":
+		return len(value) <= 255 && hostnameFormat.MatchString(value)
+	case "ipv4":
+		ip := net.ParseIP(value)
+		return ip != nil && ip.To4() != nil
+	case "ipv6":
+		ip := net.ParseIP(value)
+		return ip != nil && ip.To4() == nil
+	case "cidr":
+		_, _, err = net.ParseCIDR(value)
+	case "mac":
+		_, err = net.ParseMAC(value)
+	case "uuid":
+		return uuidFormat.MatchString(value)
+	case "byte":
+		_, err = base64.StdEncoding.DecodeString(value)
+	case "date":
+		_, err = time.Parse("2006-01-02", value)
+	case "datetime", "date-time":
+		_, err = time.Parse(time.RFC3339, value)
+	case "duration":
+		_, err = time.ParseDuration(value)
+	}
+	// unknown formats are ignored
+	return err == nil
+}
+
+func orEqual(inclusive bool) string {
+	if inclusive {
+		return "or equal to "
+	}
+	return ""
+}
+
+func formatPropertyValue(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return fmt.Sprintf("%q", value)
+	case []interface{}:
+		return "an array"
+	case map[string]interface{}:
+		return "an object"
+	}
+	return fmt.Sprint(value)
+}
+
+// decodeEndpointProperties decodes the endpoint properties keeping numbers in their original representation
+func decodeEndpointProperties(props *v1alpha1.EndpointProperties) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	if props == nil || len(props.RawMessage) == 0 {
+		return values, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(props.RawMessage))
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
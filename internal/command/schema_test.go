@@ -0,0 +1,136 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+
+	"gotest.tools/v3/assert"
+)
+
+func createSchemaKamelet() *v1alpha1.Kamelet {
+	minimum := json.Number("1")
+	maximum := json.Number("10")
+	maxLength := int64(5)
+	minItems := int64(1)
+
+	kamelet := createKamelet("k1")
+	kamelet.Spec.Definition.Properties["count"] = v1alpha1.JSONSchemaProps{Type: "integer", Minimum: &minimum, Maximum: &maximum}
+	kamelet.Spec.Definition.Properties["ratio"] = v1alpha1.JSONSchemaProps{Type: "number"}
+	kamelet.Spec.Definition.Properties["mode"] = v1alpha1.JSONSchemaProps{Type: "string", Enum: []*v1alpha1.JSON{
+		{RawMessage: []byte(`"fast"`)},
+		{RawMessage: []byte(`"slow"`)},
+	}}
+	kamelet.Spec.Definition.Properties["code"] = v1alpha1.JSONSchemaProps{Type: "string", MaxLength: &maxLength, Pattern: "^[A-Z]+$"}
+	kamelet.Spec.Definition.Properties["url"] = v1alpha1.JSONSchemaProps{Type: "string", Format: "uri"}
+	kamelet.Spec.Definition.Properties["ports"] = v1alpha1.JSONSchemaProps{Type: "array", MinItems: &minItems, Items: &v1alpha1.JSONSchemaProps{Type: "integer"}}
+	return kamelet
+}
+
+func TestCoerceEndpointProperties(t *testing.T) {
+	kamelet := createSchemaKamelet()
+	props, err := asEndpointProperties(map[string]string{
+		"k1_prop":     "10",
+		"k1_optional": "true",
+		"count":       "5",
+		"ratio":       "0.5",
+		"ports":       "80, 443",
+		"url":         "{{secret:my-secret/url}}",
+	})
+	assert.NilError(t, err)
+
+	assert.NilError(t, coerceEndpointProperties(kamelet, &props))
+	assert.Equal(t, string(props.RawMessage), `{"count":5,"k1_optional":true,"k1_prop":"10","ports":[80,443],"ratio":0.5,"url":"{{secret:my-secret/url}}"}`)
+	assert.NilError(t, verifyProperties(kamelet, v1alpha1.Endpoint{Properties: &props}))
+}
+
+func TestCoerceEndpointPropertiesJSONArray(t *testing.T) {
+	kamelet := createSchemaKamelet()
+	props, err := asEndpointProperties(map[string]string{"ports": "[8080]"})
+	assert.NilError(t, err)
+
+	assert.NilError(t, coerceEndpointProperties(kamelet, &props))
+	assert.Equal(t, string(props.RawMessage), `{"ports":[8080]}`)
+}
+
+func TestCoerceEndpointPropertiesInvalidNumbers(t *testing.T) {
+	kamelet := createSchemaKamelet()
+	for _, value := range []string{"+5", "Inf", "NaN", "0x1p3", "1_000", "05"} {
+		props, err := asEndpointProperties(map[string]string{"count": value, "ratio": value})
+		assert.NilError(t, err)
+
+		assert.NilError(t, coerceEndpointProperties(kamelet, &props))
+		assert.Equal(t, string(props.RawMessage), fmt.Sprintf(`{"count":%q,"ratio":%q}`, value, value))
+		err = verifyProperties(kamelet, v1alpha1.Endpoint{Properties: &props})
+		assert.ErrorContains(t, err, "property \"count\" must be of type integer")
+		assert.ErrorContains(t, err, "property \"ratio\" must be of type number")
+	}
+}
+
+func TestVerifyPropertiesReportsAllViolations(t *testing.T) {
+	kamelet := createSchemaKamelet()
+	props, err := asEndpointProperties(map[string]string{
+		"k1_optional": "maybe",
+		"count":       "11",
+		"mode":        "medium",
+		"code":        "abcdef",
+		"url":         "not a uri",
+		"ports":       "80,http",
+		"foo":         "bar",
+	})
+	assert.NilError(t, err)
+	assert.NilError(t, coerceEndpointProperties(kamelet, &props))
+
+	err = verifyProperties(kamelet, v1alpha1.Endpoint{Properties: &props})
+	assert.Error(t, err, `binding is missing required property "k1_prop" for Kamelet "k1"
+binding property "code" must have at most 5 characters for Kamelet "k1"
+binding property "code" must match pattern "^[A-Z]+$" for Kamelet "k1"
+binding property "count" must be less than or equal to 10 for Kamelet "k1"
+binding uses unknown property "foo" for Kamelet "k1"
+binding property "k1_optional" must be of type boolean but is "maybe" for Kamelet "k1"
+binding property "mode" must be one of "fast", "slow" but is "medium" for Kamelet "k1"
+binding property "ports[1]" must be of type integer but is "http" for Kamelet "k1"
+binding property "url" must be a valid uri for Kamelet "k1"`)
+}
+
+func TestHasFormat(t *testing.T) {
+	for _, tc := range []struct {
+		format string
+		value  string
+		valid  bool
+	}{
+		{"email", "dev@example.com", true},
+		{"email", "example.com", false},
+		{"hostname", "my-host.example.com", true},
+		{"hostname", "-invalid", false},
+		{"ipv4", "10.0.0.1", true},
+		{"ipv4", "::1", false},
+		{"ipv6", "::1", true},
+		{"uuid", "123e4567-e89b-12d3-a456-426614174000", true},
+		{"uuid", "123", false},
+		{"date-time", "2021-01-02T10:00:00Z", true},
+		{"date", "2021-13-02", false},
+		{"duration", "5s", true},
+		{"unknown", "anything", true},
+	} {
+		assert.Equal(t, hasFormat(tc.format, tc.value), tc.valid, "%s: %s", tc.format, tc.value)
+	}
+}
@@ -0,0 +1,245 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientdynamic "knative.dev/client-pkg/pkg/dynamic"
+	knerrors "knative.dev/client-pkg/pkg/errors"
+)
+
+// errNotAddressable is returned by the sink verification for existing sinks without address
+var errNotAddressable = errors.New("not addressable")
+
+// sinkResolver turns sink expressions into binding sink endpoints. Sink types that are not one of the
+// well known aliases are looked up via API discovery, so any Addressable resource on the cluster can be used.
+type sinkResolver struct {
+	// mapper resolves kinds as well as plural, singular and short resource names, discovery is skipped when nil
+	mapper meta.RESTMapper
+	// newDynamicClient creates the client used to verify the sink
+	newDynamicClient func(namespace string) (clientdynamic.KnDynamicClient, error)
+	// verify that the sink exists and is addressable
+	verify bool
+}
+
+// newSinkResolver creates a sink resolver using the API discovery and dynamic client of the plugin params
+func (params *KameletPluginParams) newSinkResolver(verify bool) (*sinkResolver, error) {
+	resolver := &sinkResolver{
+		verify: verify,
+	}
+
+	if params.NewRESTMapper != nil {
+		mapper, err := params.NewRESTMapper()
+		if err != nil {
+			return nil, err
+		}
+		resolver.mapper = mapper
+	}
+
+	if params.KnParams != nil {
+		resolver.newDynamicClient = params.NewDynamicClient
+	}
+
+	return resolver, nil
+}
+
+// resolve creates the binding sink endpoint for the given sink expression. HTTP and HTTPS URIs
+// result in a URI sink, all other expressions are decoded to an object reference in the given namespace
+// unless the expression specifies a namespace itself.
+func (r *sinkResolver) resolve(ctx context.Context, sink string, namespace string) (v1alpha1.Endpoint, error) {
+	if isURISink(sink) {
+		uri, err := decodeSinkURI(sink)
+		if err != nil {
+			return v1alpha1.Endpoint{}, err
+		}
+		return v1alpha1.Endpoint{
+			URI: &uri,
+		}, nil
+	}
+
+	ref, err := decodeSink(sink)
+	if err != nil {
+		return v1alpha1.Endpoint{}, err
+	}
+	if ref.Namespace == "" {
+		ref.Namespace = namespace
+	}
+
+	if err := r.resolveKind(&ref); err != nil {
+		return v1alpha1.Endpoint{}, err
+	}
+
+	if r.verify {
+		if err := r.verifySink(ctx, ref); err != nil {
+			return v1alpha1.Endpoint{}, err
+		}
+	}
+
+	return v1alpha1.Endpoint{
+		Ref: &ref,
+	}, nil
+}
+
+// resolveKind sets the kind and API version of the given reference. Well known aliases are resolved first,
+// other kinds or resource names are looked up via API discovery. Without discovery only fully qualified
+// references in the form of "<apiVersion>:<kind>:<name>" are supported.
+func (r *sinkResolver) resolveKind(ref *corev1.ObjectReference) error {
+	if sinkType, ok := sinkTypes[ref.Kind]; ok {
+		if sinkType.Kind != "" {
+			ref.Kind = sinkType.Kind
+		}
+		if ref.APIVersion == "" && sinkType.APIVersion != "" {
+			ref.APIVersion = sinkType.APIVersion
+		}
+		return nil
+	}
+
+	if r.mapper == nil {
+		if ref.APIVersion == "" {
+			return fmt.Errorf("unsupported sink type %q", ref.Kind)
+		}
+		return nil
+	}
+
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return err
+	}
+
+	if ref.APIVersion != "" {
+		if mapping, err := r.mapper.RESTMapping(gv.WithKind(ref.Kind).GroupKind(), gv.Version); err == nil {
+			ref.Kind = mapping.GroupVersionKind.Kind
+			return nil
+		}
+	}
+
+	gvk, err := r.mapper.KindFor(gv.WithResource(strings.ToLower(ref.Kind)))
+	if err != nil {
+		return fmt.Errorf("unsupported sink type %q: %v", ref.Kind, err)
+	}
+	ref.Kind = gvk.Kind
+	ref.APIVersion = gvk.GroupVersion().String()
+	return nil
+}
+
+// verifySink makes sure that the referenced sink exists and is addressable. Kubernetes services are
+// always addressable, all other resources need to expose an address URL in their status.
+func (r *sinkResolver) verifySink(ctx context.Context, ref corev1.ObjectReference) error {
+	if r.newDynamicClient == nil {
+		return fmt.Errorf("unable to verify sink %s %q - no client available", ref.Kind, ref.Name)
+	}
+	client, err := r.newDynamicClient(ref.Namespace)
+	if err != nil {
+		return err
+	}
+
+	gvr, err := r.resourceFor(ref)
+	if err != nil {
+		return err
+	}
+
+	obj, err := client.RawClient().Resource(gvr).Namespace(ref.Namespace).Get(ctx, ref.Name, v1.GetOptions{})
+	if err != nil {
+		return knerrors.GetError(err)
+	}
+
+	if gvr.Group == "" && ref.Kind == "Service" {
+		return nil
+	}
+
+	address, _, _ := unstructured.NestedString(obj.Object, "status", "address", "url")
+	if address == "" {
+		return fmt.Errorf("sink %s %q in namespace %q is %w", ref.Kind, ref.Name, ref.Namespace, errNotAddressable)
+	}
+
+	return nil
+}
+
+// resourceFor returns the resource of the given reference. Without discovery the resource name
+// is derived from the kind.
+func (r *sinkResolver) resourceFor(ref corev1.ObjectReference) (schema.GroupVersionResource, error) {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+
+	if r.mapper != nil {
+		mapping, err := r.mapper.RESTMapping(gv.WithKind(ref.Kind).GroupKind(), gv.Version)
+		if err != nil {
+			return schema.GroupVersionResource{}, err
+		}
+		return mapping.Resource, nil
+	}
+
+	resource, _ := meta.UnsafeGuessKindToResource(gv.WithKind(ref.Kind))
+	return resource, nil
+}
+
+func isURISink(sink string) bool {
+	lower := strings.ToLower(sink)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+func decodeSinkURI(sink string) (string, error) {
+	u, err := url.Parse(sink)
+	if err != nil {
+		return "", fmt.Errorf("invalid sink URI %q: %v", sink, err)
+	}
+	if u.Host == "" || u.Hostname() == "" {
+		return "", fmt.Errorf("invalid sink URI %q - missing host", sink)
+	}
+	return u.String(), nil
+}
+
+// decodeSink parses the given sink expression in the form of "[<apiVersion>:]<kind>:[<namespace>/]<name>"
+func decodeSink(sink string) (corev1.ObjectReference, error) {
+	ref := corev1.ObjectReference{}
+
+	if !sinkExpression.MatchString(sink) {
+		return ref, fmt.Errorf("unsupported sink expression %q - please use format <kind>:<name>", sink)
+	}
+
+	groupNames := sinkExpression.SubexpNames()
+	for _, match := range sinkExpression.FindAllStringSubmatch(sink, -1) {
+		for idx, text := range match {
+			groupName := groupNames[idx]
+			switch groupName {
+			case "apiVersion":
+				ref.APIVersion = text
+			case "namespace":
+				ref.Namespace = text
+			case "kind":
+				ref.Kind = text
+			case "name":
+				ref.Name = text
+			}
+		}
+	}
+
+	return ref, nil
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientdynamic "knative.dev/client-pkg/pkg/dynamic"
+	"knative.dev/client-pkg/pkg/dynamic/fake"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSinkResolverAliases(t *testing.T) {
+	resolver := &sinkResolver{}
+
+	endpoint, err := resolver.resolve(context.TODO(), "broker:default", "current")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, endpoint.Ref, &corev1.ObjectReference{
+		Kind:       "Broker",
+		APIVersion: eventingv1.SchemeGroupVersion.String(),
+		Namespace:  "current",
+		Name:       "default",
+	})
+
+	endpoint, err = resolver.resolve(context.TODO(), "ksvc:other/receiver", "current")
+	assert.NilError(t, err)
+	assert.Equal(t, endpoint.Ref.Kind, "Service")
+	assert.Equal(t, endpoint.Ref.APIVersion, "serving.knative.dev/v1")
+	assert.Equal(t, endpoint.Ref.Namespace, "other")
+}
+
+func TestSinkResolverFullyQualified(t *testing.T) {
+	resolver := &sinkResolver{}
+
+	endpoint, err := resolver.resolve(context.TODO(), "messaging.knative.dev/v1beta1:KafkaChannel:kc", "current")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, endpoint.Ref, &corev1.ObjectReference{
+		Kind:       "KafkaChannel",
+		APIVersion: "messaging.knative.dev/v1beta1",
+		Namespace:  "current",
+		Name:       "kc",
+	})
+
+	_, err = resolver.resolve(context.TODO(), "kafkachannel:kc", "current")
+	assert.Error(t, err, "unsupported sink type \"kafkachannel\"")
+}
+
+func TestSinkResolverDiscovery(t *testing.T) {
+	resolver := &sinkResolver{
+		mapper: createRESTMapper(),
+	}
+
+	endpoint, err := resolver.resolve(context.TODO(), "imc:test", "current")
+	assert.NilError(t, err)
+	assert.Equal(t, endpoint.Ref.Kind, "InMemoryChannel")
+	assert.Equal(t, endpoint.Ref.APIVersion, messagingv1.SchemeGroupVersion.String())
+
+	endpoint, err = resolver.resolve(context.TODO(), "kafkachannels:kc", "current")
+	assert.NilError(t, err)
+	assert.Equal(t, endpoint.Ref.Kind, "KafkaChannel")
+	assert.Equal(t, endpoint.Ref.APIVersion, "messaging.knative.dev/v1beta1")
+
+	endpoint, err = resolver.resolve(context.TODO(), "v1:service:svc", "current")
+	assert.NilError(t, err)
+	assert.Equal(t, endpoint.Ref.Kind, "Service")
+	assert.Equal(t, endpoint.Ref.APIVersion, "v1")
+
+	_, err = resolver.resolve(context.TODO(), "foo:test", "current")
+	assert.ErrorContains(t, err, "unsupported sink type \"foo\"")
+}
+
+func TestSinkResolverVerify(t *testing.T) {
+	ready := &eventingv1.Broker{
+		ObjectMeta: v1.ObjectMeta{Name: "ready", Namespace: "current"},
+		Status: eventingv1.BrokerStatus{
+			AddressStatus: duckv1.AddressStatus{
+				Address: &duckv1.Addressable{URL: apis.HTTP("broker-ingress.knative-eventing.svc.cluster.local")},
+			},
+		},
+	}
+	notReady := &eventingv1.Broker{
+		ObjectMeta: v1.ObjectMeta{Name: "not-ready", Namespace: "current"},
+	}
+
+	resolver := &sinkResolver{
+		mapper:           createRESTMapper(),
+		newDynamicClient: newFakeDynamicClient(ready, notReady),
+		verify:           true,
+	}
+
+	_, err := resolver.resolve(context.TODO(), "broker:ready", "current")
+	assert.NilError(t, err)
+
+	_, err = resolver.resolve(context.TODO(), "broker:not-ready", "current")
+	assert.Error(t, err, "sink Broker \"not-ready\" in namespace \"current\" is not addressable")
+
+	_, err = resolver.resolve(context.TODO(), "broker:missing", "current")
+	assert.ErrorContains(t, err, "\"missing\" not found")
+}
+
+func createRESTMapper() meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Service"}, meta.RESTScopeNamespace)
+	mapper.Add(eventingv1.SchemeGroupVersion.WithKind("Broker"), meta.RESTScopeNamespace)
+	mapper.Add(messagingv1.SchemeGroupVersion.WithKind("InMemoryChannel"), meta.RESTScopeNamespace)
+	mapper.Add(schema.GroupVersionKind{Group: "messaging.knative.dev", Version: "v1beta1", Kind: "KafkaChannel"}, meta.RESTScopeNamespace)
+	return shortNameMapper{mapper}
+}
+
+// shortNameMapper resolves short names the same way the discovery based shortcut expander does
+type shortNameMapper struct {
+	meta.RESTMapper
+}
+
+func (m shortNameMapper) KindFor(resource schema.GroupVersionResource) (schema.GroupVersionKind, error) {
+	if resource.Resource == "imc" {
+		resource.Resource = "inmemorychannels"
+	}
+	return m.RESTMapper.KindFor(resource)
+}
+
+func newFakeDynamicClient(objects ...runtime.Object) func(namespace string) (clientdynamic.KnDynamicClient, error) {
+	return func(namespace string) (clientdynamic.KnDynamicClient, error) {
+		return fake.CreateFakeKnDynamicClient(namespace, objects...), nil
+	}
+}
@@ -0,0 +1,195 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	clienteventingv1 "knative.dev/client-pkg/pkg/eventing/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+// bindingTrigger creates the Trigger delivering the events the binding sends to its broker sink to the subscriber.
+// The Trigger filters on the given filters or on the cloud events type of the binding and shares the binding label
+// with the binding.
+func bindingTrigger(ctx context.Context, resolver *sinkResolver, binding *v1alpha1.KameletBinding, subscriber string, filters []string) (*eventingv1.Trigger, error) {
+	broker := binding.Spec.Sink.Ref
+	if !isBrokerRef(broker) {
+		return nil, errors.New("--subscriber requires a broker as binding sink")
+	}
+	if broker.Namespace != binding.Namespace {
+		return nil, fmt.Errorf("--subscriber requires the broker to be in the namespace %q of the binding", binding.Namespace)
+	}
+
+	attributes, err := parseProperties(filters)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %v", err)
+	}
+	if len(attributes) == 0 {
+		props, err := binding.Spec.Sink.Properties.GetPropertyMap()
+		if err != nil {
+			return nil, err
+		}
+		if props[cloudEventsTypeProperty] == "" {
+			return nil, errors.New("--subscriber requires --ce-type or --filter to select the events of the binding")
+		}
+		attributes = map[string]string{"type": props[cloudEventsTypeProperty]}
+	}
+
+	destination, err := subscriberDestination(ctx, resolver, subscriber, binding.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	trigger := clienteventingv1.NewTriggerBuilder(binding.Name).
+		Namespace(binding.Namespace).
+		Broker(broker.Name).
+		Filters(attributes).
+		Subscriber(destination).
+		Build()
+	trigger.Labels = map[string]string{bindingLabel: binding.Name}
+	return trigger, nil
+}
+
+// subscriberDestination resolves the subscriber expression the same way as binding sinks
+func subscriberDestination(ctx context.Context, resolver *sinkResolver, subscriber string, namespace string) (*duckv1.Destination, error) {
+	endpoint, err := resolver.resolve(ctx, subscriber, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if endpoint.URI != nil {
+		uri, err := apis.ParseURL(*endpoint.URI)
+		if err != nil {
+			return nil, err
+		}
+		return &duckv1.Destination{URI: uri}, nil
+	}
+
+	return &duckv1.Destination{
+		Ref: &duckv1.KReference{
+			APIVersion: endpoint.Ref.APIVersion,
+			Kind:       endpoint.Ref.Kind,
+			Name:       endpoint.Ref.Name,
+			Namespace:  endpoint.Ref.Namespace,
+		},
+	}, nil
+}
+
+// checkBindingTrigger makes sure an existing Trigger of the same name belongs to the binding of the given one
+func checkBindingTrigger(client clienteventingv1.KnEventingClient, ctx context.Context, trigger *eventingv1.Trigger) error {
+	existing, err := client.GetTrigger(ctx, trigger.Name)
+	switch {
+	case k8serrors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return err
+	case existing.Labels[bindingLabel] != trigger.Labels[bindingLabel]:
+		return fmt.Errorf("trigger %q already exists and does not belong to kamelet binding %q", trigger.Name, trigger.Labels[bindingLabel])
+	}
+	return nil
+}
+
+// createBindingTrigger creates the Trigger, a Trigger of the same name created before for the binding is replaced
+func createBindingTrigger(client clienteventingv1.KnEventingClient, ctx context.Context, trigger *eventingv1.Trigger, cmdOut io.Writer) error {
+	existing, err := client.GetTrigger(ctx, trigger.Name)
+	switch {
+	case err == nil:
+		if existing.Labels[bindingLabel] != trigger.Labels[bindingLabel] {
+			return fmt.Errorf("trigger %q already exists and does not belong to kamelet binding %q", trigger.Name, trigger.Labels[bindingLabel])
+		}
+		trigger.ResourceVersion = existing.ResourceVersion
+		if err := client.UpdateTrigger(ctx, trigger); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmdOut, "trigger %q updated\n", trigger.Name)
+		return nil
+	case !k8serrors.IsNotFound(err):
+		return err
+	}
+
+	if err := client.CreateTrigger(ctx, trigger); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(cmdOut, "trigger %q created\n", trigger.Name)
+	return nil
+}
+
+// bindingTriggerOf returns the Trigger created for the binding with --subscriber, nil when the binding has none
+func bindingTriggerOf(client clienteventingv1.KnEventingClient, ctx context.Context, binding *v1alpha1.KameletBinding) (*eventingv1.Trigger, error) {
+	trigger, err := client.GetTrigger(ctx, binding.Name)
+	if k8serrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if trigger.Labels[bindingLabel] != binding.Name {
+		return nil, nil
+	}
+	return trigger, nil
+}
+
+// subscriberExpression returns the subscriber of the Trigger as accepted by --subscriber
+func subscriberExpression(trigger *eventingv1.Trigger, namespace string) (string, error) {
+	subscriber := trigger.Spec.Subscriber
+	endpoint := v1alpha1.Endpoint{}
+	if subscriber.URI != nil {
+		uri := subscriber.URI.String()
+		endpoint.URI = &uri
+	}
+	if subscriber.Ref != nil {
+		endpoint.Ref = &corev1.ObjectReference{
+			APIVersion: subscriber.Ref.APIVersion,
+			Kind:       subscriber.Ref.Kind,
+			Name:       subscriber.Ref.Name,
+			Namespace:  subscriber.Ref.Namespace,
+		}
+	}
+	return endpointExpression(endpoint, namespace)
+}
+
+// deleteBindingTriggers deletes the Triggers labelled with the name of the given binding
+func deleteBindingTriggers(client clienteventingv1.KnEventingClient, ctx context.Context, binding *v1alpha1.KameletBinding, cmdOut io.Writer) error {
+	triggers, err := client.ListTriggers(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, trigger := range triggers.Items {
+		if trigger.Labels[bindingLabel] != binding.Name {
+			continue
+		}
+		err := client.DeleteTrigger(ctx, trigger.Name)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			_, _ = fmt.Fprintf(cmdOut, "trigger %q deleted\n", trigger.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clienteventingv1 "knative.dev/client-pkg/pkg/eventing/v1"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCreateBindingTriggerUpdatesExisting(t *testing.T) {
+	client := newFakeTriggerClient(&eventingv1.Trigger{
+		ObjectMeta: v1.ObjectMeta{Name: "k1-to-broker", ResourceVersion: "1", Labels: map[string]string{bindingLabel: "k1-to-broker"}},
+	})
+
+	trigger := clienteventingv1.NewTriggerBuilder("k1-to-broker").Broker("default").Filters(map[string]string{"type": "org.example.order"}).Build()
+	trigger.Labels = map[string]string{bindingLabel: "k1-to-broker"}
+	out := &bytes.Buffer{}
+	err := createBindingTrigger(client, context.TODO(), trigger, out)
+	assert.NilError(t, err)
+	assert.Equal(t, out.String(), "trigger \"k1-to-broker\" updated\n")
+	assert.Equal(t, client.triggers["k1-to-broker"].ResourceVersion, "1")
+	assert.Equal(t, client.triggers["k1-to-broker"].Spec.Broker, "default")
+}
+
+func TestCreateBindingTriggerErrorCaseForeignTrigger(t *testing.T) {
+	client := newFakeTriggerClient(&eventingv1.Trigger{ObjectMeta: v1.ObjectMeta{Name: "k1-to-broker"}})
+
+	trigger := clienteventingv1.NewTriggerBuilder("k1-to-broker").Build()
+	trigger.Labels = map[string]string{bindingLabel: "k1-to-broker"}
+	err := createBindingTrigger(client, context.TODO(), trigger, &bytes.Buffer{})
+	assert.Error(t, err, "trigger \"k1-to-broker\" already exists and does not belong to kamelet binding \"k1-to-broker\"")
+}
+
+// fakeTriggerClient keeps the triggers in memory, all other eventing operations are not supported
+type fakeTriggerClient struct {
+	clienteventingv1.KnEventingClient
+	triggers map[string]*eventingv1.Trigger
+	// createErr is returned when a Trigger gets created
+	createErr error
+}
+
+func newFakeTriggerClient(triggers ...*eventingv1.Trigger) *fakeTriggerClient {
+	client := &fakeTriggerClient{triggers: make(map[string]*eventingv1.Trigger)}
+	for _, trigger := range triggers {
+		client.triggers[trigger.Name] = trigger
+	}
+	return client
+}
+
+func (c *fakeTriggerClient) GetTrigger(ctx context.Context, name string) (*eventingv1.Trigger, error) {
+	trigger, ok := c.triggers[name]
+	if !ok {
+		return nil, k8serrors.NewNotFound(eventingv1.Resource("triggers"), name)
+	}
+	return trigger, nil
+}
+
+func (c *fakeTriggerClient) ListTriggers(ctx context.Context) (*eventingv1.TriggerList, error) {
+	list := &eventingv1.TriggerList{}
+	for _, trigger := range c.triggers {
+		list.Items = append(list.Items, *trigger)
+	}
+	return list, nil
+}
+
+func (c *fakeTriggerClient) CreateTrigger(ctx context.Context, trigger *eventingv1.Trigger) error {
+	if c.createErr != nil {
+		return c.createErr
+	}
+	if _, ok := c.triggers[trigger.Name]; ok {
+		return k8serrors.NewAlreadyExists(eventingv1.Resource("triggers"), trigger.Name)
+	}
+	c.triggers[trigger.Name] = trigger
+	return nil
+}
+
+func (c *fakeTriggerClient) UpdateTrigger(ctx context.Context, trigger *eventingv1.Trigger) error {
+	if _, ok := c.triggers[trigger.Name]; !ok {
+		return k8serrors.NewNotFound(eventingv1.Resource("triggers"), trigger.Name)
+	}
+	c.triggers[trigger.Name] = trigger
+	return nil
+}
+
+func (c *fakeTriggerClient) DeleteTrigger(ctx context.Context, name string) error {
+	if _, ok := c.triggers[name]; !ok {
+		return k8serrors.NewNotFound(eventingv1.Resource("triggers"), name)
+	}
+	delete(c.triggers, name)
+	return nil
+}
@@ -21,6 +21,13 @@ import (
 	"io"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
 	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
 	messagingv1 "knative.dev/eventing/pkg/apis/messaging/v1"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
@@ -28,6 +35,8 @@ import (
 	camelk "github.com/apache/camel-k/pkg/client/camel/clientset/versioned"
 	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
 	"knative.dev/client-pkg/pkg/commands"
+	clienteventingv1 "knative.dev/client-pkg/pkg/eventing/v1"
+	clienteventingv1beta1 "knative.dev/client-pkg/pkg/eventing/v1beta1"
 )
 
 const (
@@ -36,6 +45,18 @@ const (
 	KameletProviderAnnotation     = "camel.apache.org/provider"
 )
 
+const (
+	kameletTypeSource = "source"
+	kameletTypeSink   = "sink"
+	kameletTypeAction = "action"
+)
+
+const (
+	cloudEventsSpecVersionProperty = "cloudEventsSpecVersion"
+	cloudEventsTypeProperty        = "cloudEventsType"
+	cloudEventsOverridePrefix      = "ce.override."
+)
+
 var (
 	sinkTypes = map[string]corev1.ObjectReference{
 		"channel": {
@@ -59,6 +80,14 @@ type KameletPluginParams struct {
 	Context          context.Context
 	ContextCancel    context.CancelFunc
 	NewKameletClient func() (camelkv1alpha1.CamelV1alpha1Interface, error)
+	// NewCamelClient creates a client for the given Camel K API version regardless of the version in use
+	NewCamelClient       func(apiVersion string) (camelkv1alpha1.CamelV1alpha1Interface, error)
+	NewRESTMapper        func() (meta.RESTMapper, error)
+	NewIntegrationClient func() (IntegrationClient, error)
+	// NewServerResources creates the discovery client telling which Camel K resources the cluster serves
+	NewServerResources func() (discovery.ServerResourcesInterface, error)
+	// APIVersion of the Camel K resources, one of v1alpha1 or v1. Detected from the cluster when empty.
+	APIVersion string
 }
 
 func (params *KameletPluginParams) Initialize() {
@@ -70,26 +99,112 @@ func (params *KameletPluginParams) Initialize() {
 	if params.NewKameletClient == nil {
 		params.NewKameletClient = params.newKameletClient
 	}
+
+	if params.NewCamelClient == nil {
+		params.NewCamelClient = params.newCamelClient
+	}
+
+	if params.NewRESTMapper == nil {
+		params.NewRESTMapper = params.newRESTMapper
+	}
+
+	if params.NewIntegrationClient == nil {
+		params.NewIntegrationClient = params.newIntegrationClient
+	}
+
+	if params.NewServerResources == nil {
+		params.NewServerResources = params.newServerResources
+	}
 }
 
 func (params *KameletPluginParams) newKameletClient() (camelkv1alpha1.CamelV1alpha1Interface, error) {
+	discoveryClient, err := params.NewServerResources()
+	if err != nil {
+		return nil, err
+	}
+
+	apiVersion, err := camelAPIVersion(params.APIVersion, discoveryClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return params.NewCamelClient(apiVersion)
+}
+
+func (params *KameletPluginParams) newServerResources() (discovery.ServerResourcesInterface, error) {
+	restConfig, err := params.RestConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return discovery.NewDiscoveryClientForConfig(restConfig)
+}
+
+func (params *KameletPluginParams) newCamelClient(apiVersion string) (camelkv1alpha1.CamelV1alpha1Interface, error) {
 	restConfig, err := params.RestConfig()
 	if err != nil {
 		return nil, err
 	}
 
+	if apiVersion == camelAPIVersionV1 {
+		dynamicClient, err := dynamic.NewForConfig(restConfig)
+		if err != nil {
+			return nil, err
+		}
+		return newCamelV1Client(dynamicClient), nil
+	}
+
 	client, err := camelk.NewForConfig(restConfig)
 	if err != nil {
 		return nil, err
 	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCamelV1alpha1Client(client.CamelV1alpha1(), dynamicClient), nil
+}
+
+func (params *KameletPluginParams) newIntegrationClient() (IntegrationClient, error) {
+	restConfig, err := params.RestConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	camelClient, err := camelk.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
 
-	return client.CamelV1alpha1(), nil
+	kubeClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kubeIntegrationClient{camel: camelClient, kube: kubeClient}, nil
+}
+
+func (params *KameletPluginParams) newRESTMapper() (meta.RESTMapper, error) {
+	restConfig, err := params.RestConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	cachedClient := memory.NewMemCacheClient(discoveryClient)
+	return restmapper.NewShortcutExpander(restmapper.NewDeferredDiscoveryRESTMapper(cachedClient), cachedClient, nil), nil
 }
 
 // CreateBindingOptions holding settings and options on the create binding command
 type CreateBindingOptions struct {
 	Name                   string
 	Source                 string
+	BindingSource          string
 	SourceProperties       []string
 	CloudEventsOverride    []string
 	CloudEventsSpecVersion string
@@ -99,5 +214,36 @@ type CreateBindingOptions struct {
 	Channel                string
 	Service                string
 	Force                  bool
+	Wait                   bool
+	WaitTimeout            int
+	DryRun                 string
+	Printer                printers.ResourcePrinter
+	Pipes                  bool
+	Integration            *IntegrationFlags
+	ErrorHandler           *ErrorHandlerFlags
+	EventTypes             *EventTypeFlags
+	Steps                  *StepFlags
+	RegisterEventType      bool
+	NewEventTypeClient     func(namespace string) (clienteventingv1beta1.KnEventingV1Beta1Client, error)
+	Subscriber             string
+	Filters                []string
+	NewTriggerClient       func(namespace string) (clienteventingv1.KnEventingClient, error)
+	CmdOut                 io.Writer
+}
+
+// UpdateBindingOptions holding settings and options on the update binding command
+type UpdateBindingOptions struct {
+	Name                   string
+	SourceProperties       []string
+	CloudEventsOverride    []string
+	CloudEventsSpecVersion string
+	CloudEventsType        string
+	Sink                   string
+	Broker                 string
+	Channel                string
+	Service                string
+	Wait                   bool
+	WaitTimeout            int
+	Integration            *IntegrationFlags
 	CmdOut                 io.Writer
 }
@@ -0,0 +1,187 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1beta1 "k8s.io/apimachinery/pkg/apis/meta/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/client-pkg/pkg/commands"
+	knerrors "knative.dev/client-pkg/pkg/errors"
+	hprinters "knative.dev/client-pkg/pkg/printers"
+)
+
+var usageExample = `
+  # Count the bindings using each Kamelet
+  kn source kamelet usage
+
+  # Count the bindings using the given Kamelet
+  kn source kamelet usage NAME
+
+  # Count the bindings using the Kamelets of all namespaces
+  kn source kamelet usage --all-namespaces`
+
+// NewUsageCommand implements 'kn-source-kamelet usage' command
+func NewUsageCommand(p *KameletPluginParams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "usage [NAME]",
+		Short:   "Show the number of Kamelet bindings using Kamelets",
+		Example: usageExample,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			if len(args) > 1 {
+				return errors.New("'kn source kamelet usage' accepts at most one Kamelet name as argument")
+			}
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			if namespace == "" && len(args) == 1 {
+				return errors.New("'kn source kamelet usage' can not be used with a Kamelet name and --all-namespaces")
+			}
+
+			client, err := p.NewKameletClient()
+			if err != nil {
+				return err
+			}
+
+			kamelets := &v1alpha1.KameletList{}
+			if len(args) == 1 {
+				kamelet, err := client.Kamelets(namespace).Get(p.Context, args[0], v1.GetOptions{})
+				if err != nil {
+					return knerrors.GetError(err)
+				}
+				kamelets.Items = append(kamelets.Items, *kamelet)
+			} else {
+				kamelets, err = client.Kamelets(namespace).List(p.Context, v1.ListOptions{})
+				if err != nil {
+					return knerrors.GetError(err)
+				}
+			}
+			if len(kamelets.Items) == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "No resources found.\n")
+				return nil
+			}
+
+			bindings, err := client.KameletBindings(namespace).List(p.Context, v1.ListOptions{})
+			if err != nil {
+				return knerrors.GetError(err)
+			}
+
+			printer := hprinters.NewTablePrinter(hprinters.PrintOptions{AllNamespaces: namespace == ""})
+			usageHandlers(printer, bindings.Items)
+			return printer.PrintObj(kamelets, cmd.OutOrStdout())
+		},
+	}
+	commands.AddNamespaceFlags(cmd.Flags(), true)
+	return cmd
+}
+
+// usageHandlers handles printing the table counting the given bindings per Kamelet
+func usageHandlers(h hprinters.PrintHandler, bindings []v1alpha1.KameletBinding) {
+	columnDefinitions := []metav1beta1.TableColumnDefinition{
+		{Name: "Namespace", Type: "string", Description: "Namespace of the Kamelet instance", Priority: 0},
+		{Name: "Name", Type: "string", Description: "Name of the Kamelet instance", Priority: 1},
+		{Name: "Type", Type: "string", Description: "Type of the Kamelet instance", Priority: 1},
+		{Name: "Bindings", Type: "string", Description: "Number of Kamelet bindings using the Kamelet", Priority: 1},
+		{Name: "Ready", Type: "string", Description: "Number of ready Kamelet bindings using the Kamelet", Priority: 1},
+	}
+	h.TableHandler(columnDefinitions, func(kameletList *v1alpha1.KameletList, options hprinters.PrintOptions) ([]metav1beta1.TableRow, error) {
+		rows := make([]metav1beta1.TableRow, 0, len(kameletList.Items))
+		for i := range kameletList.Items {
+			rows = append(rows, kameletUsageRow(&kameletList.Items[i], bindings, options))
+		}
+		return rows, nil
+	})
+}
+
+// kameletUsageRow populates the table row counting the bindings and the ready bindings using the Kamelet
+func kameletUsageRow(kamelet *v1alpha1.Kamelet, bindings []v1alpha1.KameletBinding, options hprinters.PrintOptions) metav1beta1.TableRow {
+	used := kameletBindings(kamelet, bindings)
+	ready := 0
+	for i := range used {
+		if bindingReadyCondition(used[i].Status.Conditions) == string(corev1.ConditionTrue) {
+			ready++
+		}
+	}
+
+	row := metav1beta1.TableRow{
+		Object: runtime.RawExtension{Object: kamelet},
+	}
+	if options.AllNamespaces {
+		row.Cells = append(row.Cells, kamelet.Namespace)
+	}
+	row.Cells = append(row.Cells,
+		kamelet.Name,
+		extractKameletType(kamelet),
+		strconv.Itoa(len(used)),
+		strconv.Itoa(ready))
+	return row
+}
+
+// listKameletBindings lists the bindings using the Kamelet, in the namespace of the Kamelet or in all namespaces
+func listKameletBindings(client camelkv1alpha1.CamelV1alpha1Interface, ctx context.Context, kamelet *v1alpha1.Kamelet, allNamespaces bool) ([]v1alpha1.KameletBinding, error) {
+	namespace := kamelet.Namespace
+	if allNamespaces {
+		namespace = ""
+	}
+	bindings, err := client.KameletBindings(namespace).List(ctx, v1.ListOptions{})
+	if err != nil {
+		return nil, knerrors.GetError(err)
+	}
+	return kameletBindings(kamelet, bindings.Items), nil
+}
+
+// kameletBindings returns the bindings with a source or sink referencing the Kamelet sorted by namespace and name
+func kameletBindings(kamelet *v1alpha1.Kamelet, bindings []v1alpha1.KameletBinding) []v1alpha1.KameletBinding {
+	var used []v1alpha1.KameletBinding
+	for _, binding := range bindings {
+		if referencesKamelet(binding.Spec.Source, binding.Namespace, kamelet) || referencesKamelet(binding.Spec.Sink, binding.Namespace, kamelet) {
+			used = append(used, binding)
+		}
+	}
+	sort.Slice(used, func(i, j int) bool {
+		if used[i].Namespace != used[j].Namespace {
+			return used[i].Namespace < used[j].Namespace
+		}
+		return used[i].Name < used[j].Name
+	})
+	return used
+}
+
+// referencesKamelet returns true when the endpoint references the Kamelet, references without namespace point to
+// the namespace of the binding
+func referencesKamelet(endpoint v1alpha1.Endpoint, bindingNamespace string, kamelet *v1alpha1.Kamelet) bool {
+	if !isKameletEndpoint(endpoint) || endpoint.Ref.Name != kamelet.Name {
+		return false
+	}
+	namespace := endpoint.Ref.Namespace
+	if namespace == "" {
+		namespace = bindingNamespace
+	}
+	return namespace == kamelet.Namespace
+}
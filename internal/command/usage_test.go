@@ -0,0 +1,162 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	"knative.dev/client-pkg/pkg/commands"
+	"knative.dev/client-pkg/pkg/util"
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestUsageSetup(t *testing.T) {
+	p := KameletPluginParams{
+		Context: context.TODO(),
+	}
+
+	usageCmd := NewUsageCommand(&p)
+	assert.Equal(t, usageCmd.Use, "usage [NAME]")
+	assert.Equal(t, usageCmd.Short, "Show the number of Kamelet bindings using Kamelets")
+	assert.Assert(t, usageCmd.RunE != nil)
+}
+
+func TestUsageErrorCaseTooManyArguments(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	_, err := runUsageCmd(mockClient, "k1", "k2")
+	assert.Error(t, err, "'kn source kamelet usage' accepts at most one Kamelet name as argument")
+	recorder.Validate()
+}
+
+func TestUsageErrorCaseNotFound(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(nil, errors.New("not found"))
+
+	_, err := runUsageCmd(mockClient, "k1")
+	assert.Error(t, err, "not found")
+	recorder.Validate()
+}
+
+func TestUsage(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.List(&v1alpha1.KameletList{Items: []v1alpha1.Kamelet{
+		*createKameletInNamespace("k1", "current"),
+		*createKameletInNamespace("k2", "current"),
+		*createSinkKameletInNamespace("log-sink", "current"),
+	}}, nil)
+
+	ready := createKameletBindingInNamespace("k1-to-channel", "k1", "current", channelRef("current"))
+	ready.Status.Conditions = []v1alpha1.KameletBindingCondition{{Type: v1alpha1.KameletBindingConditionReady, Status: "True"}}
+	notReady := createKameletBindingInNamespace("k1-to-broker", "k1", "current", brokerRef("current"))
+	recorder.ListBindings(&v1alpha1.KameletBindingList{Items: []v1alpha1.KameletBinding{*ready, *notReady}}, nil)
+
+	output, err := runUsageCmd(mockClient)
+	assert.NilError(t, err)
+
+	outputLines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(outputLines[0], "NAME", "TYPE", "BINDINGS", "READY"))
+	assert.Check(t, util.ContainsAll(outputLines[1], "k1", "source", "2", "1"))
+	assert.Check(t, util.ContainsAll(outputLines[2], "k2", "source", "0", "0"))
+	assert.Check(t, util.ContainsAll(outputLines[3], "log-sink", "sink", "0", "0"))
+	assert.Check(t, !strings.Contains(outputLines[0], "NAMESPACE"))
+
+	recorder.Validate()
+}
+
+func TestUsageSingleKamelet(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createSinkKameletInNamespace("log-sink", "current"), nil)
+
+	// a binding using the sink Kamelet in its sink endpoint
+	binding := createKameletBindingInNamespace("channel-to-log-sink", "log-sink", "current", channelRef("current"))
+	binding.Spec.Source, binding.Spec.Sink = binding.Spec.Sink, binding.Spec.Source
+	recorder.ListBindings(&v1alpha1.KameletBindingList{Items: []v1alpha1.KameletBinding{*binding}}, nil)
+
+	output, err := runUsageCmd(mockClient, "log-sink")
+	assert.NilError(t, err)
+
+	outputLines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(outputLines[1], "log-sink", "sink", "1", "0"))
+
+	recorder.Validate()
+}
+
+func TestUsageAllNamespaces(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.List(&v1alpha1.KameletList{Items: []v1alpha1.Kamelet{
+		*createKameletInNamespace("k1", "current"),
+		*createKameletInNamespace("k1", "other"),
+	}}, nil)
+	recorder.ListBindings(&v1alpha1.KameletBindingList{Items: []v1alpha1.KameletBinding{
+		*createKameletBindingInNamespace("k1-to-channel", "k1", "other", channelRef("other")),
+	}}, nil)
+
+	output, err := runUsageCmd(mockClient, "--all-namespaces")
+	assert.NilError(t, err)
+
+	outputLines := strings.Split(output, "\n")
+	assert.Check(t, util.ContainsAll(outputLines[0], "NAMESPACE", "NAME", "TYPE", "BINDINGS", "READY"))
+	assert.Check(t, util.ContainsAll(outputLines[1], "current", "k1", "source", "0", "0"))
+	assert.Check(t, util.ContainsAll(outputLines[2], "other", "k1", "source", "1", "0"))
+
+	recorder.Validate()
+}
+
+func TestUsageErrorCaseNameWithAllNamespaces(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	_, err := runUsageCmd(mockClient, "k1", "--all-namespaces")
+	assert.Error(t, err, "'kn source kamelet usage' can not be used with a Kamelet name and --all-namespaces")
+	recorder.Validate()
+}
+
+func runUsageCmd(c *client.MockClient, options ...string) (string, error) {
+	p := KameletPluginParams{
+		KnParams: &commands.KnParams{},
+		Context:  context.TODO(),
+		NewKameletClient: func() (camelkv1alpha1.CamelV1alpha1Interface, error) {
+			return c, nil
+		},
+	}
+
+	usageCmd, _, output := commands.CreateSourcesTestKnCommand(NewUsageCommand(&p), p.KnParams)
+
+	args := []string{"usage"}
+	args = append(args, options...)
+	usageCmd.SetArgs(args)
+	err := usageCmd.Execute()
+
+	return output.String(), err
+}
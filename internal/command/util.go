@@ -33,7 +33,23 @@ var (
 )
 
 func isEventSourceType(kamelet *v1alpha1.Kamelet) bool {
-	return kamelet.Labels[KameletTypeLabel] == "source"
+	return extractKameletType(kamelet) == kameletTypeSource
+}
+
+func isEventSinkType(kamelet *v1alpha1.Kamelet) bool {
+	return extractKameletType(kamelet) == kameletTypeSink
+}
+
+func isActionType(kamelet *v1alpha1.Kamelet) bool {
+	return extractKameletType(kamelet) == kameletTypeAction
+}
+
+func extractKameletType(kamelet *v1alpha1.Kamelet) string {
+	return kamelet.Labels[KameletTypeLabel]
+}
+
+func isKameletEndpoint(endpoint v1alpha1.Endpoint) bool {
+	return endpoint.Ref != nil && endpoint.Ref.Kind == v1alpha1.KameletKind
 }
 
 func extractKameletProvider(kamelet *v1alpha1.Kamelet) string {
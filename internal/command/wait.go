@@ -0,0 +1,130 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	"github.com/spf13/pflag"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	knflags "knative.dev/client-pkg/pkg/flags"
+	"knative.dev/client-pkg/pkg/wait"
+)
+
+// WaitFlags holding settings on waiting for a binding to become ready
+type WaitFlags struct {
+	// Wait for the binding to become ready
+	Wait bool
+	// Timeout in seconds for how long to wait for the binding to become ready
+	TimeoutInSeconds int
+}
+
+// AddFlags adds the --wait, --no-wait and --wait-timeout flags to the given flag set
+func (w *WaitFlags) AddFlags(flags *pflag.FlagSet) {
+	knflags.AddBothBoolFlags(flags, &w.Wait, "wait", "", true, "Wait for the binding to become ready.")
+	flags.IntVar(&w.TimeoutInSeconds, "wait-timeout", 60, "Seconds to wait before giving up on waiting for the binding to become ready.")
+}
+
+// waitForBindingReady watches the binding until it reaches the ready or error phase. Phase transitions are written
+// to the given output. An error is returned when the binding goes into error phase or the timeout is reached.
+// The watch starts after the given resource version returned by the create or update of the binding, so the phase
+// the binding had before the change is not taken for the result of the change.
+func waitForBindingReady(client camelkv1alpha1.CamelV1alpha1Interface, ctx context.Context, namespace string, name string, resourceVersion string, timeoutInSeconds int, out io.Writer) error {
+	timeout := time.Duration(timeoutInSeconds) * time.Second
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	_, _ = fmt.Fprintf(out, "Waiting for kamelet binding %q to become ready:\n", name)
+	msgCallback := wait.SimpleMessageCallback(out)
+	start := time.Now()
+	reason := ""
+	lastResourceVersion := resourceVersion
+
+	for {
+		watcher, err := client.KameletBindings(namespace).Watch(ctx, v1.ListOptions{
+			FieldSelector:   fields.OneTermEqualSelector("metadata.name", name).String(),
+			ResourceVersion: lastResourceVersion,
+		})
+		if err != nil {
+			return err
+		}
+
+		done, err := func() (bool, error) {
+			defer watcher.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return true, ctx.Err()
+				case <-timer.C:
+					if reason != "" {
+						return true, fmt.Errorf("timeout: kamelet binding %q not ready after %d seconds: %s", name, timeoutInSeconds, reason)
+					}
+					return true, fmt.Errorf("timeout: kamelet binding %q not ready after %d seconds", name, timeoutInSeconds)
+				case event, ok := <-watcher.ResultChan():
+					if !ok {
+						// watch has been closed by the server, start a new one
+						return false, nil
+					}
+					if event.Type != watch.Added && event.Type != watch.Modified {
+						continue
+					}
+					binding, ok := event.Object.(*v1alpha1.KameletBinding)
+					if !ok {
+						continue
+					}
+					if resourceVersion != "" && binding.ResourceVersion == resourceVersion {
+						// still the binding as written, its status is not yet updated by the operator
+						continue
+					}
+					lastResourceVersion = binding.ResourceVersion
+
+					reason = bindingNonReadyConditionReason(binding.Status.Conditions)
+					if reason == "<unknown>" {
+						reason = ""
+					}
+					message := string(binding.Status.Phase)
+					if reason != "" {
+						message = fmt.Sprintf("%s - %s", message, reason)
+					}
+					if message != "" {
+						msgCallback(time.Since(start), message)
+					}
+
+					switch binding.Status.Phase {
+					case v1alpha1.KameletBindingPhaseReady:
+						return true, nil
+					case v1alpha1.KameletBindingPhaseError:
+						return true, fmt.Errorf("kamelet binding %q failed: %s", name, reason)
+					}
+				}
+			}
+		}()
+		if done {
+			if err == nil {
+				_, _ = fmt.Fprintf(out, "kamelet binding %q ready\n", name)
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,369 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	camelkv1alpha1 "github.com/apache/camel-k/pkg/client/camel/clientset/versioned/typed/camel/v1alpha1"
+	"golang.org/x/term"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientdynamic "knative.dev/client-pkg/pkg/dynamic"
+	knerrors "knative.dev/client-pkg/pkg/errors"
+)
+
+const passwordDescriptor = "urn:alm:descriptor:com.tectonic.ui:password"
+
+// addressable resources offered by the wizard in the form of "<sink type>" -> resource
+var wizardAddressables = []struct {
+	sinkType string
+	resource schema.GroupVersionResource
+}{
+	{"broker", schema.GroupVersionResource{Group: "eventing.knative.dev", Version: "v1", Resource: "brokers"}},
+	{"channel", schema.GroupVersionResource{Group: "messaging.knative.dev", Version: "v1", Resource: "channels"}},
+	{"ksvc", schema.GroupVersionResource{Group: "serving.knative.dev", Version: "v1", Resource: "services"}},
+}
+
+// bindingWizard prompts for the settings of a Kamelet binding
+type bindingWizard struct {
+	in  *bufio.Reader
+	out io.Writer
+	// terminal is set when input is read from a terminal, so password input can be hidden
+	terminal *os.File
+}
+
+func newBindingWizard(in io.Reader, out io.Writer) *bindingWizard {
+	w := &bindingWizard{
+		in:  bufio.NewReader(in),
+		out: out,
+	}
+	if isTerminal(in) {
+		w.terminal = in.(*os.File)
+	}
+	return w
+}
+
+// isTerminal returns true when the given input is an interactive terminal
+func isTerminal(in io.Reader) bool {
+	f, ok := in.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// run completes the given options by asking for the Kamelet, its properties and the binding sink or source.
+// Settings already given on the command line are not asked for.
+func (w *bindingWizard) run(ctx context.Context, client camelkv1alpha1.CamelV1alpha1Interface,
+	newDynamicClient func(namespace string) (clientdynamic.KnDynamicClient, error), namespace string, options *CreateBindingOptions) error {
+	if options.Source == "" {
+		source, err := w.selectKamelet(ctx, client, namespace)
+		if err != nil {
+			return err
+		}
+		options.Source = source
+	}
+
+	kamelet, err := client.Kamelets(namespace).Get(ctx, options.Source, v1.GetOptions{})
+	if err != nil {
+		return knerrors.GetError(err)
+	}
+
+	props, err := w.promptProperties(kamelet, options.SourceProperties)
+	if err != nil {
+		return err
+	}
+	options.SourceProperties = append(options.SourceProperties, props...)
+
+	switch {
+	case isEventSourceType(kamelet):
+		if sinkExpressionFor(options.Sink, options.Broker, options.Channel, options.Service) == "" {
+			sink, err := w.selectAddressable(ctx, newDynamicClient, namespace, "sink", "broker", "channel", "ksvc")
+			if err != nil {
+				return err
+			}
+			options.Sink = sink
+		}
+	case isEventSinkType(kamelet):
+		if options.BindingSource == "" {
+			source, err := w.selectAddressable(ctx, newDynamicClient, namespace, "source", "broker", "channel")
+			if err != nil {
+				return err
+			}
+			options.BindingSource = source
+		}
+	}
+	return nil
+}
+
+func (w *bindingWizard) selectKamelet(ctx context.Context, client camelkv1alpha1.CamelV1alpha1Interface, namespace string) (string, error) {
+	selector, err := kameletTypeSelector(kameletTypeSource)
+	if err != nil {
+		return "", err
+	}
+	kamelets, err := client.Kamelets(namespace).List(ctx, v1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return "", knerrors.GetError(err)
+	}
+	if len(kamelets.Items) == 0 {
+		return "", fmt.Errorf("no source Kamelets found in namespace %q", namespace)
+	}
+
+	choices := make([]string, 0, len(kamelets.Items))
+	_, _ = fmt.Fprintln(w.out, "Select a source Kamelet:")
+	for i, kamelet := range kamelets.Items {
+		choices = append(choices, kamelet.Name)
+		title := ""
+		if kamelet.Spec.Definition != nil {
+			title = kamelet.Spec.Definition.Title
+		}
+		_, _ = fmt.Fprintf(w.out, "  %d) %s\t%s\n", i+1, kamelet.Name, title)
+	}
+
+	for {
+		answer, err := w.prompt(fmt.Sprintf("Kamelet [1-%d]: ", len(choices)))
+		if err != nil {
+			return "", err
+		}
+		if choice, ok := selectChoice(choices, answer); ok {
+			return choice, nil
+		}
+		_, _ = fmt.Fprintf(w.out, "Please select a number between 1 and %d or a Kamelet name.\n", len(choices))
+	}
+}
+
+// promptProperties asks for all Kamelet properties not given yet, required properties first
+func (w *bindingWizard) promptProperties(kamelet *v1alpha1.Kamelet, given []string) ([]string, error) {
+	if kamelet.Spec.Definition == nil || len(kamelet.Spec.Definition.Properties) == 0 {
+		return nil, nil
+	}
+
+	givenProps, err := parseProperties(given)
+	if err != nil {
+		return nil, err
+	}
+
+	required := make(map[string]bool)
+	for _, name := range kamelet.Spec.Definition.Required {
+		required[name] = true
+	}
+	names := sortedKeys(kamelet.Spec.Definition.Properties)
+	sort.SliceStable(names, func(i, j int) bool {
+		return required[names[i]] && !required[names[j]]
+	})
+
+	var props []string
+	for _, name := range names {
+		if _, ok := givenProps[name]; ok {
+			continue
+		}
+		value, err := w.promptProperty(name, kamelet.Spec.Definition.Properties[name], required[name])
+		if err != nil {
+			return nil, err
+		}
+		if value != "" {
+			props = append(props, name+"="+value)
+		}
+	}
+	return props, nil
+}
+
+func (w *bindingWizard) promptProperty(name string, prop v1alpha1.JSONSchemaProps, required bool) (string, error) {
+	label := name
+	if required {
+		label += " (required)"
+	}
+	_, _ = fmt.Fprintln(w.out)
+	if prop.Description != "" {
+		_, _ = fmt.Fprintf(w.out, "%s: %s\n", label, prop.Description)
+	} else {
+		_, _ = fmt.Fprintln(w.out, label)
+	}
+
+	defaultValue := ""
+	if prop.Default != nil {
+		defaultValue = jsonValueString(prop.Default.RawMessage)
+		_, _ = fmt.Fprintf(w.out, "  Default: %s\n", defaultValue)
+	}
+	var choices []string
+	for _, e := range prop.Enum {
+		if e != nil {
+			choices = append(choices, jsonValueString(e.RawMessage))
+		}
+	}
+	if len(choices) > 0 {
+		_, _ = fmt.Fprintf(w.out, "  Choices: %s\n", strings.Join(choices, ", "))
+	}
+
+	for {
+		var answer string
+		var err error
+		if isPasswordProperty(prop) {
+			answer, err = w.promptSecret(name + ": ")
+		} else {
+			answer, err = w.prompt(name + ": ")
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch {
+		case answer == "" && !required:
+			// the Kamelet default applies
+			return "", nil
+		case answer == "" && defaultValue != "":
+			return defaultValue, nil
+		case answer == "":
+			_, _ = fmt.Fprintf(w.out, "Property %q is required.\n", name)
+		case len(choices) > 0:
+			if choice, ok := selectChoice(choices, answer); ok {
+				return choice, nil
+			}
+			_, _ = fmt.Fprintf(w.out, "Please choose one of: %s\n", strings.Join(choices, ", "))
+		default:
+			return answer, nil
+		}
+	}
+}
+
+// selectAddressable lets the user choose one of the existing addressable resources of the given sink types.
+// Any other answer is used as sink expression.
+func (w *bindingWizard) selectAddressable(ctx context.Context, newDynamicClient func(namespace string) (clientdynamic.KnDynamicClient, error),
+	namespace string, role string, sinkTypes ...string) (string, error) {
+	var choices []string
+	if newDynamicClient != nil {
+		client, err := newDynamicClient(namespace)
+		if err != nil {
+			return "", err
+		}
+		for _, addressable := range wizardAddressables {
+			if !contains(sinkTypes, addressable.sinkType) {
+				continue
+			}
+			list, err := client.RawClient().Resource(addressable.resource).Namespace(namespace).List(ctx, v1.ListOptions{})
+			if k8serrors.IsNotFound(err) {
+				// resource type not installed on the cluster
+				continue
+			}
+			if err != nil {
+				return "", knerrors.GetError(err)
+			}
+			for _, item := range list.Items {
+				choices = append(choices, addressable.sinkType+":"+item.GetName())
+			}
+		}
+	}
+
+	_, _ = fmt.Fprintln(w.out)
+	if len(choices) == 0 {
+		_, _ = fmt.Fprintf(w.out, "No %s found in namespace %q.\n", strings.Join(sinkTypes, ", "), namespace)
+	} else {
+		_, _ = fmt.Fprintf(w.out, "Select a binding %s:\n", role)
+		for i, choice := range choices {
+			_, _ = fmt.Fprintf(w.out, "  %d) %s\n", i+1, choice)
+		}
+	}
+
+	question := fmt.Sprintf("Binding %s expression: ", role)
+	if len(choices) > 0 {
+		question = fmt.Sprintf("Binding %s [1-%d] or %s expression: ", role, len(choices), role)
+	}
+	for {
+		answer, err := w.prompt(question)
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			continue
+		}
+		if index, err := strconv.Atoi(answer); err == nil {
+			if index < 1 || index > len(choices) {
+				_, _ = fmt.Fprintf(w.out, "Please select a number between 1 and %d.\n", len(choices))
+				continue
+			}
+			return choices[index-1], nil
+		}
+		return answer, nil
+	}
+}
+
+func (w *bindingWizard) prompt(question string) (string, error) {
+	_, _ = fmt.Fprint(w.out, question)
+	line, err := w.in.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		if errors.Is(err, io.EOF) {
+			return "", errors.New("interactive input aborted")
+		}
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func (w *bindingWizard) promptSecret(question string) (string, error) {
+	if w.terminal == nil {
+		return w.prompt(question)
+	}
+	_, _ = fmt.Fprint(w.out, question)
+	data, err := term.ReadPassword(int(w.terminal.Fd()))
+	_, _ = fmt.Fprintln(w.out)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// selectChoice returns the choice given by its value or by its position starting with 1
+func selectChoice(choices []string, answer string) (string, bool) {
+	if contains(choices, answer) {
+		return answer, true
+	}
+	if index, err := strconv.Atoi(answer); err == nil && index >= 1 && index <= len(choices) {
+		return choices[index-1], true
+	}
+	return "", false
+}
+
+func isPasswordProperty(prop v1alpha1.JSONSchemaProps) bool {
+	return prop.Format == "password" || contains(prop.XDescriptors, passwordDescriptor)
+}
+
+// jsonValueString returns the plain value of the given JSON, strings are unquoted
+func jsonValueString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright © 2021 The Knative Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package command
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/apache/camel-k/pkg/apis/camel/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	eventingv1 "knative.dev/eventing/pkg/apis/eventing/v1"
+
+	"knative.dev/kn-plugin-source-kamelet/internal/client"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBindInteractive(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	kamelet := createKameletInNamespace("k1", namespace)
+	recorder.List(&v1alpha1.KameletList{Items: []v1alpha1.Kamelet{*kamelet}}, nil)
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	broker := &eventingv1.Broker{
+		TypeMeta:   v1.TypeMeta{APIVersion: eventingv1.SchemeGroupVersion.String(), Kind: "Broker"},
+		ObjectMeta: v1.ObjectMeta{Name: "default", Namespace: namespace},
+	}
+	recorder.CreateKameletBinding(createKameletBindingInNamespace("k1-to-broker-default", "k1", namespace, &corev1.ObjectReference{
+		Kind:       "Broker",
+		APIVersion: eventingv1.SchemeGroupVersion.String(),
+		Namespace:  namespace,
+		Name:       "default",
+	}), nil)
+
+	err := runBindCmdWithInput(mockClient, []runtime.Object{broker}, "1\nfoo\n\n1\n", "--interactive", "--no-wait")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+}
+
+func TestBindInteractiveWithSourceAndSink(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	namespace := "current"
+	kamelet := createKameletInNamespace("k1", namespace)
+	recorder.Get(kamelet, nil)
+	recorder.Get(kamelet, nil)
+
+	binding := createKameletBindingInNamespace("k1-to-channel-test", "k1", namespace, channelRef(namespace))
+	binding.Spec.Source.Properties.RawMessage = []byte("{\"k1_optional\":true,\"k1_prop\":\"foo\"}")
+	recorder.CreateKameletBinding(binding, nil)
+
+	err := runBindCmdWithInput(mockClient, nil, "true\n", "k1", "--interactive", "--channel", "test", "--property", "k1_prop=foo", "--no-wait")
+	assert.NilError(t, err)
+
+	recorder.Validate()
+}
+
+func TestBindInteractiveAborted(t *testing.T) {
+	mockClient := client.NewMockClient(t)
+	recorder := mockClient.Recorder()
+
+	recorder.Get(createKameletInNamespace("k1", "current"), nil)
+
+	err := runBindCmdWithInput(mockClient, nil, "", "k1", "--interactive")
+	assert.Error(t, err, "interactive input aborted")
+
+	recorder.Validate()
+}
+
+func TestWizardPromptProperties(t *testing.T) {
+	kamelet := createKamelet("k1")
+	kamelet.Spec.Definition.Required = append(kamelet.Spec.Definition.Required, "level")
+	kamelet.Spec.Definition.Properties["level"] = v1alpha1.JSONSchemaProps{
+		Type:        "string",
+		Description: "The log level",
+		Default:     &v1alpha1.JSON{RawMessage: []byte(`"INFO"`)},
+		Enum:        []*v1alpha1.JSON{{RawMessage: []byte(`"DEBUG"`)}, {RawMessage: []byte(`"INFO"`)}},
+	}
+	kamelet.Spec.Definition.Properties["password"] = v1alpha1.JSONSchemaProps{
+		Type:         "string",
+		XDescriptors: []string{passwordDescriptor},
+	}
+
+	out := new(bytes.Buffer)
+	wizard := newBindingWizard(strings.NewReader("\nbar\n\n\nsecret\n"), out)
+	props, err := wizard.promptProperties(kamelet, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, props, []string{"k1_prop=bar", "level=INFO", "password=secret"})
+
+	output := out.String()
+	assert.Assert(t, strings.Index(output, "k1_prop (required)") < strings.Index(output, "level (required)"))
+	assert.Assert(t, strings.Index(output, "level (required)") < strings.Index(output, "k1_optional: The k1 optional property"))
+	assert.Assert(t, strings.Contains(output, "Default: INFO"))
+	assert.Assert(t, strings.Contains(output, "Choices: DEBUG, INFO"))
+	assert.Assert(t, strings.Contains(output, "Property \"k1_prop\" is required."))
+}
+
+func TestWizardPromptPropertiesEnumChoice(t *testing.T) {
+	kamelet := createKamelet("k1")
+	kamelet.Spec.Definition.Properties["level"] = v1alpha1.JSONSchemaProps{
+		Type: "string",
+		Enum: []*v1alpha1.JSON{{RawMessage: []byte(`"DEBUG"`)}, {RawMessage: []byte(`"INFO"`)}},
+	}
+
+	out := new(bytes.Buffer)
+	wizard := newBindingWizard(strings.NewReader("foo\n\nTRACE\n2\n"), out)
+	props, err := wizard.promptProperties(kamelet, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, props, []string{"k1_prop=foo", "level=INFO"})
+	assert.Assert(t, strings.Contains(out.String(), "Please choose one of: DEBUG, INFO"))
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/spf13/cobra"
+	"knative.dev/client-pkg/pkg/flags"
 	"knative.dev/kn-plugin-source-kamelet/internal/command"
 )
 
@@ -28,6 +29,10 @@ func NewSourceKameletCommand() *cobra.Command {
 		Use:   "kn-source-kamelet",
 		Short: "Knative eventing Kamelet source plugin",
 		Long:  `Plugin manages Kamelets and KameletBindings as Knative eventing sources.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// sets the value of paired "--foo" and "--no-foo" flags
+			return flags.ReconcileBoolFlags(cmd.Flags())
+		},
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -38,8 +43,12 @@ func NewSourceKameletCommand() *cobra.Command {
 	}
 	p.Initialize()
 
+	rootCmd.PersistentFlags().StringVar(&p.APIVersion, "api-version", "",
+		"Camel K API version, one of v1alpha1 (Kamelet and KameletBinding) or v1 (Kamelet and Pipe). Detected from the cluster by default.")
+
 	rootCmd.AddCommand(command.NewListCommand(p))
 	rootCmd.AddCommand(command.NewDescribeCommand(p))
+	rootCmd.AddCommand(command.NewUsageCommand(p))
 	rootCmd.AddCommand(command.NewBindCommand(p))
 	rootCmd.AddCommand(command.NewBindingCommand(p))
 	rootCmd.AddCommand(command.NewVersionCommand())